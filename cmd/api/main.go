@@ -2,15 +2,28 @@
 // @version 2.1.25
 // @description Returns PVPC electricity prices for a given range
 // @BasePath /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description An HS256 signed JWT given as "Bearer <token>". The subject is the user id.
 package main
 
 import (
 	"context"
 	_ "electricity-prices/docs"
 	"electricity-prices/pkg/alexa"
+	"electricity-prices/pkg/auth"
+	"electricity-prices/pkg/bill"
+	"electricity-prices/pkg/consumption"
 	"electricity-prices/pkg/db"
+	"electricity-prices/pkg/demand"
+	"electricity-prices/pkg/entsoe"
+	"electricity-prices/pkg/forecast"
+	"electricity-prices/pkg/generation"
 	"electricity-prices/pkg/i18n"
 	"electricity-prices/pkg/price"
+	"electricity-prices/pkg/profile"
+	"electricity-prices/pkg/tariff"
 	"golang.org/x/text/language"
 	"log"
 	"os"
@@ -84,6 +97,41 @@ func main() {
 		colName = "prices"
 	}
 
+	consumptionColName := os.Getenv("MONGODB_CONSUMPTION_COLLECTION")
+	if consumptionColName == "" {
+		consumptionColName = "consumption"
+	}
+
+	forecastColName := os.Getenv("MONGODB_FORECAST_COLLECTION")
+	if forecastColName == "" {
+		forecastColName = "forecasts"
+	}
+
+	profileColName := os.Getenv("MONGODB_PROFILE_COLLECTION")
+	if profileColName == "" {
+		profileColName = "profiles"
+	}
+
+	generationColName := os.Getenv("MONGODB_GENERATION_COLLECTION")
+	if generationColName == "" {
+		generationColName = "generation"
+	}
+
+	demandColName := os.Getenv("MONGODB_DEMAND_COLLECTION")
+	if demandColName == "" {
+		demandColName = "demand"
+	}
+
+	// The secret used to verify the tokens that identify users
+	authSecret := os.Getenv("AUTH_TOKEN_SECRET")
+
+	// Load the rates used to calculate bills and add taxes to prices
+	rates, err := bill.LoadRates(os.Getenv("BILL_RATES_FILE"))
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to load bill rates: ", err)
+	}
+
 	// Configure services
 	col, err := db.GetCollection(ctx, dbName, colName)
 	if err != nil {
@@ -92,10 +140,99 @@ func main() {
 	}
 	priceCollection := price.ColReceiver{Col: col}
 	priceService := price.Receiver{Collection: priceCollection}
-	priceHandler := price.Handler{PriceService: &priceService}
-	alexaService := alexa.Service{PriceService: &priceService}
+	priceService.Rating, err = price.ParseRatingConfig(os.Getenv("RATING_METHOD"), os.Getenv("RATING_THRESHOLD"), os.Getenv("RATING_BASELINE_DAYS"))
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to parse rating configuration: ", err)
+	}
+	if windows := os.Getenv("RANK_WINDOWS"); windows != "" {
+		priceService.RankWindows, err = price.ParseRankWindows(windows)
+		if err != nil {
+			cancel()
+			log.Fatal("Failed to parse rank windows: ", err)
+		}
+	}
+	priceService.Periods, err = price.ParsePeriodStrategy(os.Getenv("PERIOD_STRATEGY"))
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to parse period strategy: ", err)
+	}
+	surplusService := price.Receiver{Collection: price.ColReceiver{Col: col, Series: price.Surplus}}
+	omieSpainService := price.Receiver{Collection: price.ColReceiver{Col: col, Series: price.OmieSpain}}
+	omiePortugalService := price.Receiver{Collection: price.ColReceiver{Col: col, Series: price.OmiePortugal}}
+	priceHandler := price.Handler{
+		PriceService: &priceService,
+		Taxes:        rates,
+		SeriesServices: map[price.Series]price.Service{
+			price.Surplus:      &surplusService,
+			price.OmieSpain:    &omieSpainService,
+			price.OmiePortugal: &omiePortugalService,
+		},
+	}
+	for _, series := range []price.Series{price.IntradaySpain, price.IntradayPortugal, price.ContinuousSpain, price.ContinuousPortugal} {
+		marketService := price.Receiver{Collection: price.ColReceiver{Col: col, Series: series}}
+		priceHandler.SeriesServices[series] = &marketService
+	}
+	for session := 1; session <= price.IntradaySessions; session++ {
+		for _, series := range []price.Series{price.IntradaySpain, price.IntradayPortugal} {
+			sessionService := price.Receiver{Collection: price.ColReceiver{Col: col, Series: series, Session: session}}
+			priceHandler.SeriesServices[series.WithSession(session)] = &sessionService
+		}
+	}
+	if zone := os.Getenv("ENTSOE_ZONE"); zone != "" {
+		entsoeService := price.Receiver{Collection: price.ColReceiver{Col: col, Series: entsoe.SeriesFor(zone)}}
+		priceHandler.SeriesServices[entsoe.SeriesFor(zone)] = &entsoeService
+	}
+	demandCol, err := db.GetCollection(ctx, dbName, demandColName)
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to get demand collection: ", err)
+	}
+	demandService := demand.Receiver{Collection: demand.ColReceiver{Col: demandCol}}
+	demandHandler := demand.Handler{DemandService: &demandService}
+	forecastCol, err := db.GetCollection(ctx, dbName, forecastColName)
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to get forecast collection: ", err)
+	}
+	forecastService := forecast.Receiver{PriceService: &priceService, DemandService: &demandService, Collection: forecast.ColReceiver{Col: forecastCol}}
+	forecastHandler := forecast.Handler{ForecastService: &forecastService, Taxes: rates}
+	profileCol, err := db.GetCollection(ctx, dbName, profileColName)
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to get profile collection: ", err)
+	}
+	profileService := profile.Receiver{PriceService: &priceService, Collection: profile.ColReceiver{Col: profileCol}}
+	priceService.Shapes = &profileService
+	profileHandler := profile.Handler{ProfileService: &profileService, Taxes: rates}
+	generationCol, err := db.GetCollection(ctx, dbName, generationColName)
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to get generation collection: ", err)
+	}
+	generationService := generation.Receiver{Collection: generation.ColReceiver{Col: generationCol}, PriceService: &priceService}
+	generationHandler := generation.Handler{GenerationService: &generationService, Taxes: rates}
+	alexaService := alexa.Service{PriceService: &priceService, ForecastService: &forecastService, ProfileService: &profileService}
+	alexaService.PeriodStrategy, err = price.ParsePeriodStrategy(os.Getenv("ALEXA_PERIOD_STRATEGY"))
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to parse Alexa period strategy: ", err)
+	}
 	alexaHandler := alexa.Handler{AlexaService: alexaService}
 
+	billService := bill.Receiver{PriceService: &priceService, SurplusService: &surplusService, Rates: rates}
+	billHandler := bill.Handler{BillService: &billService}
+
+	consumptionCol, err := db.GetCollection(ctx, dbName, consumptionColName)
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to get consumption collection: ", err)
+	}
+	consumptionService := consumption.Receiver{Collection: consumption.ColReceiver{Col: consumptionCol}}
+	consumptionHandler := consumption.Handler{ConsumptionService: &consumptionService}
+	tariffService := tariff.Receiver{PriceService: &priceService, ConsumptionService: &consumptionService, Rates: rates}
+	tariffHandler := tariff.Handler{TariffService: &tariffService}
+
 	// Set up the API routes.
 	router := gin.Default()
 
@@ -111,7 +248,7 @@ func main() {
 	config := cors.Config{
 		AllowOrigins:  strings.Split(origins, ","),
 		AllowMethods:  []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:  []string{"Origin", "Content-Length", "Content-Type"},
+		AllowHeaders:  []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
 		ExposeHeaders: []string{"Content-Length"},
 	}
 	router.Use(cors.New(config))
@@ -125,9 +262,34 @@ func main() {
 	router.GET("/api/v1/price", priceHandler.GetPrices)
 	router.GET("/api/v1/price/averages", priceHandler.GetThirtyDayAverages)
 	router.GET("/api/v1/price/dailyinfo", priceHandler.GetDailyInfo)
+	router.GET("/api/v1/price/periods", priceHandler.GetUpcomingPeriods)
+	router.GET("/api/v1/price/stats", priceHandler.GetStats)
+	router.GET("/api/v1/price/heatmap", priceHandler.GetHeatmap)
+	router.GET("/api/v1/price/anomalies", priceHandler.GetAnomalies)
+	router.GET("/api/v1/price/calendar.ics", priceHandler.GetCalendar)
+	router.POST("/api/v1/bill", billHandler.CalculateBill)
+	router.GET("/api/v1/forecast", forecastHandler.GetForecast)
+	router.GET("/api/v1/forecast/accuracy", forecastHandler.GetAccuracy)
+	router.GET("/api/v1/profile/similar", profileHandler.GetSimilarDays)
+	router.GET("/api/v1/profile/clusters", profileHandler.GetClusters)
+	router.GET("/api/v1/profile/labels", profileHandler.GetLabels)
+	router.GET("/api/v1/generation", generationHandler.GetGridHours)
+	router.GET("/api/v1/generation/greenest", generationHandler.GetGreenestWindow)
+	router.GET("/api/v1/demand", demandHandler.GetDemand)
 	router.GET("/api/v1/alexa", alexaHandler.GetFullFeed)
 	router.POST("/api/v1/alexa-skill", alexaHandler.ProcessSkillRequest)
 
+	// The consumption is stored per user so it is only served to callers with a verified token
+	if authSecret != "" {
+		requireUser := auth.RequireUser([]byte(authSecret))
+		router.GET("/api/v1/consumption", requireUser, consumptionHandler.GetConsumption)
+		router.POST("/api/v1/consumption", requireUser, consumptionHandler.UploadConsumption)
+		router.POST("/api/v1/tariff/compare", auth.IdentifyUser([]byte(authSecret)), tariffHandler.Compare)
+	} else {
+		log.Println("AUTH_TOKEN_SECRET is not set, the consumption routes are disabled")
+		router.POST("/api/v1/tariff/compare", tariffHandler.Compare)
+	}
+
 	// Use the generated docs in the docs package.
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/swagger/doc.json")))
 
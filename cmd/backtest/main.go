@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"electricity-prices/pkg/backtest"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/db"
+	"electricity-prices/pkg/price"
+	"encoding/json"
+	"flag"
+	"github.com/joho/godotenv"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+func init() {
+	// Load .env file if it exists
+	_ = godotenv.Load()
+}
+
+func main() {
+	// Parse the flags
+	end := time.Now().AddDate(0, 0, -1)
+	startFlag := flag.String("start", end.AddDate(0, 0, -364).Format("2006-01-02"), "First day to replay in format yyyy-MM-dd")
+	endFlag := flag.String("end", end.Format("2006-01-02"), "Last day to replay in format yyyy-MM-dd")
+	ratings := flag.String("ratings", "absolute,percentage,percentile,weekday", "Comma separated rating methods to compare")
+	periods := flag.String("periods", "variance,quantile,kmeans,smoothed", "Comma separated period strategies to compare")
+	kwh := flag.Float64("kwh", 1, "Flexible consumption per day moved into the cheap periods, in kWh")
+	format := flag.String("format", "csv", "Output format, csv or json")
+	out := flag.String("out", "", "File to write the report to, defaults to stdout")
+	flag.Parse()
+
+	start, err := date.ParseDate(*startFlag)
+	if err != nil {
+		log.Fatal("Failed to parse start date: ", err)
+	}
+	last, err := date.ParseDate(*endFlag)
+	if err != nil {
+		log.Fatal("Failed to parse end date: ", err)
+	}
+	if last.Before(start) {
+		log.Fatal("The end date must not be before the start date")
+	}
+	if *format != "csv" && *format != "json" {
+		log.Fatal("Unknown format: ", *format)
+	}
+	candidates, err := backtest.ParseCandidates(*ratings, *periods)
+	if err != nil {
+		log.Fatal("Failed to parse candidates: ", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		_ = db.CloseMongoConnection(context.Background())
+	}()
+
+	// Get the db name and collection name
+	dbName := os.Getenv("MONGODB_DB")
+	if dbName == "" {
+		dbName = "electricity-prices"
+	}
+
+	colName := os.Getenv("MONGODB_COLLECTION")
+	if colName == "" {
+		colName = "prices"
+	}
+
+	// Configure services
+	col, err := db.GetCollection(ctx, dbName, colName)
+	if err != nil {
+		log.Fatal("Failed to get collection: ", err)
+	}
+	// Percentile ranks aren't part of the backtest, so don't fetch their history
+	priceService := price.Receiver{Collection: price.ColReceiver{Col: col}, RankWindows: []price.RankWindow{}}
+	backtester := backtest.Backtester{PriceService: &priceService, KwhPerDay: *kwh}
+
+	report, err := backtester.Run(ctx, start, last, candidates)
+	if err != nil {
+		log.Fatal("Failed to run the backtest: ", err)
+	}
+
+	// Write the report
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal("Failed to create output file: ", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = backtest.WriteCSV(w, report)
+	}
+	if err != nil {
+		log.Fatal("Failed to write the report: ", err)
+	}
+}
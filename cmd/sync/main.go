@@ -3,8 +3,14 @@ package main
 import (
 	"context"
 	"electricity-prices/pkg/db"
+	"electricity-prices/pkg/demand"
+	"electricity-prices/pkg/entsoe"
 	"electricity-prices/pkg/esios"
+	"electricity-prices/pkg/forecast"
+	"electricity-prices/pkg/generation"
+	"electricity-prices/pkg/omie"
 	"electricity-prices/pkg/price"
+	"electricity-prices/pkg/profile"
 	"electricity-prices/pkg/ree"
 	"electricity-prices/pkg/sync"
 	"github.com/joho/godotenv"
@@ -56,6 +62,26 @@ func main() {
 		colName = "prices"
 	}
 
+	forecastColName := os.Getenv("MONGODB_FORECAST_COLLECTION")
+	if forecastColName == "" {
+		forecastColName = "forecasts"
+	}
+
+	profileColName := os.Getenv("MONGODB_PROFILE_COLLECTION")
+	if profileColName == "" {
+		profileColName = "profiles"
+	}
+
+	generationColName := os.Getenv("MONGODB_GENERATION_COLLECTION")
+	if generationColName == "" {
+		generationColName = "generation"
+	}
+
+	demandColName := os.Getenv("MONGODB_DEMAND_COLLECTION")
+	if demandColName == "" {
+		demandColName = "demand"
+	}
+
 	// Configure services
 	col, err := db.GetCollection(ctx, dbName, colName)
 	if err != nil {
@@ -70,7 +96,7 @@ func main() {
 	esiosClient := esios.Client{
 		Http: &http.Client{Timeout: time.Second * 30},
 	}
-	syncService := sync.Syncer{PriceService: &priceService, PrimaryClient: &reeClient, SecondaryClient: &esiosClient}
+	syncService := sync.Syncer{PriceService: &priceService, PrimaryClient: &reeClient, SecondaryClient: &esiosClient, ComponentsClient: &esiosClient, AnomalyDetector: &price.AnomalyDetector{}}
 
 	// Sync with the API.
 	synced, err := syncService.Sync(ctx, time.Now().AddDate(0, 0, 1))
@@ -84,6 +110,158 @@ func main() {
 		cancel()
 		log.Fatal("Failed to sync fully...")
 	}
+
+	// Sync the demand up to tomorrow, whose forecast the regression-demand model uses
+	demandCol, err := db.GetCollection(ctx, dbName, demandColName)
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to get demand collection: ", err)
+	}
+	demandService := demand.Receiver{Collection: demand.ColReceiver{Col: demandCol}}
+	demandClient := ree.DemandClient{
+		Http: &http.Client{Timeout: time.Second * 30},
+	}
+	demandSync := sync.DemandSyncer{DemandService: &demandService, Client: &demandClient}
+	demandHours, err := demandSync.Sync(ctx, time.Now().AddDate(0, 0, 1))
+	if err != nil {
+		log.Println("Failed to sync demand: ", err)
+	} else {
+		log.Printf("Synced %d hours of demand", demandHours)
+	}
+
+	// Record the forecasts for tomorrow so their accuracy can be tracked once the prices are published
+	forecastCol, err := db.GetCollection(ctx, dbName, forecastColName)
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to get forecast collection: ", err)
+	}
+	forecastService := forecast.Receiver{PriceService: &priceService, DemandService: &demandService, Collection: forecast.ColReceiver{Col: forecastCol}}
+	forecasts, err := forecastService.RecordForecasts(ctx, time.Now().AddDate(0, 0, 1))
+	if err != nil {
+		log.Println("Failed to record forecasts: ", err)
+	} else {
+		log.Printf("Recorded %d forecasts", len(forecasts))
+	}
+
+	// Label the shape of every day since the prices were first synced, including tomorrow once it is published.
+	// The whole history is clustered together so the labels of all days stay comparable.
+	profileCol, err := db.GetCollection(ctx, dbName, profileColName)
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to get profile collection: ", err)
+	}
+	profileService := profile.Receiver{PriceService: &priceService, Collection: profile.ColReceiver{Col: profileCol}}
+	labels, err := profileService.RecordLabels(ctx, sync.HistoryStart, time.Now().AddDate(0, 0, 1))
+	if err != nil {
+		log.Println("Failed to record day labels: ", err)
+	} else {
+		log.Printf("Recorded %d day labels", len(labels))
+	}
+
+	// Sync the OMIE day-ahead marginal prices for Spain and Portugal
+	omieSeries := []struct {
+		series price.Series
+		zone   omie.Zone
+	}{{price.OmieSpain, omie.Spain}, {price.OmiePortugal, omie.Portugal}}
+	for _, o := range omieSeries {
+		series, zone := o.series, o.zone
+		omieService := price.Receiver{Collection: price.ColReceiver{Col: col, Series: series}}
+		omieClient := omie.Client{
+			Http: &http.Client{Timeout: time.Second * 30},
+			Zone: zone,
+		}
+		omieSync := sync.Syncer{PriceService: &omieService, PrimaryClient: &omieClient, AnomalyDetector: &price.AnomalyDetector{}}
+		synced, err = omieSync.Sync(ctx, time.Now().AddDate(0, 0, 1))
+		if err != nil {
+			log.Printf("Failed to sync %s prices with API: %s", series, err)
+			continue
+		}
+		if !synced {
+			log.Printf("Failed to sync %s prices fully...", series)
+			continue
+		}
+		log.Printf("Synced %s prices successfully", series)
+	}
+
+	// Sync the OMIE intraday auction sessions and continuous market prices, which fill in through the day
+	marketSeries := []struct {
+		series price.Series
+		client price.Client
+	}{
+		{price.IntradaySpain, &omie.IntradayClient{Http: &http.Client{Timeout: time.Second * 30}, Zone: omie.Spain}},
+		{price.IntradayPortugal, &omie.IntradayClient{Http: &http.Client{Timeout: time.Second * 30}, Zone: omie.Portugal}},
+		{price.ContinuousSpain, &omie.ContinuousClient{Http: &http.Client{Timeout: time.Second * 30}, Zone: omie.Spain}},
+		{price.ContinuousPortugal, &omie.ContinuousClient{Http: &http.Client{Timeout: time.Second * 30}, Zone: omie.Portugal}},
+	}
+	for _, m := range marketSeries {
+		marketService := price.Receiver{Collection: price.ColReceiver{Col: col, Series: m.series}}
+		marketSync := sync.MarketSyncer{PriceService: &marketService, Client: m.client}
+		saved, err := marketSync.Sync(ctx, time.Now().AddDate(0, 0, 1))
+		if err != nil {
+			log.Printf("Failed to sync %s prices: %s", m.series, err)
+		} else {
+			log.Printf("Synced %d %s prices", saved, m.series)
+		}
+	}
+
+	// Sync the ENTSO-E day-ahead prices for the configured bidding zone
+	entsoeZone := os.Getenv("ENTSOE_ZONE")
+	if eic, ok := entsoe.ParseZone(entsoeZone); entsoeZone != "" && !ok {
+		log.Println("Unknown ENTSO-E bidding zone: ", entsoeZone)
+	} else if entsoeZone != "" {
+		entsoeService := price.Receiver{Collection: price.ColReceiver{Col: col, Series: entsoe.SeriesFor(entsoeZone)}}
+		entsoeClient := entsoe.Client{
+			Http:  &http.Client{Timeout: time.Second * 30},
+			Token: os.Getenv("ENTSOE_API_TOKEN"),
+			Zone:  eic,
+		}
+		entsoeSync := sync.Syncer{PriceService: &entsoeService, PrimaryClient: &entsoeClient, AnomalyDetector: &price.AnomalyDetector{}, Location: entsoe.MarketLocation(eic)}
+		synced, err = entsoeSync.Sync(ctx, time.Now().AddDate(0, 0, 1))
+		if err != nil {
+			log.Println("Failed to sync ENTSO-E prices with API: ", err)
+		} else if !synced {
+			log.Println("Failed to sync ENTSO-E prices fully...")
+		} else {
+			log.Println("Synced ENTSO-E prices successfully")
+		}
+	}
+
+	// Sync the surplus compensation prices if there is an ESIOS token
+	token := os.Getenv("ESIOS_API_TOKEN")
+	if token != "" {
+		surplusService := price.Receiver{Collection: price.ColReceiver{Col: col, Series: price.Surplus}}
+		surplusClient := esios.SurplusClient{
+			Http:  &http.Client{Timeout: time.Second * 30},
+			Token: token,
+		}
+		surplusSync := sync.Syncer{PriceService: &surplusService, PrimaryClient: &surplusClient}
+		synced, err = surplusSync.Sync(ctx, time.Now().AddDate(0, 0, 1))
+		if err != nil {
+			log.Println("Failed to sync surplus prices with API: ", err)
+		} else if !synced {
+			log.Println("Failed to sync surplus prices fully...")
+		} else {
+			log.Println("Synced surplus prices successfully")
+		}
+	}
+
+	// Sync the generation mix and CO2 emissions, which are published through the day
+	generationCol, err := db.GetCollection(ctx, dbName, generationColName)
+	if err != nil {
+		cancel()
+		log.Fatal("Failed to get generation collection: ", err)
+	}
+	generationService := generation.Receiver{Collection: generation.ColReceiver{Col: generationCol}, PriceService: &priceService}
+	generationClient := ree.GenerationClient{
+		Http: &http.Client{Timeout: time.Second * 30},
+	}
+	generationSync := sync.GenerationSyncer{GenerationService: &generationService, Client: &generationClient}
+	hours, err := generationSync.Sync(ctx, time.Now())
+	if err != nil {
+		log.Println("Failed to sync generation: ", err)
+	} else {
+		log.Printf("Synced %d hours of generation", hours)
+	}
 	cancel()
 
 	// Wait for the cancellation of the context (due to signal handling)
@@ -31,6 +31,12 @@ const docTemplate = `{
                         "description": "Language in format es or en",
                         "name": "lang",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cheap and expensive period detection, variance, quantile, kmeans or smoothed. Defaults to the deployment's strategy",
+                        "name": "periodStrategy",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -90,21 +96,78 @@ const docTemplate = `{
                 }
             }
         },
-        "/price": {
+        "/bill": {
+            "post": {
+                "description": "Returns an itemised bill for the billing period using the stored PVPC prices and the hourly consumption provided. Dates should be given in the form yyyy-MM-dd and are inclusive.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bill"
+                ],
+                "operationId": "calculate-bill",
+                "parameters": [
+                    {
+                        "description": "Billing period, contracted power (kW) and hourly consumption (kWh)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bill.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bill.Bill"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/consumption": {
             "get": {
-                "description": "Returns price info for the date provided. If no date is provided it defaults to today. The day should be given in a string form yyyy-MM-dd",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the stored hourly consumption for the user identified by the bearer token between the start and end dates (inclusive). Defaults to the last 30 days. The dates should be given in a string form yyyy-MM-dd",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Price"
+                    "Consumption"
                 ],
-                "operationId": "get-prices",
+                "operationId": "get-consumption",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Date in format yyyy-MM-dd",
-                        "name": "date",
+                        "description": "Start date in format yyyy-MM-dd",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in format yyyy-MM-dd",
+                        "name": "end",
                         "in": "query"
                     }
                 ],
@@ -114,7 +177,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/price.Price"
+                                "$ref": "#/definitions/consumption.Consumption"
                             }
                         }
                     },
@@ -124,6 +187,65 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Imports an hourly consumption CSV as exported by Datadis or the distributors for the user identified by the bearer token. The file can be sent as the request body or as a multipart form field named file. Existing readings for the same hours are replaced.",
+                "consumes": [
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consumption"
+                ],
+                "operationId": "upload-consumption",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Consumption CSV",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/consumption.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -133,21 +255,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/price/averages": {
+        "/demand": {
             "get": {
-                "description": "Returns daily averages for the date provided and the previous 30 days.",
+                "description": "Returns the real, forecast and scheduled peninsular demand in MW for each hour between the start and end dates (inclusive). Defaults to today and tomorrow.\nThe real demand is only set for the hours that have passed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Price"
+                    "Demand"
                 ],
-                "operationId": "get-daily-averages",
+                "operationId": "get-demand",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Date in format yyyy-MM-dd",
-                        "name": "date",
+                        "description": "Start date in format yyyy-MM-dd",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in format yyyy-MM-dd",
+                        "name": "end",
                         "in": "query"
                     }
                 ],
@@ -157,7 +285,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/price.DailyAverage"
+                                "$ref": "#/definitions/demand.Demand"
                             }
                         }
                     },
@@ -176,29 +304,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/price/dailyinfo": {
+        "/forecast": {
             "get": {
-                "description": "Returns daily info for the date provided.",
+                "description": "Returns a forecast of the hourly prices for the date, made from the prices before it. Defaults to tomorrow so it can be used before the official prices are published around 20:15.\nThe prices are estimates and are labelled with forecast true. The lower and upper bounds hold 80% of the model's errors on the previous four weeks.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Price"
+                    "Forecast"
                 ],
-                "operationId": "get-daily-info",
+                "operationId": "get-forecast",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Date in format yyyy-MM-dd",
                         "name": "date",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Forecasting model, regression (default), regression-demand, same-weekday or seasonal-naive",
+                        "name": "model",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/price.DailyPriceInfo"
+                            "$ref": "#/definitions/forecast.Forecast"
                         }
                     },
                     "400": {
@@ -207,6 +359,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -215,105 +373,2346 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "alexa.AlexaResponse": {
-            "type": "object",
-            "properties": {
-                "mainText": {
-                    "type": "string"
-                },
-                "redirectionUrl": {
-                    "type": "string"
-                },
-                "titleText": {
-                    "type": "string"
-                },
-                "uid": {
-                    "type": "string"
-                },
-                "updateDate": {
-                    "type": "string"
-                }
-            }
-        },
-        "api.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string"
-                }
-            }
         },
-        "price.DailyAverage": {
-            "type": "object",
-            "properties": {
-                "average": {
-                    "type": "number"
-                },
-                "date": {
-                    "type": "string"
-                }
-            }
-        },
-        "price.DailyPriceInfo": {
-            "type": "object",
-            "properties": {
-                "cheapestPeriods": {
-                    "type": "array",
-                    "items": {
-                        "type": "array",
-                        "items": {
-                            "$ref": "#/definitions/price.Price"
-                        }
+        "/forecast/accuracy": {
+            "get": {
+                "description": "Returns how close each model's recorded forecasts were to the prices once they were published, for the days between the start and end dates (inclusive). Defaults to the last 30 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Forecast"
+                ],
+                "operationId": "get-forecast-accuracy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date in format yyyy-MM-dd",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in format yyyy-MM-dd",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT",
+                        "name": "taxes",
+                        "in": "query"
                     }
-                },
-                "dayAverage": {
-                    "type": "number"
-                },
-                "dayRating": {
-                    "$ref": "#/definitions/price.DayRating"
-                },
-                "expensivePeriods": {
-                    "type": "array",
-                    "items": {
-                        "type": "array",
-                        "items": {
-                            "$ref": "#/definitions/price.Price"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/forecast.AccuracyReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
-                },
-                "prices": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/price.Price"
-                    }
-                },
-                "thirtyDayAverage": {
-                    "type": "number"
                 }
             }
         },
-        "price.DayRating": {
-            "type": "string",
-            "enum": [
-                ""
-            ],
-            "x-enum-varnames": [
-                "Nil"
-            ]
-        },
-        "price.Price": {
-            "type": "object",
-            "properties": {
-                "dateTime": {
-                    "type": "string"
-                },
+        "/generation": {
+            "get": {
+                "description": "Returns the renewable share and carbon intensity of the peninsular generation for each hour of the date, alongside the price. Defaults to today.\nThe generation is published during the day, so today only has the hours so far. The carbon intensity is in grams of CO2 equivalent per kWh.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Generation"
+                ],
+                "operationId": "get-generation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date in format yyyy-MM-dd",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/generation.GridHour"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/generation/greenest": {
+            "get": {
+                "description": "Returns the consecutive hours of the date with the lowest average carbon intensity, for running appliances when emissions are lowest rather than when prices are. Defaults to the 3 hours of today.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Generation"
+                ],
+                "operationId": "get-greenest-window",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date in format yyyy-MM-dd",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Length of the window in hours, between 1 and 24",
+                        "name": "hours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/generation.Window"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/price": {
+            "get": {
+                "description": "Returns price info for the date provided. If no date is provided it defaults to today. The day should be given in a string form yyyy-MM-dd",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Price"
+                ],
+                "operationId": "get-prices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date in format yyyy-MM-dd",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured",
+                        "name": "series",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Intraday auction session, 1 to 3. Defaults to every session",
+                        "name": "session",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT, only for the pvpc day-ahead prices",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the breakdown of each price into its components",
+                        "name": "components",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format, json (default), csv or xlsx. Defaults to the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale of the CSV decimal separator, such as es. Defaults to the Accept-Language header",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/price.Price"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/price/anomalies": {
+            "get": {
+                "description": "Returns the anomalies flagged when the prices were synced between the start and end dates (inclusive). If no dates are provided it defaults to the last 30 days.\nSPIKE and DIP hours are far from the rolling 30-day distribution, NON_POSITIVE hours have a zero or negative price and DAY_JUMP marks the first hour of a day whose average moved sharply from the day before.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Price"
+                ],
+                "operationId": "get-price-anomalies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date in format yyyy-MM-dd",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in format yyyy-MM-dd",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return anomalies of this type, SPIKE, DIP, NON_POSITIVE or DAY_JUMP",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured",
+                        "name": "series",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Intraday auction session, 1 to 3. Required for the intraday market",
+                        "name": "session",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT, only for the pvpc day-ahead prices",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/price.Anomaly"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/price/averages": {
+            "get": {
+                "description": "Returns daily averages for the date provided and the previous 30 days.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Price"
+                ],
+                "operationId": "get-daily-averages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date in format yyyy-MM-dd",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured",
+                        "name": "series",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Intraday auction session, 1 to 3. Required for the intraday market",
+                        "name": "session",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT, only for the pvpc day-ahead prices",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format, json (default), csv or xlsx. Defaults to the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale of the CSV decimal separator, such as es. Defaults to the Accept-Language header",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/price.DailyAverage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/price/calendar.ics": {
+            "get": {
+                "description": "Returns an iCalendar feed with an event for each cheap and expensive period of today and tomorrow, to subscribe to from a calendar app. Each event's summary includes the average price of the period in cents/kWh.\nThe periods are detected over today's and tomorrow's prices together with the variance heuristic, so they continue past midnight once tomorrow's prices are published.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Price"
+                ],
+                "operationId": "get-price-calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language in format es or en",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bidding zone, es (default) for the PVPC, pt for the OMIE price of Portugal or an ENTSO-E zone such as fr when configured",
+                        "name": "zone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period type, cheap or expensive. Defaults to both",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/price/dailyinfo": {
+            "get": {
+                "description": "Returns daily info for the date provided, including percentile ranks of the day and its hours against previous periods. A rank of 15 means cheaper than 85% of the period.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Price"
+                ],
+                "operationId": "get-daily-info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date in format yyyy-MM-dd",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured",
+                        "name": "series",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Intraday auction session, 1 to 3. Required for the intraday market",
+                        "name": "session",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT, only for the pvpc day-ahead prices",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the breakdown of the prices into their components",
+                        "name": "components",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day rating method, absolute (default), percentage, percentile or weekday",
+                        "name": "ratingMethod",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Rating threshold, in €/kWh for absolute and weekday, a fraction of the baseline for percentage or the good percentile for percentile",
+                        "name": "ratingThreshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days in the rating baseline, defaults to 30",
+                        "name": "ratingBaselineDays",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cheap and expensive period detection, variance (default), quantile, kmeans or smoothed",
+                        "name": "periodStrategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the intermediate values of the variance heuristic and the thresholds each hour was compared against",
+                        "name": "explain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format, json (default), csv or xlsx. Defaults to the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale of the CSV decimal separator, such as es. Defaults to the Accept-Language header",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/price.DailyPriceInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/price/heatmap": {
+            "get": {
+                "description": "Returns the mean and median price for each weekday (Monday first) and local hour between the start and end dates (inclusive). If no dates are provided it defaults to the last 365 days.\nThe days included can be filtered by month, season or day type. Holidays are weekends and national holidays.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Price"
+                ],
+                "operationId": "get-price-heatmap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date in format yyyy-MM-dd",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in format yyyy-MM-dd",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Month to include, 1 to 12",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Season to include, winter, spring, summer or autumn",
+                        "name": "season",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day type to include, working or holiday",
+                        "name": "dayType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format, json (default), csv or xlsx. Defaults to the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale of the CSV decimal separator, such as es. Defaults to the Accept-Language header",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured",
+                        "name": "series",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Intraday auction session, 1 to 3. Required for the intraday market",
+                        "name": "session",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT, only for the pvpc day-ahead prices",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/price.Heatmap"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/price/periods": {
+            "get": {
+                "description": "Returns the cheap and expensive periods from the current hour until the last known price. Once tomorrow's prices are published the periods continue past midnight.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Price"
+                ],
+                "operationId": "get-upcoming-periods",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured",
+                        "name": "series",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Intraday auction session, 1 to 3. Required for the intraday market",
+                        "name": "session",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT, only for the pvpc day-ahead prices",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the breakdown of the prices into their components",
+                        "name": "components",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cheap and expensive period detection, variance (default), quantile, kmeans or smoothed",
+                        "name": "periodStrategy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/price.UpcomingPeriods"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/price/stats": {
+            "get": {
+                "description": "Returns descriptive statistics of the hourly prices between the start and end dates (inclusive). If no dates are provided it defaults to the last 30 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Price"
+                ],
+                "operationId": "get-price-stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date in format yyyy-MM-dd",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in format yyyy-MM-dd",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured",
+                        "name": "series",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Intraday auction session, 1 to 3. Required for the intraday market",
+                        "name": "session",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT, only for the pvpc day-ahead prices",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/price.Stats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/clusters": {
+            "get": {
+                "description": "Groups the days between the start and end dates (inclusive) by the shape of their prices, such as a solar duck curve or an evening peak. Defaults to the last 365 days.\nEach cluster has the number of days in it, its centroid as a normalised shape and the average price of its days at each hour.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "operationId": "get-clusters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date in format yyyy-MM-dd",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in format yyyy-MM-dd",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT",
+                        "name": "taxes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/profile.ClusterReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/labels": {
+            "get": {
+                "description": "Returns the shape cluster of each day between the start and end dates (inclusive), as labelled by the sync job. Defaults to the last 30 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "operationId": "get-labels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date in format yyyy-MM-dd",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in format yyyy-MM-dd",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT",
+                        "name": "taxes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/profile.DayLabel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/similar": {
+            "get": {
+                "description": "Returns the historical days whose hourly price shape is most similar to the date, closest first. Defaults to today.\nThe prices of each day are normalised to a mean of zero and a standard deviation of one before comparing, so days with different price levels but the same shape match.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "operationId": "get-similar-days",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date in format yyyy-MM-dd",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Distance metric, euclidean (default) or dtw",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only compare with days in the same season",
+                        "name": "sameSeason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only compare with days that are also working days, or also weekends and holidays",
+                        "name": "sameDayType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days of history to search, defaults to 365",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days to return, defaults to 5",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price unit, eur-kwh (default), cent-kwh or eur-mwh",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the electricity tax and VAT",
+                        "name": "taxes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/profile.SimilarDays"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tariff/compare": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Calculates the cost of a consumption profile under PVPC and each of the offers over a historical range, using the stored prices. The profile is either an estimated profile spread from an annual consumption over a typical residential shape (not the REE initial profiles) or, without one, the consumption uploaded by the user identified by the bearer token. Returns the options ranked from cheapest to most expensive with a month-by-month breakdown. Dates should be given in the form yyyy-MM-dd and are inclusive.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tariff"
+                ],
+                "operationId": "compare-tariffs",
+                "parameters": [
+                    {
+                        "description": "Range, consumption profile and offers to compare",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tariff.ComparisonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tariff.Comparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "alexa.AlexaResponse": {
+            "type": "object",
+            "properties": {
+                "mainText": {
+                    "type": "string"
+                },
+                "redirectionUrl": {
+                    "type": "string"
+                },
+                "titleText": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                },
+                "updateDate": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "bill.Bill": {
+            "type": "object",
+            "properties": {
+                "consumptionKwh": {
+                    "type": "number"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "exportKwh": {
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bill.Item"
+                    }
+                },
+                "start": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "bill.Concept": {
+            "type": "string",
+            "enum": [
+                "ENERGY",
+                "SURPLUS_COMPENSATION",
+                "POWER_P1",
+                "POWER_P2",
+                "ELECTRICITY_TAX",
+                "METER_RENTAL",
+                "VAT"
+            ],
+            "x-enum-varnames": [
+                "Energy",
+                "Compensation",
+                "PowerP1",
+                "PowerP2",
+                "ElectricityTax",
+                "MeterRental",
+                "Vat"
+            ]
+        },
+        "bill.Consumption": {
+            "type": "object",
+            "properties": {
+                "dateTime": {
+                    "type": "string"
+                },
+                "kwh": {
+                    "type": "number"
+                }
+            }
+        },
+        "bill.Item": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "concept": {
+                    "$ref": "#/definitions/bill.Concept"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "bill.Request": {
+            "type": "object",
+            "properties": {
+                "consumption": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bill.Consumption"
+                    }
+                },
+                "end": {
+                    "type": "string"
+                },
+                "export": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bill.Consumption"
+                    }
+                },
+                "powerP1": {
+                    "type": "number"
+                },
+                "powerP2": {
+                    "type": "number"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "consumption.Consumption": {
+            "type": "object",
+            "properties": {
+                "dateTime": {
+                    "type": "string"
+                },
+                "kwh": {
+                    "type": "number"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "consumption.ImportResult": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "demand.Demand": {
+            "type": "object",
+            "properties": {
+                "dateTime": {
+                    "type": "string"
+                },
+                "forecast": {
+                    "type": "number"
+                },
+                "real": {
+                    "type": "number"
+                },
+                "scheduled": {
+                    "type": "number"
+                }
+            }
+        },
+        "forecast.Accuracy": {
+            "type": "object",
+            "properties": {
+                "bias": {
+                    "type": "number"
+                },
+                "coverage": {
+                    "type": "number"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "hours": {
+                    "type": "integer"
+                },
+                "mae": {
+                    "type": "number"
+                },
+                "model": {
+                    "$ref": "#/definitions/forecast.Model"
+                },
+                "rmse": {
+                    "type": "number"
+                }
+            }
+        },
+        "forecast.AccuracyReport": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "models": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/forecast.Accuracy"
+                    }
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "forecast.Forecast": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "dayAverage": {
+                    "type": "number"
+                },
+                "dayLower": {
+                    "type": "number"
+                },
+                "dayUpper": {
+                    "type": "number"
+                },
+                "forecast": {
+                    "type": "boolean"
+                },
+                "model": {
+                    "$ref": "#/definitions/forecast.Model"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/forecast.HourForecast"
+                    }
+                }
+            }
+        },
+        "forecast.HourForecast": {
+            "type": "object",
+            "properties": {
+                "dateTime": {
+                    "type": "string"
+                },
+                "lower": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "upper": {
+                    "type": "number"
+                }
+            }
+        },
+        "forecast.Model": {
+            "type": "string",
+            "enum": [
+                "seasonal-naive",
+                "same-weekday",
+                "regression",
+                "regression-demand"
+            ],
+            "x-enum-varnames": [
+                "SeasonalNaive",
+                "SameWeekday",
+                "Regression",
+                "RegressionDemand"
+            ]
+        },
+        "generation.GridHour": {
+            "type": "object",
+            "properties": {
+                "carbonIntensity": {
+                    "type": "number"
+                },
+                "dateTime": {
+                    "type": "string"
+                },
+                "generation": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "renewableShare": {
+                    "type": "number"
+                }
+            }
+        },
+        "generation.Window": {
+            "type": "object",
+            "properties": {
+                "carbonIntensity": {
+                    "type": "number"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "hours": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "renewableShare": {
+                    "type": "number"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "price.Anomaly": {
+            "type": "object",
+            "properties": {
+                "baseline": {
+                    "type": "number"
+                },
+                "dateTime": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "type": {
+                    "$ref": "#/definitions/price.AnomalyType"
+                }
+            }
+        },
+        "price.AnomalyType": {
+            "type": "string",
+            "enum": [
+                "SPIKE",
+                "DIP",
+                "NON_POSITIVE",
+                "DAY_JUMP"
+            ],
+            "x-enum-varnames": [
+                "Spike",
+                "Dip",
+                "NonPositive",
+                "DayJump"
+            ]
+        },
+        "price.Components": {
+            "type": "object",
+            "properties": {
+                "adjustment": {
+                    "description": "Adjustment services",
+                    "type": "number"
+                },
+                "capacityPayments": {
+                    "description": "Capacity payments",
+                    "type": "number"
+                },
+                "energy": {
+                    "description": "Day-ahead and intraday market price",
+                    "type": "number"
+                },
+                "interruptibility": {
+                    "description": "Interruptibility service",
+                    "type": "number"
+                },
+                "operatorFees": {
+                    "description": "Market and system operator funding",
+                    "type": "number"
+                },
+                "other": {
+                    "description": "Other regulated adjustments",
+                    "type": "number"
+                },
+                "tollsAndCharges": {
+                    "description": "Access tolls and charges",
+                    "type": "number"
+                },
+                "tradingCosts": {
+                    "description": "Retailer trading costs",
+                    "type": "number"
+                }
+            }
+        },
+        "price.DailyAverage": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "shape": {
+                    "type": "string"
+                }
+            }
+        },
+        "price.DailyPriceInfo": {
+            "type": "object",
+            "properties": {
+                "anomalies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/price.Anomaly"
+                    }
+                },
+                "cheapestPeriods": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/price.Price"
+                        }
+                    }
+                },
+                "components": {
+                    "$ref": "#/definitions/price.Components"
+                },
+                "dayAverage": {
+                    "type": "number"
+                },
+                "dayRating": {
+                    "$ref": "#/definitions/price.DayRating"
+                },
+                "expensivePeriods": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/price.Price"
+                        }
+                    }
+                },
+                "explanation": {
+                    "$ref": "#/definitions/price.Explanation"
+                },
+                "percentileRanks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/price.PercentileRank"
+                    }
+                },
+                "periodStrategy": {
+                    "type": "string"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/price.Price"
+                    }
+                },
+                "rating": {
+                    "$ref": "#/definitions/price.RatingDetails"
+                },
+                "shape": {
+                    "type": "string"
+                },
+                "thirtyDayAverage": {
+                    "type": "number"
+                }
+            }
+        },
+        "price.DayRating": {
+            "type": "string",
+            "enum": [
+                ""
+            ],
+            "x-enum-varnames": [
+                "Nil"
+            ]
+        },
+        "price.Explanation": {
+            "type": "object",
+            "properties": {
+                "centroids": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "cheapThreshold": {
+                    "type": "number"
+                },
+                "cheapVariance": {
+                    "type": "number"
+                },
+                "combinedAverage": {
+                    "type": "number"
+                },
+                "dayAverage": {
+                    "type": "number"
+                },
+                "expensiveCutOff": {
+                    "type": "number"
+                },
+                "expensiveThreshold": {
+                    "type": "number"
+                },
+                "expensiveVariance": {
+                    "type": "number"
+                },
+                "hours": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/price.HourExplanation"
+                    }
+                },
+                "maxPrice": {
+                    "type": "number"
+                },
+                "maxVariance": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer"
+                },
+                "minPrice": {
+                    "type": "number"
+                },
+                "minVariance": {
+                    "type": "number"
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "thirtyDayAverage": {
+                    "type": "number"
+                }
+            }
+        },
+        "price.Heatmap": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "end": {
+                    "type": "string"
+                },
+                "mean": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "median": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "start": {
+                    "type": "string"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "price.HourExplanation": {
+            "type": "object",
+            "properties": {
+                "cheap": {
+                    "type": "boolean"
+                },
+                "cheapThreshold": {
+                    "type": "number"
+                },
+                "dateTime": {
+                    "type": "string"
+                },
+                "expensive": {
+                    "type": "boolean"
+                },
+                "expensiveThreshold": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "price.PercentileRank": {
+            "type": "object",
+            "properties": {
+                "dayAverage": {
+                    "type": "number"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "hours": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "window": {
+                    "type": "string"
+                }
+            }
+        },
+        "price.Percentiles": {
+            "type": "object",
+            "properties": {
+                "p10": {
+                    "type": "number"
+                },
+                "p25": {
+                    "type": "number"
+                },
+                "p5": {
+                    "type": "number"
+                },
+                "p75": {
+                    "type": "number"
+                },
+                "p90": {
+                    "type": "number"
+                },
+                "p95": {
+                    "type": "number"
+                }
+            }
+        },
+        "price.Price": {
+            "type": "object",
+            "properties": {
+                "anomalies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/price.Anomaly"
+                    }
+                },
+                "components": {
+                    "$ref": "#/definitions/price.Components"
+                },
+                "dateTime": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "session": {
+                    "type": "integer"
+                }
+            }
+        },
+        "price.PriceAtTime": {
+            "type": "object",
+            "properties": {
+                "dateTime": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "price.RatingDetails": {
+            "type": "object",
+            "properties": {
+                "badAbove": {
+                    "type": "number"
+                },
+                "baseline": {
+                    "type": "number"
+                },
+                "baselineDays": {
+                    "type": "integer"
+                },
+                "goodBelow": {
+                    "type": "number"
+                },
+                "method": {
+                    "$ref": "#/definitions/price.RatingMethod"
+                },
+                "threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "price.RatingMethod": {
+            "type": "string",
+            "enum": [
+                "absolute",
+                "percentage",
+                "percentile",
+                "weekday"
+            ],
+            "x-enum-varnames": [
+                "AbsoluteBand",
+                "PercentageBand",
+                "PercentileBand",
+                "SameWeekday"
+            ]
+        },
+        "price.Season": {
+            "type": "string",
+            "enum": [
+                "winter",
+                "spring",
+                "summer",
+                "autumn"
+            ],
+            "x-enum-varnames": [
+                "Winter",
+                "Spring",
+                "Summer",
+                "Autumn"
+            ]
+        },
+        "price.Stats": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "max": {
+                    "$ref": "#/definitions/price.PriceAtTime"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "median": {
+                    "type": "number"
+                },
+                "min": {
+                    "$ref": "#/definitions/price.PriceAtTime"
+                },
+                "nonPositiveHours": {
+                    "type": "integer"
+                },
+                "percentiles": {
+                    "$ref": "#/definitions/price.Percentiles"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "stdDev": {
+                    "type": "number"
+                }
+            }
+        },
+        "price.UpcomingPeriods": {
+            "type": "object",
+            "properties": {
+                "cheapestPeriods": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/price.Price"
+                        }
+                    }
+                },
+                "end": {
+                    "type": "string"
+                },
+                "expensivePeriods": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/price.Price"
+                        }
+                    }
+                },
+                "periodStrategy": {
+                    "type": "string"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/price.Price"
+                    }
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "profile.Cluster": {
+            "type": "object",
+            "properties": {
+                "centroid": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "shape": {
+                    "$ref": "#/definitions/profile.Shape"
+                }
+            }
+        },
+        "profile.ClusterReport": {
+            "type": "object",
+            "properties": {
+                "clusters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/profile.Cluster"
+                    }
+                },
+                "end": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "profile.DayLabel": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "distance": {
+                    "type": "number"
+                },
+                "shape": {
+                    "$ref": "#/definitions/profile.Shape"
+                }
+            }
+        },
+        "profile.Match": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "distance": {
+                    "type": "number"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "season": {
+                    "$ref": "#/definitions/price.Season"
+                },
+                "workingDay": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "profile.Metric": {
+            "type": "string",
+            "enum": [
+                "euclidean",
+                "dtw"
+            ],
+            "x-enum-varnames": [
+                "Euclidean",
+                "DTW"
+            ]
+        },
+        "profile.Shape": {
+            "type": "string",
+            "enum": [
+                "FLAT",
+                "SOLAR_DUCK_CURVE",
+                "EVENING_PEAK",
+                "DOUBLE_PEAK"
+            ],
+            "x-enum-varnames": [
+                "Flat",
+                "DuckCurve",
+                "EveningPeak",
+                "DoublePeak"
+            ]
+        },
+        "profile.SimilarDays": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/profile.Match"
+                    }
+                },
+                "metric": {
+                    "$ref": "#/definitions/profile.Metric"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "season": {
+                    "$ref": "#/definitions/price.Season"
+                },
+                "workingDay": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "tariff.Comparison": {
+            "type": "object",
+            "properties": {
+                "consumptionKwh": {
+                    "type": "number"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tariff.Option"
+                    }
+                },
+                "profile": {
+                    "$ref": "#/definitions/tariff.ProfileSource"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "tariff.ComparisonRequest": {
+            "type": "object",
+            "properties": {
+                "annualKwh": {
+                    "type": "number"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "offers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tariff.Offer"
+                    }
+                },
+                "powerP1": {
+                    "type": "number"
+                },
+                "powerP2": {
+                    "type": "number"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "tariff.MonthlyCost": {
+            "type": "object",
+            "properties": {
+                "consumptionKwh": {
+                    "type": "number"
+                },
+                "energy": {
+                    "type": "number"
+                },
+                "month": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "tariff.Offer": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "offPeakPrice": {
+                    "type": "number"
+                },
+                "peakPrice": {
+                    "type": "number"
+                },
+                "powerP1": {
+                    "type": "number"
+                },
+                "powerP2": {
+                    "type": "number"
+                },
                 "price": {
                     "type": "number"
+                },
+                "type": {
+                    "$ref": "#/definitions/tariff.OfferType"
+                }
+            }
+        },
+        "tariff.OfferType": {
+            "type": "string",
+            "enum": [
+                "PVPC",
+                "FIXED",
+                "TWO_PERIOD"
+            ],
+            "x-enum-varnames": [
+                "Pvpc",
+                "Fixed",
+                "TwoPeriod"
+            ]
+        },
+        "tariff.Option": {
+            "type": "object",
+            "properties": {
+                "differenceFromCheapest": {
+                    "type": "number"
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tariff.MonthlyCost"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "type": {
+                    "$ref": "#/definitions/tariff.OfferType"
                 }
             }
+        },
+        "tariff.ProfileSource": {
+            "type": "string",
+            "enum": [
+                "USER",
+                "ESTIMATED"
+            ],
+            "x-enum-varnames": [
+                "UserProfile",
+                "EstimatedProfile"
+            ]
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "An HS256 signed JWT given as \"Bearer \u003ctoken\u003e\". The subject is the user id.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -3,6 +3,7 @@ package alexa
 import (
 	"electricity-prices/pkg/api"
 	"electricity-prices/pkg/i18n"
+	"electricity-prices/pkg/price"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"io"
@@ -20,6 +21,7 @@ type Handler struct {
 // @ID get-full-feed
 // @Produce  json
 // @Param lang query string false "Language in format es or en"
+// @Param periodStrategy query string false "Cheap and expensive period detection, variance, quantile, kmeans or smoothed. Defaults to the deployment's strategy"
 // @Success 200 {object} alexa.AlexaResponse
 // @Failure 400 {object} api.ErrorResponse
 // @Failure 500 {object} api.ErrorResponse
@@ -28,12 +30,23 @@ func (s *Handler) GetFullFeed(c *gin.Context) {
 	// Parse language from request
 	lang := i18n.ParseLanguage(c.DefaultQuery("lang", "es"))
 
-	title := s.AlexaService.GetTitle(lang)
+	// Parse the period detection strategy, overriding the service's when provided
+	service := s.AlexaService
+	periods, err := price.ParsePeriodStrategy(c.Query("periodStrategy"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if periods != nil {
+		service.PeriodStrategy = periods
+	}
+
+	title := service.GetTitle(lang)
 
 	// Get the context from the request
 	ctx := c.Request.Context()
 
-	feed, err := s.AlexaService.GetFullFeed(ctx, time.Now(), lang)
+	feed, err := service.GetFullFeed(ctx, time.Now(), lang)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
 		return
@@ -3,7 +3,9 @@ package alexa
 import (
 	"context"
 	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/forecast"
 	"electricity-prices/pkg/price"
+	"electricity-prices/pkg/profile"
 	"fmt"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -11,8 +13,15 @@ import (
 	"time"
 )
 
+// Service answers the flash briefing and the skill from the prices.
+// PeriodStrategy chooses how the cheap and expensive periods are detected, nil uses the price service's default.
+// ForecastService is optional and gives an estimate for tomorrow before the prices are published.
+// ProfileService is optional and describes the shape of today's prices.
 type Service struct {
-	PriceService price.Service
+	PriceService    price.Service
+	PeriodStrategy  price.PeriodStrategy
+	ForecastService forecast.Service
+	ProfileService  profile.Service
 }
 
 func (s *Service) GetTitle(lang language.Tag) string {
@@ -22,7 +31,7 @@ func (s *Service) GetTitle(lang language.Tag) string {
 
 func (s *Service) GetFullFeed(ctx context.Context, t time.Time, lang language.Tag) (string, error) {
 	// Get the daily info for the given date
-	dailyInfo, err := s.PriceService.GetDailyInfo(ctx, t)
+	dailyInfo, err := s.PriceService.GetDailyInfo(ctx, t, s.options())
 
 	if err != nil {
 		return "", err
@@ -35,6 +44,11 @@ func (s *Service) GetFullFeed(ctx context.Context, t time.Time, lang language.Ta
 	// Parse day rating message
 	messages = append(messages, s.getTodayRatingMessage(dailyInfo.DayRating, dailyInfo.DayAverage, lang))
 
+	// Describe the shape of today's prices
+	if shape := s.getTodayShapeMessage(ctx, t, lang); shape != "" {
+		messages = append(messages, shape)
+	}
+
 	// Get current price
 	messages = append(messages, s.getPriceMessage(dailyInfo.Prices, t, lang))
 
@@ -45,7 +59,7 @@ func (s *Service) GetFullFeed(ctx context.Context, t time.Time, lang language.Ta
 	messages = append(messages, s.getNextExpensivePeriodMessage(dailyInfo.ExpensivePeriods, t, lang))
 
 	// Get tomorrow's data
-	tomorrowInfo, err := s.PriceService.GetDailyInfo(ctx, t.AddDate(0, 0, 1))
+	tomorrowInfo, err := s.PriceService.GetDailyInfo(ctx, t.AddDate(0, 0, 1), s.options())
 	if err == nil && len(tomorrowInfo.Prices) > 0 {
 		messages = append(messages, s.getTomorrowRatingMessage(tomorrowInfo.DayRating, tomorrowInfo.DayAverage, lang))
 	}
@@ -90,23 +104,23 @@ func (s *Service) ProcessAlexaSkillRequest(ctx context.Context, intent AlexaInte
 		rating, err := s.PriceService.GetDayRating(ctx, tomorrow)
 		avg, err2 := s.PriceService.GetDayAverage(ctx, tomorrow)
 		if err != nil || err2 != nil {
-			msg = s.getTomorrowNoDataMessage(lang)
+			msg = s.getTomorrowForecastMessage(ctx, tomorrow, lang)
 		} else {
 			msg = s.getTomorrowRatingMessage(rating, avg, lang)
 		}
 	case "NEXT_CHEAP":
-		cheapPeriods, err := s.PriceService.GetCheapPeriods(ctx, t)
+		upcoming, err := s.PriceService.GetUpcomingPeriods(ctx, t, s.options())
 		if err != nil {
 			msg = s.getUnknownError(lang)
 		} else {
-			msg = s.getNextCheapPeriodMessage(cheapPeriods, t, lang)
+			msg = s.getNextCheapPeriodMessage(upcoming.CheapPeriods, t, lang)
 		}
 	case "NEXT_EXPENSIVE":
-		expensivePeriods, err := s.PriceService.GetExpensivePeriods(ctx, t)
+		upcoming, err := s.PriceService.GetUpcomingPeriods(ctx, t, s.options())
 		if err != nil {
 			msg = s.getUnknownError(lang)
 		} else {
-			msg = s.getNextExpensivePeriodMessage(expensivePeriods, t, lang)
+			msg = s.getNextExpensivePeriodMessage(upcoming.ExpensivePeriods, t, lang)
 		}
 	case "CURRENT_PRICE":
 		pr, err := s.PriceService.GetPrice(ctx, t)
@@ -130,6 +144,12 @@ func (s *Service) ProcessAlexaSkillRequest(ctx context.Context, intent AlexaInte
 	return WrapAlexaSkillResponse(msg, endSess)
 }
 
+// options
+// Get the price options for the service's period strategy.
+func (s *Service) options() price.Options {
+	return price.Options{Periods: s.PeriodStrategy}
+}
+
 func (s *Service) getUnknownError(lang language.Tag) string {
 	p := message.NewPrinter(lang)
 	errMesg := p.Sprintf("alexa_unknown_error")
@@ -150,6 +170,39 @@ func (s *Service) getTomorrowNoDataMessage(lang language.Tag) string {
 	return noData
 }
 
+// getTomorrowForecastMessage
+// Get the no data message for tomorrow, followed by the forecast when there is one.
+func (s *Service) getTomorrowForecastMessage(ctx context.Context, tomorrow time.Time, lang language.Tag) string {
+	noData := s.getTomorrowNoDataMessage(lang)
+	if s.ForecastService == nil {
+		return noData
+	}
+
+	f, err := s.ForecastService.GetForecast(ctx, tomorrow, forecast.Regression)
+	if err != nil {
+		return noData
+	}
+
+	p := message.NewPrinter(lang)
+	return noData + " " + p.Sprintf("alexa_tomorrow_forecast", price.FormatPrice(f.DayAverage), price.FormatPrice(f.DayLower), price.FormatPrice(f.DayUpper))
+}
+
+// getTodayShapeMessage
+// Get the description of the shape of today's prices, or an empty message when the day has not been labelled.
+func (s *Service) getTodayShapeMessage(ctx context.Context, t time.Time, lang language.Tag) string {
+	if s.ProfileService == nil {
+		return ""
+	}
+
+	label, err := s.ProfileService.GetLabel(ctx, t)
+	if err != nil || label.Shape == "" {
+		return ""
+	}
+
+	p := message.NewPrinter(lang)
+	return p.Sprintf(fmt.Sprintf("alexa_shape_%s", strings.ToLower(string(label.Shape))))
+}
+
 func (s *Service) getTodayRatingMessage(dayRating price.DayRating, dayAverage float64, lang language.Tag) string {
 	p := message.NewPrinter(lang)
 
@@ -208,6 +261,8 @@ func (s *Service) getNextCheapPeriodMessage(periods [][]price.Price, t time.Time
 
 	if started {
 		return p.Sprintf("alexa_current_cheap_period", start, avg, end)
+	} else if date.ParseToLocalDay(next[0].DateTime) != date.ParseToLocalDay(t) {
+		return p.Sprintf("alexa_next_cheap_period_tomorrow", start, avg, end)
 	} else {
 		return p.Sprintf("alexa_next_cheap_period", start, avg, end)
 	}
@@ -235,6 +290,8 @@ func (s *Service) getNextExpensivePeriodMessage(periods [][]price.Price, t time.
 
 	if started {
 		return p.Sprintf("alexa_current_expensive_period", start, avg, end)
+	} else if date.ParseToLocalDay(next[0].DateTime) != date.ParseToLocalDay(t) {
+		return p.Sprintf("alexa_next_expensive_period_tomorrow", start, avg, end)
 	} else {
 		return p.Sprintf("alexa_next_expensive_period", start, avg, end)
 	}
@@ -2,8 +2,10 @@ package alexa
 
 import (
 	"context"
+	"electricity-prices/pkg/forecast"
 	"electricity-prices/pkg/i18n"
 	"electricity-prices/pkg/price"
+	"electricity-prices/pkg/profile"
 	"errors"
 	"golang.org/x/text/language"
 	"log"
@@ -188,6 +190,59 @@ func TestGetTomorrowNoDataMessage(t *testing.T) {
 	}
 }
 
+func TestGetTodayShapeMessage(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		name     string
+		label    *profile.DayLabel
+		labelErr error
+		lang     language.Tag
+		expected string
+	}{
+		{
+			name:     "Duck curve (English)",
+			label:    &profile.DayLabel{Shape: profile.DuckCurve},
+			lang:     language.English,
+			expected: "Prices are lowest in the middle of the day, when there is most solar power, and rise into the evening.",
+		},
+		{
+			name:     "Double peak (Spanish)",
+			label:    &profile.DayLabel{Shape: profile.DoublePeak},
+			lang:     language.Spanish,
+			expected: "Los precios tienen un pico por la mañana y otro por la noche.",
+		},
+		{
+			name:     "Not labelled",
+			label:    &profile.DayLabel{},
+			labelErr: profile.ErrLabelNotFound,
+			lang:     language.English,
+			expected: "",
+		},
+		{
+			name:     "No profile service",
+			lang:     language.English,
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &Service{}
+			if tc.label != nil {
+				service.ProfileService = &profile.MockProfileService{
+					MockGetLabelResult: &[]profile.DayLabel{*tc.label},
+					MockGetLabelError:  &[]error{tc.labelErr},
+				}
+			}
+
+			actual := service.getTodayShapeMessage(ctx, time.Date(2023, 1, 1, 12, 0, 0, 0, madridLocation), tc.lang)
+			if actual != tc.expected {
+				t.Errorf("Expected '%s', but got '%s'", tc.expected, actual)
+			}
+		})
+	}
+}
+
 func TestGetTodayRatingMessage(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -980,9 +1035,10 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 		mockThirtyDayAvg    float64
 		mockThirtyDayErr    error
 		mockGetCheapPeriods [][]price.Price
-		mockGetCheapError   error
 		mockGetExpensive    [][]price.Price
-		mockGetExpensiveErr error
+		mockUpcomingErr     error
+		mockForecast        *forecast.Forecast
+		mockForecastErr     error
 		mockGetPrice        price.Price
 		mockGetPriceErr     error
 		expectMessage       string
@@ -1286,6 +1342,46 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 			expectMessage:       "Mañana es un día malo, con un precio promedio de 20 céntimos.",
 			expectEnd:           false,
 		},
+		{
+			name: "TOMORROW - forecast (English)",
+			lang: language.English,
+			t:    time.Date(2023, 1, 1, 3, 0, 0, 0, madridLocation),
+			intent: AlexaIntent{
+				Name: "TOMORROW",
+			},
+			mockDayRatingError:  errors.New("error"),
+			mockDayAverageError: errors.New("error"),
+			mockForecast:        &forecast.Forecast{DayAverage: 0.12, DayLower: 0.09, DayUpper: 0.15},
+			expectMessage:       "There is no data available yet for tomorrow. Please check back later. Prices are generally available by 8:30 PM. The forecast is an average price of around 12 cents, likely between 9 and 15 cents.",
+			expectEnd:           false,
+		},
+		{
+			name: "TOMORROW - forecast (Spanish)",
+			lang: language.Spanish,
+			t:    time.Date(2023, 1, 1, 3, 0, 0, 0, madridLocation),
+			intent: AlexaIntent{
+				Name: "TOMORROW",
+			},
+			mockDayRatingError:  errors.New("error"),
+			mockDayAverageError: errors.New("error"),
+			mockForecast:        &forecast.Forecast{DayAverage: 0.12, DayLower: 0.09, DayUpper: 0.15},
+			expectMessage:       "Aún no hay datos disponibles para mañana. Por favor, vuelva más tarde. Los precios están generalmente disponibles a las 8:30 PM. La previsión es un precio medio de unos 12 céntimos, probablemente entre 9 y 15 céntimos.",
+			expectEnd:           false,
+		},
+		{
+			name: "TOMORROW - forecast error (English)",
+			lang: language.English,
+			t:    time.Date(2023, 1, 1, 3, 0, 0, 0, madridLocation),
+			intent: AlexaIntent{
+				Name: "TOMORROW",
+			},
+			mockDayRatingError:  errors.New("error"),
+			mockDayAverageError: errors.New("error"),
+			mockForecast:        &forecast.Forecast{},
+			mockForecastErr:     forecast.ErrInsufficientHistory,
+			expectMessage:       "There is no data available yet for tomorrow. Please check back later. Prices are generally available by 8:30 PM.",
+			expectEnd:           false,
+		},
 		{
 			name: "TOMORROW - error (English)",
 			lang: language.English,
@@ -1324,9 +1420,9 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 			mockGetCheapPeriods: [][]price.Price{
 				pricesToday[10:11], pricesToday[13:14],
 			},
-			mockGetCheapError: nil,
-			expectMessage:     "You are currently in a cheap period that started at 10 AM with an average price of 11 cents and will end at 11 AM.",
-			expectEnd:         false,
+			mockUpcomingErr: nil,
+			expectMessage:   "You are currently in a cheap period that started at 10 AM with an average price of 11 cents and will end at 11 AM.",
+			expectEnd:       false,
 		},
 		{
 			name: "NEXT_CHEAP (Spanish)",
@@ -1338,9 +1434,9 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 			mockGetCheapPeriods: [][]price.Price{
 				pricesToday[10:11], pricesToday[12:15],
 			},
-			mockGetCheapError: nil,
-			expectMessage:     "Actualmente se encuentra en un período barato que comenzó a las 10 AM con un precio promedio de 11 céntimos y terminara a las 11 AM.",
-			expectEnd:         false,
+			mockUpcomingErr: nil,
+			expectMessage:   "Actualmente se encuentra en un período barato que comenzó a las 10 AM con un precio promedio de 11 céntimos y terminara a las 11 AM.",
+			expectEnd:       false,
 		},
 		{
 			name: "NEXT_CHEAP - error (English)",
@@ -1352,9 +1448,9 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 			mockGetCheapPeriods: [][]price.Price{
 				pricesToday[10:11], pricesToday[13:14],
 			},
-			mockGetCheapError: errors.New("error"),
-			expectMessage:     "Sorry, there was an error. Please try again later.",
-			expectEnd:         false,
+			mockUpcomingErr: errors.New("error"),
+			expectMessage:   "Sorry, there was an error. Please try again later.",
+			expectEnd:       false,
 		},
 		{
 			name: "NEXT_CHEAP - error (Spanish)",
@@ -1364,10 +1460,49 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 				Name: "NEXT_CHEAP",
 			},
 			mockGetCheapPeriods: nil,
-			mockGetCheapError:   errors.New("error"),
+			mockUpcomingErr:     errors.New("error"),
 			expectMessage:       "Lo siento, no pude obtener los datos. Por favor, inténtelo de nuevo más tarde.",
 			expectEnd:           false,
 		},
+		{
+			name: "NEXT_CHEAP - tomorrow (English)",
+			lang: language.English,
+			t:    time.Date(2023, 1, 1, 23, 30, 0, 0, madridLocation),
+			intent: AlexaIntent{
+				Name: "NEXT_CHEAP",
+			},
+			mockGetCheapPeriods: [][]price.Price{
+				pricesTomorrow[1:4],
+			},
+			expectMessage: "The next cheap period starts tomorrow at 1 AM with an average price of 4 cents and will end at 4 AM.",
+			expectEnd:     false,
+		},
+		{
+			name: "NEXT_CHEAP - tomorrow (Spanish)",
+			lang: language.Spanish,
+			t:    time.Date(2023, 1, 1, 23, 30, 0, 0, madridLocation),
+			intent: AlexaIntent{
+				Name: "NEXT_CHEAP",
+			},
+			mockGetCheapPeriods: [][]price.Price{
+				pricesTomorrow[1:4],
+			},
+			expectMessage: "El próximo período barato comienza mañana a las 1 AM con un precio medio de 4 céntimos y terminara a las 4 AM.",
+			expectEnd:     false,
+		},
+		{
+			name: "NEXT_CHEAP - across midnight (English)",
+			lang: language.English,
+			t:    time.Date(2023, 1, 1, 23, 10, 0, 0, madridLocation),
+			intent: AlexaIntent{
+				Name: "NEXT_CHEAP",
+			},
+			mockGetCheapPeriods: [][]price.Price{
+				append(append([]price.Price{}, pricesToday[22:24]...), pricesTomorrow[0:2]...),
+			},
+			expectMessage: "You are currently in a cheap period that started at 10 PM with an average price of 13 cents and will end at 2 AM.",
+			expectEnd:     false,
+		},
 		{
 			name: "NEXT_EXPENSIVE (English)",
 			lang: language.English,
@@ -1378,9 +1513,9 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 			mockGetExpensive: [][]price.Price{
 				pricesToday[2:5], pricesToday[8:9],
 			},
-			mockGetExpensiveErr: nil,
-			expectMessage:       "The expensive periods for today have already passed.",
-			expectEnd:           false,
+			mockUpcomingErr: nil,
+			expectMessage:   "The expensive periods for today have already passed.",
+			expectEnd:       false,
 		},
 		{
 			name: "NEXT_EXPENSIVE (Spanish)",
@@ -1392,9 +1527,9 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 			mockGetExpensive: [][]price.Price{
 				pricesToday[2:5], pricesToday[8:9],
 			},
-			mockGetExpensiveErr: nil,
-			expectMessage:       "Los períodos caros de hoy ya han pasado.",
-			expectEnd:           false,
+			mockUpcomingErr: nil,
+			expectMessage:   "Los períodos caros de hoy ya han pasado.",
+			expectEnd:       false,
 		},
 		{
 			name: "NEXT_EXPENSIVE - error (English)",
@@ -1406,9 +1541,9 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 			mockGetExpensive: [][]price.Price{
 				pricesToday[2:5], pricesToday[8:9],
 			},
-			mockGetExpensiveErr: errors.New("error"),
-			expectMessage:       "Sorry, there was an error. Please try again later.",
-			expectEnd:           false,
+			mockUpcomingErr: errors.New("error"),
+			expectMessage:   "Sorry, there was an error. Please try again later.",
+			expectEnd:       false,
 		},
 		{
 			name: "NEXT_EXPENSIVE - error (Spanish)",
@@ -1420,9 +1555,9 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 			mockGetExpensive: [][]price.Price{
 				pricesToday[2:5], pricesToday[8:9],
 			},
-			mockGetExpensiveErr: errors.New("error"),
-			expectMessage:       "Lo siento, no pude obtener los datos. Por favor, inténtelo de nuevo más tarde.",
-			expectEnd:           false,
+			mockUpcomingErr: errors.New("error"),
+			expectMessage:   "Lo siento, no pude obtener los datos. Por favor, inténtelo de nuevo más tarde.",
+			expectEnd:       false,
 		},
 		{
 			name: "CURRENT_PRICE (English)",
@@ -1531,10 +1666,8 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 				MockGetDayRatingError:         &[]error{tc.mockDayRatingError},
 				MockGetDayAverageResult:       &[]float64{tc.mockDayAverage},
 				MockGetDayAverageError:        &[]error{tc.mockDayAverageError},
-				MockGetCheapPeriodsResult:     &[][][]price.Price{tc.mockGetCheapPeriods},
-				MockGetCheapPeriodsError:      &[]error{tc.mockGetCheapError},
-				MockGetExpensivePeriodsResult: &[][][]price.Price{tc.mockGetExpensive},
-				MockGetExpensivePeriodsError:  &[]error{tc.mockGetExpensiveErr},
+				MockGetUpcomingPeriodsResult:  &[]price.UpcomingPeriods{{CheapPeriods: tc.mockGetCheapPeriods, ExpensivePeriods: tc.mockGetExpensive}},
+				MockGetUpcomingPeriodsError:   &[]error{tc.mockUpcomingErr},
 				MockGetPriceResult:            &[]price.Price{tc.mockGetPrice},
 				MockGetPriceError:             &[]error{tc.mockGetPriceErr},
 				MockGetThirtyDayAverageResult: &[]float64{tc.mockThirtyDayAvg},
@@ -1544,6 +1677,12 @@ func TestProcessAlexaSkillRequest(t *testing.T) {
 			service := &Service{
 				PriceService: mockPriceService,
 			}
+			if tc.mockForecast != nil {
+				service.ForecastService = &forecast.MockForecastService{
+					MockGetForecastResult: &[]forecast.Forecast{*tc.mockForecast},
+					MockGetForecastError:  &[]error{tc.mockForecastErr},
+				}
+			}
 
 			res := service.ProcessAlexaSkillRequest(ctx, tc.intent, tc.t, tc.lang)
 			if res.Response.OutputSpeech.Text != tc.expectMessage {
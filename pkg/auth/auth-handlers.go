@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"electricity-prices/pkg/api"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const userKey = "userId"
+
+// RequireUser rejects requests without a valid bearer token and stores the verified user id in the context.
+func RequireUser(secret []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, api.ErrorResponse{Message: "A bearer token is required."})
+			return
+		}
+		identify(c, secret)
+	}
+}
+
+// IdentifyUser stores the verified user id in the context when the request has a bearer token.
+// Requests without one are let through anonymously, but an invalid token is still rejected.
+func IdentifyUser(secret []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		identify(c, secret)
+	}
+}
+
+// UserID returns the user id verified by RequireUser or IdentifyUser, or an empty string if there is none.
+func UserID(c *gin.Context) string {
+	return c.GetString(userKey)
+}
+
+// identify
+// Verify the bearer token of the request and store its user id, aborting with 401 if it is not valid.
+func identify(c *gin.Context, secret []byte) {
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || token == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, api.ErrorResponse{Message: "A bearer token is required."})
+		return
+	}
+
+	userID, err := VerifyToken(token, secret, time.Now())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, api.ErrorResponse{Message: "The bearer token is not valid."})
+		return
+	}
+
+	c.Set(userKey, userID)
+	c.Next()
+}
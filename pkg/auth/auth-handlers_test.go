@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserMiddleware(t *testing.T) {
+	secret := []byte("secret")
+
+	tests := []struct {
+		name           string
+		middleware     func(secret []byte) gin.HandlerFunc
+		authorization  string
+		expectedStatus int
+		expectedUserID string
+	}{
+		{
+			name:           "required with a valid token",
+			middleware:     RequireUser,
+			authorization:  "Bearer " + newToken("HS256", `{"sub":"user-1"}`, secret),
+			expectedStatus: http.StatusOK,
+			expectedUserID: "user-1",
+		},
+		{
+			name:           "required without a token",
+			middleware:     RequireUser,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "required with an invalid token",
+			middleware:     RequireUser,
+			authorization:  "Bearer " + newToken("HS256", `{"sub":"user-1"}`, []byte("other")),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "optional with a valid token",
+			middleware:     IdentifyUser,
+			authorization:  "Bearer " + newToken("HS256", `{"sub":"user-1"}`, secret),
+			expectedStatus: http.StatusOK,
+			expectedUserID: "user-1",
+		},
+		{
+			name:           "optional without a token",
+			middleware:     IdentifyUser,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "optional with an invalid token",
+			middleware:     IdentifyUser,
+			authorization:  "Basic dXNlcjpwYXNz",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			var userID string
+			router := gin.New()
+			router.GET("/", tt.middleware(secret), func(c *gin.Context) {
+				userID = UserID(c)
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedUserID, userID)
+		})
+	}
+}
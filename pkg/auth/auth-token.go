@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type claims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// VerifyToken checks an HS256 signed JWT against the secret and returns the user id in its subject.
+// Tokens without a subject, with another algorithm or that have expired are rejected.
+func VerifyToken(token string, secret []byte, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return "", err
+	}
+	if h.Alg != "HS256" {
+		return "", fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, h.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	if !hmac.Equal(signature, sign(parts[0]+"."+parts[1], secret)) {
+		return "", fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return "", err
+	}
+	if c.Subject == "" {
+		return "", fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+	if c.ExpiresAt != 0 && !now.Before(time.Unix(c.ExpiresAt, 0)) {
+		return "", fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+
+	return c.Subject, nil
+}
+
+// decodeSegment
+// Decode a base64url encoded JSON segment of the token.
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+	return nil
+}
+
+// sign
+// Calculate the HS256 signature of the signing input.
+func sign(input string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newToken(alg string, payload string, secret []byte) string {
+	input := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"`+alg+`","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return input + "." + base64.RawURLEncoding.EncodeToString(sign(input, secret))
+}
+
+func TestVerifyToken(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedUserID string
+		expectingError bool
+	}{
+		{
+			name:           "valid token",
+			token:          newToken("HS256", `{"sub":"user-1"}`, secret),
+			expectedUserID: "user-1",
+		},
+		{
+			name:           "valid token before expiry",
+			token:          newToken("HS256", `{"sub":"user-1","exp":1715778000}`, secret),
+			expectedUserID: "user-1",
+		},
+		{
+			name:           "expired token",
+			token:          newToken("HS256", `{"sub":"user-1","exp":1715770800}`, secret),
+			expectingError: true,
+		},
+		{
+			name:           "signed with another secret",
+			token:          newToken("HS256", `{"sub":"user-1"}`, []byte("other")),
+			expectingError: true,
+		},
+		{
+			name:           "unsupported algorithm",
+			token:          newToken("none", `{"sub":"user-1"}`, secret),
+			expectingError: true,
+		},
+		{
+			name:           "missing subject",
+			token:          newToken("HS256", `{"exp":1715778000}`, secret),
+			expectingError: true,
+		},
+		{
+			name:           "malformed token",
+			token:          "not-a-token",
+			expectingError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, err := VerifyToken(tt.token, secret, now)
+			if tt.expectingError {
+				assert.True(t, errors.Is(err, ErrInvalidToken))
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedUserID, userID)
+		})
+	}
+}
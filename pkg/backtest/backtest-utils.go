@@ -0,0 +1,234 @@
+package backtest
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// dayResult is what a candidate made of a single day.
+type dayResult struct {
+	ratingMethod   price.RatingMethod
+	periodStrategy string
+	rating         price.DayRating
+	dayAverage     float64
+	cheapAverage   float64
+	cheapHours     map[int]bool
+	expensiveHours int
+	cheapPeriods   int
+}
+
+// ParseCandidates
+// Parse the comma separated rating methods and period strategies into every combination of the two.
+// An empty list uses the deployment default for that part of the candidate.
+func ParseCandidates(ratings string, strategies string) ([]Candidate, error) {
+	var candidates []Candidate
+	for _, method := range splitList(ratings) {
+		rating, err := price.ParseRatingConfig(method, "", "")
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range splitList(strategies) {
+			periods, err := price.ParsePeriodStrategy(name)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, Candidate{
+				Name:    candidateName(method, name),
+				Rating:  rating,
+				Periods: periods,
+			})
+		}
+	}
+	return candidates, nil
+}
+
+// WriteCSV
+// Write the report as CSV with one row per candidate.
+func WriteCSV(w io.Writer, report Report) error {
+	writer := csv.NewWriter(w)
+	header := []string{
+		"candidate", "ratingMethod", "periodStrategy", "start", "end", "days", "missingDays",
+		"good", "normal", "bad", "goodShare", "normalShare", "badShare",
+		"ratingStability", "cheapHourStability", "cheapHoursPerDay", "expensiveHoursPerDay", "cheapPeriodsPerDay",
+		"daysWithoutCheapPeriods", "averageSavings", "savingsPercent", "totalSavings",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, r := range report.Results {
+		err := writer.Write([]string{
+			r.Candidate,
+			r.RatingMethod,
+			r.PeriodStrategy,
+			report.Start,
+			report.End,
+			strconv.Itoa(r.Days),
+			strconv.Itoa(r.MissingDays),
+			strconv.Itoa(r.Good),
+			strconv.Itoa(r.Normal),
+			strconv.Itoa(r.Bad),
+			formatFloat(r.GoodShare),
+			formatFloat(r.NormalShare),
+			formatFloat(r.BadShare),
+			formatFloat(r.RatingStability),
+			formatFloat(r.CheapHourStability),
+			formatFloat(r.CheapHoursPerDay),
+			formatFloat(r.ExpensiveHoursPerDay),
+			formatFloat(r.CheapPeriodsPerDay),
+			strconv.Itoa(r.DaysWithoutCheapPeriods),
+			formatFloat(r.AverageSavings),
+			formatFloat(r.SavingsPercent),
+			formatFloat(r.TotalSavings),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// toDayResult
+// Get what the backtest needs from the daily info.
+func toDayResult(info price.DailyPriceInfo) dayResult {
+	day := dayResult{
+		ratingMethod:   info.Rating.Method,
+		periodStrategy: info.PeriodStrategy,
+		rating:         info.DayRating,
+		dayAverage:     info.DayAverage,
+		cheapHours:     make(map[int]bool),
+		cheapPeriods:   len(info.CheapPeriods),
+	}
+
+	var cheap []price.Price
+	for _, period := range info.CheapPeriods {
+		for _, p := range period {
+			day.cheapHours[p.DateTime.In(date.Location).Hour()] = true
+			cheap = append(cheap, p)
+		}
+	}
+	day.cheapAverage = price.CalculateAverage(cheap)
+	for _, period := range info.ExpensivePeriods {
+		day.expensiveHours += len(period)
+	}
+	return day
+}
+
+// summarise
+// Calculate the candidate's result over the replayed days.
+func summarise(candidate Candidate, days []dayResult, kwh float64) Result {
+	result := Result{Candidate: candidate.Name, Days: len(days)}
+	if len(days) == 0 {
+		return result
+	}
+	result.RatingMethod = string(days[0].ratingMethod)
+	result.PeriodStrategy = days[0].periodStrategy
+
+	var cheapHours, expensiveHours, cheapPeriods int
+	var savings, dayTotal, sameRating, overlap float64
+	for i, day := range days {
+		switch day.rating {
+		case price.Good:
+			result.Good++
+		case price.Bad:
+			result.Bad++
+		default:
+			result.Normal++
+		}
+		cheapHours += len(day.cheapHours)
+		expensiveHours += day.expensiveHours
+		cheapPeriods += day.cheapPeriods
+
+		if len(day.cheapHours) == 0 {
+			result.DaysWithoutCheapPeriods++
+		} else {
+			savings += day.dayAverage - day.cheapAverage
+		}
+		dayTotal += day.dayAverage
+
+		if i > 0 {
+			if day.rating == days[i-1].rating {
+				sameRating++
+			}
+			overlap += calculateOverlap(days[i-1].cheapHours, day.cheapHours)
+		}
+	}
+
+	n := float64(len(days))
+	result.GoodShare = float64(result.Good) / n
+	result.NormalShare = float64(result.Normal) / n
+	result.BadShare = float64(result.Bad) / n
+	result.CheapHoursPerDay = float64(cheapHours) / n
+	result.ExpensiveHoursPerDay = float64(expensiveHours) / n
+	result.CheapPeriodsPerDay = float64(cheapPeriods) / n
+	result.AverageSavings = savings * kwh / n
+	if dayTotal != 0 {
+		result.SavingsPercent = savings / dayTotal * 100
+	}
+	result.TotalSavings = savings * kwh
+	if len(days) > 1 {
+		result.RatingStability = sameRating / (n - 1)
+		result.CheapHourStability = overlap / (n - 1)
+	} else {
+		result.RatingStability = 1
+		result.CheapHourStability = 1
+	}
+
+	return result
+}
+
+// calculateOverlap
+// Calculate the intersection over union of two sets of hours. Two empty sets overlap fully.
+func calculateOverlap(a map[int]bool, b map[int]bool) float64 {
+	union := len(a)
+	var intersection int
+	for hour := range b {
+		if a[hour] {
+			intersection++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 1
+	}
+	return float64(intersection) / float64(union)
+}
+
+// candidateName
+// Name a candidate after its rating method and period strategy, using default for the empty parts.
+func candidateName(method string, strategy string) string {
+	if method == "" {
+		method = "default"
+	}
+	if strategy == "" {
+		strategy = "default"
+	}
+	return fmt.Sprintf("%s/%s", method, strategy)
+}
+
+// splitList
+// Split a comma separated list, returning a single empty value for an empty list.
+func splitList(list string) []string {
+	var values []string
+	for _, v := range strings.Split(list, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return []string{""}
+	}
+	return values
+}
+
+// formatFloat
+// Format a float for the CSV output.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 5, 64)
+}
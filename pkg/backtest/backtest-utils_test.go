@@ -0,0 +1,97 @@
+package backtest
+
+import (
+	"bytes"
+	"electricity-prices/pkg/price"
+	"strings"
+	"testing"
+)
+
+func TestParseCandidates(t *testing.T) {
+	testCases := []struct {
+		name      string
+		ratings   string
+		periods   string
+		expected  []string
+		expectErr bool
+	}{
+		{name: "Defaults", expected: []string{"default/default"}},
+		{name: "Cross product", ratings: "absolute, percentile", periods: "variance,kmeans", expected: []string{
+			"absolute/variance", "absolute/kmeans", "percentile/variance", "percentile/kmeans",
+		}},
+		{name: "Unknown rating", ratings: "median", expectErr: true},
+		{name: "Unknown strategy", periods: "median", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			candidates, err := ParseCandidates(tc.ratings, tc.periods)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("Expected an error, but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if len(candidates) != len(tc.expected) {
+				t.Fatalf("Expected %d candidates, but got %d", len(tc.expected), len(candidates))
+			}
+			for i, c := range candidates {
+				if c.Name != tc.expected[i] {
+					t.Errorf("Expected %s, but got %s", tc.expected[i], c.Name)
+				}
+			}
+		})
+	}
+
+	candidates, _ := ParseCandidates("percentage", "quantile")
+	if candidates[0].Rating.Method != price.PercentageBand || candidates[0].Periods.Name() != "quantile" {
+		t.Errorf("Expected a percentage and quantile candidate, but got %+v", candidates[0])
+	}
+}
+
+func TestCalculateOverlap(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        map[int]bool
+		b        map[int]bool
+		expected float64
+	}{
+		{"Both empty", map[int]bool{}, map[int]bool{}, 1},
+		{"One empty", map[int]bool{1: true}, map[int]bool{}, 0},
+		{"Same", map[int]bool{1: true, 2: true}, map[int]bool{1: true, 2: true}, 1},
+		{"Partial", map[int]bool{1: true, 2: true}, map[int]bool{2: true, 3: true}, 1.0 / 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := calculateOverlap(tc.a, tc.b); actual != tc.expected {
+				t.Errorf("Expected %f, but got %f", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := Report{Start: "2024-03-01", End: "2024-03-31", Results: []Result{
+		{Candidate: "absolute/variance", RatingMethod: "absolute", PeriodStrategy: "variance", Days: 31, Good: 10, GoodShare: 10.0 / 31},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, report); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, but got %d", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "candidate,ratingMethod,periodStrategy,start,end,days") {
+		t.Errorf("Unexpected header %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "absolute/variance,absolute,variance,2024-03-01,2024-03-31,31,0,10,0,0,0.32258,") {
+		t.Errorf("Unexpected row %s", lines[1])
+	}
+}
@@ -0,0 +1,48 @@
+package backtest
+
+import (
+	"electricity-prices/pkg/price"
+)
+
+// Candidate is a combination of a rating method and a period detection strategy to replay.
+type Candidate struct {
+	Name    string
+	Rating  price.RatingConfig
+	Periods price.PeriodStrategy
+}
+
+// Result summarises a candidate over the replayed days.
+// RatingStability is the share of consecutive days that kept the same rating and CheapHourStability is the mean
+// overlap (intersection over union) of the cheap hours of consecutive days.
+// The savings are what a user moving KwhPerDay of flexible consumption into the cheap hours would have saved
+// compared to spreading it evenly over the day, in € per day for AverageSavings and in € over the days for TotalSavings.
+type Result struct {
+	Candidate               string  `json:"candidate"`
+	RatingMethod            string  `json:"ratingMethod"`
+	PeriodStrategy          string  `json:"periodStrategy"`
+	Days                    int     `json:"days"`
+	MissingDays             int     `json:"missingDays"`
+	Good                    int     `json:"good"`
+	Normal                  int     `json:"normal"`
+	Bad                     int     `json:"bad"`
+	GoodShare               float64 `json:"goodShare"`
+	NormalShare             float64 `json:"normalShare"`
+	BadShare                float64 `json:"badShare"`
+	RatingStability         float64 `json:"ratingStability"`
+	CheapHourStability      float64 `json:"cheapHourStability"`
+	CheapHoursPerDay        float64 `json:"cheapHoursPerDay"`
+	ExpensiveHoursPerDay    float64 `json:"expensiveHoursPerDay"`
+	CheapPeriodsPerDay      float64 `json:"cheapPeriodsPerDay"`
+	DaysWithoutCheapPeriods int     `json:"daysWithoutCheapPeriods"`
+	AverageSavings          float64 `json:"averageSavings"`
+	SavingsPercent          float64 `json:"savingsPercent"`
+	TotalSavings            float64 `json:"totalSavings"`
+}
+
+// Report is the result of every candidate over the same days.
+type Report struct {
+	Start     string   `json:"start"`
+	End       string   `json:"end"`
+	KwhPerDay float64  `json:"kwhPerDay"`
+	Results   []Result `json:"results"`
+}
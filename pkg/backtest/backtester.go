@@ -0,0 +1,57 @@
+package backtest
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"time"
+)
+
+const defaultKwhPerDay = 1.0
+
+// Backtester replays the stored history day by day through the price service with each candidate.
+// KwhPerDay is the flexible consumption moved into the cheap periods when calculating the savings and defaults to 1 kWh.
+type Backtester struct {
+	PriceService price.Service
+	KwhPerDay    float64
+}
+
+// Run replays the days between start and end (inclusive) with each candidate.
+// Days without prices are counted as missing and skipped.
+func (b *Backtester) Run(ctx context.Context, start time.Time, end time.Time, candidates []Candidate) (Report, error) {
+	kwh := b.KwhPerDay
+	if kwh == 0 {
+		kwh = defaultKwhPerDay
+	}
+
+	from := date.StartOfDay(start)
+	to := date.StartOfDay(end)
+	report := Report{
+		Start:     date.ParseToLocalDay(from),
+		End:       date.ParseToLocalDay(to),
+		KwhPerDay: kwh,
+		Results:   make([]Result, 0, len(candidates)),
+	}
+
+	for _, candidate := range candidates {
+		var days []dayResult
+		var missing int
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			info, err := b.PriceService.GetDailyInfo(ctx, day, price.Options{Rating: candidate.Rating, Periods: candidate.Periods})
+			if err != nil {
+				return Report{}, err
+			}
+			if len(info.Prices) == 0 {
+				missing++
+				continue
+			}
+			days = append(days, toDayResult(info))
+		}
+
+		result := summarise(candidate, days, kwh)
+		result.MissingDays = missing
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
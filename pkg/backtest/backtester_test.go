@@ -0,0 +1,134 @@
+package backtest
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func dailyInfo(day time.Time, rating price.DayRating, cheapHours ...int) price.DailyPriceInfo {
+	info := price.DailyPriceInfo{
+		DayRating:      rating,
+		PeriodStrategy: "variance",
+		Rating:         price.RatingDetails{Method: price.AbsoluteBand},
+	}
+	for i := 0; i < 24; i++ {
+		info.Prices = append(info.Prices, price.Price{DateTime: day.Add(time.Duration(i) * time.Hour), Price: 0.2})
+	}
+	var period []price.Price
+	for _, h := range cheapHours {
+		info.Prices[h].Price = 0.08
+		period = append(period, info.Prices[h])
+	}
+	if len(period) > 0 {
+		info.CheapPeriods = [][]price.Price{period}
+	}
+	info.DayAverage = price.CalculateAverage(info.Prices)
+	return info
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, date.Location)
+
+	tests := []struct {
+		name       string
+		infos      []price.DailyPriceInfo
+		errs       []error
+		expected   Result
+		expectsErr bool
+	}{
+		{
+			name: "Three days",
+			infos: []price.DailyPriceInfo{
+				dailyInfo(day, price.Good, 2, 3),
+				dailyInfo(day.AddDate(0, 0, 1), price.Good, 3, 4),
+				dailyInfo(day.AddDate(0, 0, 2), price.Bad),
+			},
+			errs: []error{nil, nil, nil},
+			expected: Result{
+				Candidate: "absolute/variance", RatingMethod: "absolute", PeriodStrategy: "variance",
+				Days: 3, Good: 2, Bad: 1, GoodShare: 2.0 / 3, BadShare: 1.0 / 3,
+				RatingStability: 0.5, CheapHourStability: (1.0/3 + 0) / 2,
+				CheapHoursPerDay: 4.0 / 3, CheapPeriodsPerDay: 2.0 / 3, DaysWithoutCheapPeriods: 1,
+			},
+		},
+		{
+			name: "Missing day",
+			infos: []price.DailyPriceInfo{
+				dailyInfo(day, price.Normal, 2),
+				{},
+				dailyInfo(day.AddDate(0, 0, 2), price.Normal, 2),
+			},
+			errs: []error{nil, nil, nil},
+			expected: Result{
+				Candidate: "absolute/variance", RatingMethod: "absolute", PeriodStrategy: "variance",
+				Days: 2, MissingDays: 1, Normal: 2, NormalShare: 1,
+				RatingStability: 1, CheapHourStability: 1, CheapHoursPerDay: 1, CheapPeriodsPerDay: 1,
+			},
+		},
+		{
+			name:       "Error",
+			infos:      []price.DailyPriceInfo{{}},
+			errs:       []error{errors.New("error")},
+			expectsErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPriceService := &price.MockPriceService{
+				MockGetDailyInfoResult: &tt.infos,
+				MockGetDailyInfoError:  &tt.errs,
+			}
+			backtester := Backtester{PriceService: mockPriceService, KwhPerDay: 2}
+			candidates, err := ParseCandidates("absolute", "variance")
+			assert.NoError(t, err)
+
+			report, err := backtester.Run(ctx, day, day.AddDate(0, 0, 2), candidates)
+
+			if tt.expectsErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "2024-03-01", report.Start)
+			assert.Equal(t, "2024-03-03", report.End)
+			assert.Equal(t, 2.0, report.KwhPerDay)
+			assert.Len(t, report.Results, 1)
+
+			result := report.Results[0]
+			assert.Greater(t, result.AverageSavings, 0.0)
+			assert.Greater(t, result.SavingsPercent, 0.0)
+			assert.InDelta(t, result.AverageSavings*float64(result.Days), result.TotalSavings, 1e-9)
+			result.AverageSavings, result.SavingsPercent, result.TotalSavings = 0, 0, 0
+			assert.InDeltaMapValues(t, toMap(tt.expected), toMap(result), 1e-9)
+			assert.Equal(t, tt.expected.Candidate, result.Candidate)
+			assert.Equal(t, tt.expected.RatingMethod, result.RatingMethod)
+			assert.Equal(t, tt.expected.PeriodStrategy, result.PeriodStrategy)
+		})
+	}
+}
+
+func toMap(r Result) map[string]float64 {
+	return map[string]float64{
+		"days":                    float64(r.Days),
+		"missingDays":             float64(r.MissingDays),
+		"good":                    float64(r.Good),
+		"normal":                  float64(r.Normal),
+		"bad":                     float64(r.Bad),
+		"goodShare":               r.GoodShare,
+		"normalShare":             r.NormalShare,
+		"badShare":                r.BadShare,
+		"ratingStability":         r.RatingStability,
+		"cheapHourStability":      r.CheapHourStability,
+		"cheapHoursPerDay":        r.CheapHoursPerDay,
+		"expensiveHoursPerDay":    r.ExpensiveHoursPerDay,
+		"cheapPeriodsPerDay":      r.CheapPeriodsPerDay,
+		"daysWithoutCheapPeriods": float64(r.DaysWithoutCheapPeriods),
+	}
+}
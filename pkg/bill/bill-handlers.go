@@ -0,0 +1,47 @@
+package bill
+
+import (
+	"electricity-prices/pkg/api"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	BillService Service
+}
+
+// CalculateBill @Summary Calculate a bill
+// @Description Returns an itemised bill for the billing period using the stored PVPC prices and the hourly consumption provided. Dates should be given in the form yyyy-MM-dd and are inclusive.
+// @Tags Bill
+// @ID calculate-bill
+// @Accept  json
+// @Produce  json
+// @Param request body bill.Request true "Billing period, contracted power (kW) and hourly consumption (kWh)"
+// @Success 200 {object} bill.Bill
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /bill [post]
+func (h *Handler) CalculateBill(c *gin.Context) {
+	var req Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse request body."})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	b, err := h.BillService.CalculateBill(ctx, req)
+	if errors.Is(err, ErrInvalidRequest) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, b)
+}
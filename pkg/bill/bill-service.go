@@ -0,0 +1,58 @@
+package bill
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"fmt"
+	"time"
+)
+
+type Service interface {
+	CalculateBill(ctx context.Context, req Request) (Bill, error)
+}
+
+type Receiver struct {
+	PriceService   price.Service
+	SurplusService price.Service
+	Rates          RateSchedule
+}
+
+// CalculateBill returns an itemised bill for the requested billing period
+// using the stored PVPC prices and the consumption provided.
+// Any exported energy is compensated at the stored surplus prices.
+func (r *Receiver) CalculateBill(ctx context.Context, req Request) (Bill, error) {
+	start, err := date.ParseDate(req.Start)
+	if err != nil {
+		return Bill{}, fmt.Errorf("%w: failed to parse start date", ErrInvalidRequest)
+	}
+	end, err := date.ParseDate(req.End)
+	if err != nil {
+		return Bill{}, fmt.Errorf("%w: failed to parse end date", ErrInvalidRequest)
+	}
+	if end.Before(start) {
+		return Bill{}, fmt.Errorf("%w: end date is before start date", ErrInvalidRequest)
+	}
+
+	endOfDay := end.AddDate(0, 0, 1).Add(-time.Second)
+
+	// Get the prices for the whole billing period
+	prices, err := r.PriceService.GetPrices(ctx, start, endOfDay)
+	if err != nil {
+		return Bill{}, err
+	}
+
+	// Get the surplus prices if there is exported energy to compensate
+	var surplusPrices []price.Price
+	if len(req.Export) > 0 {
+		if r.SurplusService == nil {
+			return Bill{}, fmt.Errorf("%w: surplus compensation is not available", ErrInvalidRequest)
+		}
+		surplusPrices, err = r.SurplusService.GetPrices(ctx, start, endOfDay)
+		if err != nil {
+			return Bill{}, err
+		}
+	}
+
+	return CalculateBill(start, end, req, prices, surplusPrices, r.Rates)
+}
@@ -0,0 +1,102 @@
+package bill
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiverCalculateBill(t *testing.T) {
+	ctx := context.Background()
+	schedule, err := LoadRates("testdata/rates.toml")
+	if err != nil {
+		t.Fatalf("Error loading rates: %s", err)
+	}
+
+	priceExample := price.Price{
+		DateTime: time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location),
+		Price:    0.1,
+	}
+	consumption := []Consumption{{DateTime: priceExample.DateTime, Kwh: 1}}
+
+	tests := []struct {
+		name           string
+		req            Request
+		mockPrices     *[][]price.Price
+		mockPricesErr  *[]error
+		mockSurplus    *[][]price.Price
+		expectedKwh    float64
+		expectingError bool
+	}{
+		{
+			name:           "success",
+			req:            Request{Start: "2021-01-01", End: "2021-01-01", PowerP1: 5, PowerP2: 5, Consumption: consumption},
+			mockPrices:     &[][]price.Price{{priceExample}},
+			mockPricesErr:  &[]error{},
+			expectedKwh:    1,
+			expectingError: false,
+		},
+		{
+			name:           "invalid start date",
+			req:            Request{Start: "01/01/2021", End: "2021-01-01"},
+			mockPrices:     &[][]price.Price{},
+			mockPricesErr:  &[]error{},
+			expectingError: true,
+		},
+		{
+			name:           "invalid end date",
+			req:            Request{Start: "2021-01-01", End: "tomorrow"},
+			mockPrices:     &[][]price.Price{},
+			mockPricesErr:  &[]error{},
+			expectingError: true,
+		},
+		{
+			name:           "success with export",
+			req:            Request{Start: "2021-01-01", End: "2021-01-01", PowerP1: 5, PowerP2: 5, Consumption: consumption, Export: consumption},
+			mockPrices:     &[][]price.Price{{priceExample}},
+			mockPricesErr:  &[]error{},
+			mockSurplus:    &[][]price.Price{{priceExample}},
+			expectedKwh:    1,
+			expectingError: false,
+		},
+		{
+			name:           "export without surplus prices",
+			req:            Request{Start: "2021-01-01", End: "2021-01-01", PowerP1: 5, PowerP2: 5, Consumption: consumption, Export: consumption},
+			mockPrices:     &[][]price.Price{{priceExample}},
+			mockPricesErr:  &[]error{},
+			expectingError: true,
+		},
+		{
+			name:           "failure getting prices",
+			req:            Request{Start: "2021-01-01", End: "2021-01-01", Consumption: consumption},
+			mockPrices:     &[][]price.Price{},
+			mockPricesErr:  &[]error{errors.New("not found")},
+			expectingError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priceService := &price.MockPriceService{MockGetPricesResult: tt.mockPrices, MockGetPricesError: tt.mockPricesErr}
+			service := &Receiver{PriceService: priceService, Rates: schedule}
+			if tt.mockSurplus != nil {
+				service.SurplusService = &price.MockPriceService{MockGetPricesResult: tt.mockSurplus, MockGetPricesError: &[]error{}}
+			}
+
+			result, err := service.CalculateBill(ctx, tt.req)
+
+			if tt.expectingError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedKwh, result.ConsumptionKwh)
+				assert.Equal(t, 1, result.Days)
+			}
+		})
+	}
+}
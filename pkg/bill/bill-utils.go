@@ -0,0 +1,217 @@
+package bill
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var (
+	ErrInvalidRequest    = errors.New("invalid bill request")
+	ErrUnknownPowerTerms = errors.New("power terms not known")
+)
+
+type segment struct {
+	rates Rates
+	days  []time.Time
+}
+
+// CalculateBill
+// Build an itemised bill for the days between start and end (inclusive) from the hourly consumption and prices.
+// Exported energy is valued at the surplus prices and compensated against the energy term.
+// A new set of line items is created each time the rates change within the billing period.
+func CalculateBill(start time.Time, end time.Time, req Request, prices []price.Price, surplusPrices []price.Price, schedule RateSchedule) (Bill, error) {
+	if end.Before(start) {
+		return Bill{}, fmt.Errorf("%w: end date is before start date", ErrInvalidRequest)
+	}
+	if req.PowerP1 < 0 || req.PowerP2 < 0 {
+		return Bill{}, fmt.Errorf("%w: contracted power cannot be negative", ErrInvalidRequest)
+	}
+
+	segments, err := splitByRates(start, end, schedule)
+	if err != nil {
+		return Bill{}, err
+	}
+
+	// Index the prices and readings by hour and day
+	priceMap := indexPrices(prices)
+	surplusMap := indexPrices(surplusPrices)
+	consumptionByDay := groupByDay(req.Consumption)
+	exportByDay := groupByDay(req.Export)
+
+	// Every reading must fall inside the billing period
+	days, matched := 0, 0
+	for _, seg := range segments {
+		days += len(seg.days)
+		for _, d := range seg.days {
+			matched += len(consumptionByDay[date.ParseToLocalDay(d)]) + len(exportByDay[date.ParseToLocalDay(d)])
+		}
+	}
+	if matched != len(req.Consumption)+len(req.Export) {
+		return Bill{}, fmt.Errorf("%w: consumption provided outside of the billing period", ErrInvalidRequest)
+	}
+
+	bill := Bill{
+		Start: date.ParseToLocalDay(start),
+		End:   date.ParseToLocalDay(end),
+		Days:  days,
+		Items: []Item{},
+	}
+
+	for _, seg := range segments {
+		items, kwh, exportKwh, err := calculateSegment(seg, req, consumptionByDay, exportByDay, priceMap, surplusMap)
+		if err != nil {
+			return Bill{}, err
+		}
+		bill.ConsumptionKwh += kwh
+		bill.ExportKwh += exportKwh
+		bill.Items = append(bill.Items, items...)
+	}
+
+	for _, item := range bill.Items {
+		bill.Total += item.Amount
+	}
+	bill.Total = roundToCents(bill.Total)
+
+	return bill, nil
+}
+
+// splitByRates
+// Split the billing period into consecutive runs of days that share the same rates.
+func splitByRates(start time.Time, end time.Time, schedule RateSchedule) ([]segment, error) {
+	var segments []segment
+	lastDay := date.StartOfDay(end)
+	for d := date.StartOfDay(start); !d.After(lastDay); d = d.AddDate(0, 0, 1) {
+		rates, err := schedule.At(d)
+		if err != nil {
+			return nil, err
+		}
+		if len(segments) == 0 || segments[len(segments)-1].rates.EffectiveFrom != rates.EffectiveFrom {
+			segments = append(segments, segment{rates: rates})
+		}
+		segments[len(segments)-1].days = append(segments[len(segments)-1].days, d)
+	}
+	return segments, nil
+}
+
+// calculateSegment
+// Calculate the line items for a run of days that share the same rates.
+// Returns the items and the energy consumed and exported in the segment.
+func calculateSegment(seg segment, req Request, consumptionByDay map[string][]Consumption, exportByDay map[string][]Consumption, priceMap map[int64]float64, surplusMap map[int64]float64) ([]Item, float64, float64, error) {
+	rates := seg.rates
+	from := date.ParseToLocalDay(seg.days[0])
+	to := date.ParseToLocalDay(seg.days[len(seg.days)-1])
+	if rates.PowerP1 == nil || rates.PowerP2 == nil {
+		return nil, 0, 0, fmt.Errorf("%w: the rate schedule has no power terms from %s, set them in BILL_RATES_FILE", ErrUnknownPowerTerms, rates.EffectiveFrom)
+	}
+
+	// Energy term
+	kwh, energy, err := valueReadings(seg.days, consumptionByDay, priceMap)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	var energyRate float64
+	if kwh != 0 {
+		energyRate = energy / kwh
+	}
+
+	// Surplus compensation, which cannot exceed the energy term
+	exportKwh, compensation, err := valueReadings(seg.days, exportByDay, surplusMap)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	var compensationRate float64
+	if exportKwh != 0 {
+		compensationRate = compensation / exportKwh
+	}
+	compensation = math.Min(compensation, energy)
+
+	// Power term, prorated by the number of days in each year
+	var powerP1, powerP2 float64
+	for _, d := range seg.days {
+		daysInYear := float64(time.Date(d.Year(), 12, 31, 0, 0, 0, 0, date.Location).YearDay())
+		powerP1 += req.PowerP1 * *rates.PowerP1 / daysInYear
+		powerP2 += req.PowerP2 * *rates.PowerP2 / daysInYear
+	}
+
+	// The electricity tax applies to energy and power, subject to a minimum per kWh
+	taxBase := energy - compensation + powerP1 + powerP2
+	tax := math.Max(taxBase*rates.ElectricityTax, kwh*rates.ElectricityTaxMin)
+
+	meter := float64(len(seg.days)) * rates.MeterRental
+
+	items := []Item{
+		{Concept: Energy, Quantity: kwh, Unit: "kWh", Rate: energyRate, Amount: roundToCents(energy)},
+		{Concept: PowerP1, Quantity: req.PowerP1, Unit: "kW", Rate: *rates.PowerP1, Amount: roundToCents(powerP1)},
+		{Concept: PowerP2, Quantity: req.PowerP2, Unit: "kW", Rate: *rates.PowerP2, Amount: roundToCents(powerP2)},
+		{Concept: ElectricityTax, Quantity: taxBase, Unit: "EUR", Rate: rates.ElectricityTax, Amount: roundToCents(tax)},
+		{Concept: MeterRental, Quantity: float64(len(seg.days)), Unit: "days", Rate: rates.MeterRental, Amount: roundToCents(meter)},
+	}
+
+	// Only self-consumers with exported energy get a compensation line
+	if exportKwh > 0 {
+		comp := Item{Concept: Compensation, Quantity: exportKwh, Unit: "kWh", Rate: compensationRate, Amount: -roundToCents(compensation)}
+		items = append(items[:1], append([]Item{comp}, items[1:]...)...)
+	}
+
+	// VAT applies to everything above
+	var vatBase float64
+	for _, item := range items {
+		vatBase += item.Amount
+	}
+	items = append(items, Item{Concept: Vat, Quantity: vatBase, Unit: "EUR", Rate: rates.Vat, Amount: roundToCents(vatBase * rates.Vat)})
+
+	for i := range items {
+		items[i].Start = from
+		items[i].End = to
+	}
+
+	return items, kwh, exportKwh, nil
+}
+
+// valueReadings
+// Sum the energy and its value at the hourly prices for the readings on the given days.
+func valueReadings(days []time.Time, readingsByDay map[string][]Consumption, priceMap map[int64]float64) (float64, float64, error) {
+	var kwh, value float64
+	for _, d := range days {
+		for _, c := range readingsByDay[date.ParseToLocalDay(d)] {
+			p, ok := priceMap[c.DateTime.Truncate(time.Hour).Unix()]
+			if !ok {
+				return 0, 0, fmt.Errorf("%w: no price found for %s", ErrInvalidRequest, c.DateTime.In(date.Location).Format("2006-01-02 15:04"))
+			}
+			kwh += c.Kwh
+			value += c.Kwh * p
+		}
+	}
+	return kwh, value, nil
+}
+
+// indexPrices
+// Index the prices by the start of their hour.
+func indexPrices(prices []price.Price) map[int64]float64 {
+	priceMap := make(map[int64]float64)
+	for _, p := range prices {
+		priceMap[p.DateTime.Truncate(time.Hour).Unix()] = p.Price
+	}
+	return priceMap
+}
+
+// groupByDay
+// Group the readings by local day.
+func groupByDay(readings []Consumption) map[string][]Consumption {
+	byDay := make(map[string][]Consumption)
+	for _, c := range readings {
+		day := date.ParseToLocalDay(c.DateTime)
+		byDay[day] = append(byDay[day], c)
+	}
+	return byDay
+}
+
+// roundToCents
+// Round an amount in euro to two decimal places.
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,319 @@
+package bill
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+const epsilon = 1e-4 // Tolerance level
+
+func floatEquals(a, b float64) bool {
+	return math.Abs(a-b) <= epsilon
+}
+
+func TestCalculateBill(t *testing.T) {
+	schedule, err := LoadRates("testdata/rates.toml")
+	if err != nil {
+		t.Fatalf("Error loading rates: %s", err)
+	}
+
+	prices := []price.Price{
+		{DateTime: time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location), Price: 0.1},
+		{DateTime: time.Date(2021, 1, 2, 10, 0, 0, 0, date.Location), Price: 0.2},
+	}
+	consumption := []Consumption{
+		{DateTime: time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location), Kwh: 1},
+		{DateTime: time.Date(2021, 1, 2, 10, 0, 0, 0, date.Location), Kwh: 2},
+	}
+
+	testCases := []struct {
+		name          string
+		start         time.Time
+		end           time.Time
+		req           Request
+		prices        []price.Price
+		expectedItems int
+		expectedKwh   float64
+		expectedTotal float64
+		expectErr     bool
+		invalid       bool
+	}{
+		{
+			name:          "Single rate period",
+			start:         time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location),
+			end:           time.Date(2021, 1, 2, 0, 0, 0, 0, date.Location),
+			req:           Request{PowerP1: 5, PowerP2: 5, Consumption: consumption},
+			prices:        prices,
+			expectedItems: 6,
+			expectedKwh:   3,
+			expectedTotal: 1.89,
+		},
+		{
+			name:          "No consumption",
+			start:         time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location),
+			end:           time.Date(2021, 1, 2, 0, 0, 0, 0, date.Location),
+			req:           Request{PowerP1: 5, PowerP2: 5},
+			prices:        prices,
+			expectedItems: 6,
+			expectedKwh:   0,
+			expectedTotal: 1.32,
+		},
+		{
+			name:          "Rates change during the period",
+			start:         time.Date(2020, 12, 31, 0, 0, 0, 0, date.Location),
+			end:           time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location),
+			req:           Request{PowerP1: 5, PowerP2: 5, Consumption: consumption[:1]},
+			prices:        prices,
+			expectedItems: 12,
+			expectedKwh:   1,
+		},
+		{
+			name:      "Missing price",
+			start:     time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location),
+			end:       time.Date(2021, 1, 2, 0, 0, 0, 0, date.Location),
+			req:       Request{PowerP1: 5, PowerP2: 5, Consumption: consumption},
+			prices:    prices[:1],
+			expectErr: true,
+			invalid:   true,
+		},
+		{
+			name:      "Consumption outside the period",
+			start:     time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location),
+			end:       time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location),
+			req:       Request{PowerP1: 5, PowerP2: 5, Consumption: consumption},
+			prices:    prices,
+			expectErr: true,
+			invalid:   true,
+		},
+		{
+			name:      "End before start",
+			start:     time.Date(2021, 1, 2, 0, 0, 0, 0, date.Location),
+			end:       time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location),
+			req:       Request{PowerP1: 5, PowerP2: 5},
+			expectErr: true,
+			invalid:   true,
+		},
+		{
+			name:      "Negative power",
+			start:     time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location),
+			end:       time.Date(2021, 1, 2, 0, 0, 0, 0, date.Location),
+			req:       Request{PowerP1: -5, PowerP2: 5},
+			expectErr: true,
+			invalid:   true,
+		},
+		{
+			name:      "No rates in effect",
+			start:     time.Date(2019, 1, 1, 0, 0, 0, 0, date.Location),
+			end:       time.Date(2019, 1, 2, 0, 0, 0, 0, date.Location),
+			req:       Request{PowerP1: 5, PowerP2: 5},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bill, err := CalculateBill(tc.start, tc.end, tc.req, tc.prices, nil, schedule)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if errors.Is(err, ErrInvalidRequest) != tc.invalid {
+					t.Errorf("Expected invalid request to be %t for %s", tc.invalid, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+			if len(bill.Items) != tc.expectedItems {
+				t.Errorf("Expected %d items, but got %d", tc.expectedItems, len(bill.Items))
+			}
+			if !floatEquals(bill.ConsumptionKwh, tc.expectedKwh) {
+				t.Errorf("Expected %f kWh, but got %f", tc.expectedKwh, bill.ConsumptionKwh)
+			}
+			var total float64
+			for _, item := range bill.Items {
+				total += item.Amount
+			}
+			if !floatEquals(bill.Total, total) {
+				t.Errorf("Expected total %f to equal the sum of the items %f", bill.Total, total)
+			}
+			if tc.expectedTotal != 0 && !floatEquals(bill.Total, tc.expectedTotal) {
+				t.Errorf("Expected total %f, but got %f", tc.expectedTotal, bill.Total)
+			}
+		})
+	}
+}
+
+func TestCalculateBillItems(t *testing.T) {
+	schedule, err := LoadRates("testdata/rates.toml")
+	if err != nil {
+		t.Fatalf("Error loading rates: %s", err)
+	}
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location)
+	end := time.Date(2021, 1, 2, 0, 0, 0, 0, date.Location)
+	req := Request{
+		PowerP1: 5,
+		PowerP2: 5,
+		Consumption: []Consumption{
+			{DateTime: time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location), Kwh: 1},
+			{DateTime: time.Date(2021, 1, 2, 10, 0, 0, 0, date.Location), Kwh: 2},
+		},
+	}
+	prices := []price.Price{
+		{DateTime: time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location), Price: 0.1},
+		{DateTime: time.Date(2021, 1, 2, 10, 0, 0, 0, date.Location), Price: 0.2},
+	}
+
+	bill, err := CalculateBill(start, end, req, prices, nil, schedule)
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+
+	expected := map[Concept]float64{
+		Energy:         0.5,
+		PowerP1:        1.0,
+		PowerP2:        0.1,
+		ElectricityTax: 0.08,
+		MeterRental:    0.04,
+		Vat:            0.17,
+	}
+
+	for _, item := range bill.Items {
+		if !floatEquals(item.Amount, expected[item.Concept]) {
+			t.Errorf("Expected %s to be %f, but got %f", item.Concept, expected[item.Concept], item.Amount)
+		}
+		if item.Start != "2021-01-01" || item.End != "2021-01-02" {
+			t.Errorf("Expected %s to cover the whole period but got %s to %s", item.Concept, item.Start, item.End)
+		}
+	}
+	if bill.Days != 2 {
+		t.Errorf("Expected 2 days, but got %d", bill.Days)
+	}
+}
+
+func TestCalculateBillUnknownPowerTerms(t *testing.T) {
+	schedule, err := LoadRates("testdata/unknown-power-rates.toml")
+	if err != nil {
+		t.Fatalf("Error loading rates: %s", err)
+	}
+
+	day := time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location)
+	_, err = CalculateBill(day, day, Request{PowerP1: 5, PowerP2: 5}, nil, nil, schedule)
+	if !errors.Is(err, ErrUnknownPowerTerms) {
+		t.Fatalf("Expected an unknown power terms error, got %v", err)
+	}
+
+	// An offer that sets its own power terms can still be billed
+	if _, err := CalculateBill(day, day, Request{PowerP1: 5, PowerP2: 5}, nil, nil, schedule.WithPower(30, 1)); err != nil {
+		t.Errorf("expected nil, got %s", err)
+	}
+}
+
+func TestCalculateBillCompensation(t *testing.T) {
+	schedule, err := LoadRates("testdata/rates.toml")
+	if err != nil {
+		t.Fatalf("Error loading rates: %s", err)
+	}
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location)
+	end := time.Date(2021, 1, 2, 0, 0, 0, 0, date.Location)
+	noon := time.Date(2021, 1, 1, 12, 0, 0, 0, date.Location)
+	consumption := []Consumption{
+		{DateTime: time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location), Kwh: 1},
+		{DateTime: time.Date(2021, 1, 2, 10, 0, 0, 0, date.Location), Kwh: 2},
+	}
+	prices := []price.Price{
+		{DateTime: time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location), Price: 0.1},
+		{DateTime: time.Date(2021, 1, 2, 10, 0, 0, 0, date.Location), Price: 0.2},
+	}
+	surplus := []price.Price{{DateTime: noon, Price: 0.05}}
+
+	testCases := []struct {
+		name                 string
+		export               []Consumption
+		surplus              []price.Price
+		expectedCompensation float64
+		expectedExportKwh    float64
+		expectErr            bool
+	}{
+		{
+			name:                 "Compensated at the surplus price",
+			export:               []Consumption{{DateTime: noon, Kwh: 2}},
+			surplus:              surplus,
+			expectedCompensation: -0.1,
+			expectedExportKwh:    2,
+		},
+		{
+			name:                 "Capped at the energy term",
+			export:               []Consumption{{DateTime: noon, Kwh: 20}},
+			surplus:              surplus,
+			expectedCompensation: -0.5,
+			expectedExportKwh:    20,
+		},
+		{
+			name:      "Missing surplus price",
+			export:    []Consumption{{DateTime: noon, Kwh: 2}},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := Request{PowerP1: 5, PowerP2: 5, Consumption: consumption, Export: tc.export}
+			bill, err := CalculateBill(start, end, req, prices, tc.surplus, schedule)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+
+			var found bool
+			for _, item := range bill.Items {
+				if item.Concept == Compensation {
+					found = true
+					if !floatEquals(item.Amount, tc.expectedCompensation) {
+						t.Errorf("Expected compensation %f, but got %f", tc.expectedCompensation, item.Amount)
+					}
+				}
+			}
+			if !found {
+				t.Error("Expected a compensation item")
+			}
+			if !floatEquals(bill.ExportKwh, tc.expectedExportKwh) {
+				t.Errorf("Expected %f kWh exported, but got %f", tc.expectedExportKwh, bill.ExportKwh)
+			}
+		})
+	}
+}
+
+func TestRoundToCents(t *testing.T) {
+	testCases := []struct {
+		name     string
+		amount   float64
+		expected float64
+	}{
+		{"Zero", 0, 0},
+		{"Round down", 1.234, 1.23},
+		{"Round up", 1.235001, 1.24},
+		{"Negative", -1.234, -1.23},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := roundToCents(tc.amount)
+			if !floatEquals(actual, tc.expected) {
+				t.Errorf("Expected %f, but got %f", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package bill
+
+import (
+	"time"
+)
+
+// Consumption is the energy used, or exported, in the hour starting at DateTime.
+type Consumption struct {
+	DateTime time.Time `json:"dateTime"`
+	Kwh      float64   `json:"kwh"`
+}
+
+type Request struct {
+	Start       string        `json:"start"`
+	End         string        `json:"end"`
+	PowerP1     float64       `json:"powerP1"`
+	PowerP2     float64       `json:"powerP2"`
+	Consumption []Consumption `json:"consumption"`
+	Export      []Consumption `json:"export"`
+}
+
+type Concept string
+
+const (
+	Energy         Concept = "ENERGY"
+	Compensation   Concept = "SURPLUS_COMPENSATION"
+	PowerP1        Concept = "POWER_P1"
+	PowerP2        Concept = "POWER_P2"
+	ElectricityTax Concept = "ELECTRICITY_TAX"
+	MeterRental    Concept = "METER_RENTAL"
+	Vat            Concept = "VAT"
+)
+
+type Item struct {
+	Concept  Concept `json:"concept"`
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Rate     float64 `json:"rate"`
+	Amount   float64 `json:"amount"`
+}
+
+type Bill struct {
+	Start          string  `json:"start"`
+	End            string  `json:"end"`
+	Days           int     `json:"days"`
+	ConsumptionKwh float64 `json:"consumptionKwh"`
+	ExportKwh      float64 `json:"exportKwh"`
+	Items          []Item  `json:"items"`
+	Total          float64 `json:"total"`
+}
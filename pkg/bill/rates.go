@@ -0,0 +1,100 @@
+package bill
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	_ "embed"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"os"
+	"sort"
+	"time"
+)
+
+//go:embed rates.toml
+var defaultRates string
+
+// Rates are the regulated terms that apply to a bill from EffectiveFrom onwards.
+// The power terms are nil when the published values for the period aren't in the schedule.
+type Rates struct {
+	EffectiveFrom     string   `toml:"effectiveFrom" json:"effectiveFrom"`
+	PowerP1           *float64 `toml:"powerP1" json:"powerP1,omitempty"`
+	PowerP2           *float64 `toml:"powerP2" json:"powerP2,omitempty"`
+	ElectricityTax    float64  `toml:"electricityTax" json:"electricityTax"`
+	ElectricityTaxMin float64  `toml:"electricityTaxMin" json:"electricityTaxMin"`
+	MeterRental       float64  `toml:"meterRental" json:"meterRental"`
+	Vat               float64  `toml:"vat" json:"vat"`
+	effectiveFrom     time.Time
+}
+
+type RateSchedule []Rates
+
+// LoadRates loads the rate schedule from the TOML file at the given path.
+// If no path is provided the embedded default schedule is used.
+func LoadRates(path string) (RateSchedule, error) {
+	data := defaultRates
+	if path != "" {
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data = string(bytes)
+	}
+
+	var file struct {
+		Rates []Rates `toml:"rates"`
+	}
+	if _, err := toml.Decode(data, &file); err != nil {
+		return nil, err
+	}
+	if len(file.Rates) == 0 {
+		return nil, fmt.Errorf("no rates found")
+	}
+
+	for i, r := range file.Rates {
+		d, err := date.ParseDate(r.EffectiveFrom)
+		if err != nil {
+			return nil, fmt.Errorf("invalid effectiveFrom %s: %v", r.EffectiveFrom, err)
+		}
+		file.Rates[i].effectiveFrom = d
+	}
+
+	schedule := RateSchedule(file.Rates)
+	sort.Slice(schedule, func(i, j int) bool {
+		return schedule[i].effectiveFrom.Before(schedule[j].effectiveFrom)
+	})
+
+	return schedule, nil
+}
+
+// At returns the rates in effect on the given day.
+func (s RateSchedule) At(t time.Time) (Rates, error) {
+	day := date.StartOfDay(t)
+	for i := len(s) - 1; i >= 0; i-- {
+		if !s[i].effectiveFrom.After(day) {
+			return s[i], nil
+		}
+	}
+	return Rates{}, fmt.Errorf("no rates in effect on %s", date.ParseToLocalDay(t))
+}
+
+// TaxesAt returns the electricity tax and VAT in effect on the given day, so prices can be returned with taxes.
+func (s RateSchedule) TaxesAt(t time.Time) (price.Taxes, error) {
+	rates, err := s.At(t)
+	if err != nil {
+		return price.Taxes{}, err
+	}
+	return price.Taxes{ElectricityTax: rates.ElectricityTax, Vat: rates.Vat}, nil
+}
+
+// WithPower returns a copy of the schedule with the power term prices replaced.
+// This is used to price offers that set their own power term.
+func (s RateSchedule) WithPower(powerP1 float64, powerP2 float64) RateSchedule {
+	schedule := make(RateSchedule, len(s))
+	for i, r := range s {
+		r.PowerP1 = &powerP1
+		r.PowerP2 = &powerP2
+		schedule[i] = r
+	}
+	return schedule
+}
@@ -0,0 +1,100 @@
+package bill
+
+import (
+	"electricity-prices/pkg/date"
+	"testing"
+	"time"
+)
+
+func TestLoadRates(t *testing.T) {
+	testCases := []struct {
+		name      string
+		path      string
+		expectErr bool
+	}{
+		{"Embedded defaults", "", false},
+		{"Valid file", "testdata/rates.toml", false},
+		{"Missing file", "testdata/missing.toml", true},
+		{"Invalid date", "testdata/invalid-rates.toml", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := LoadRates(tc.path)
+			if tc.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expected nil, got %s", err)
+			}
+			if len(schedule) == 0 {
+				t.Error("expected rates to be loaded")
+			}
+			for i := 1; i < len(schedule); i++ {
+				if schedule[i].effectiveFrom.Before(schedule[i-1].effectiveFrom) {
+					t.Errorf("expected rates to be sorted by effectiveFrom")
+				}
+			}
+		})
+	}
+}
+
+func TestRateScheduleAt(t *testing.T) {
+	schedule, err := LoadRates("testdata/rates.toml")
+	if err != nil {
+		t.Fatalf("Error loading rates: %s", err)
+	}
+
+	testCases := []struct {
+		name      string
+		date      time.Time
+		expected  string
+		expectErr bool
+	}{
+		{"Before first entry", time.Date(2019, 12, 31, 12, 0, 0, 0, date.Location), "", true},
+		{"First day of first entry", time.Date(2020, 1, 1, 0, 0, 0, 0, date.Location), "2020-01-01", false},
+		{"Within first entry", time.Date(2020, 6, 15, 23, 0, 0, 0, date.Location), "2020-01-01", false},
+		{"First day of second entry", time.Date(2021, 1, 1, 0, 0, 0, 0, date.Location), "2021-01-01", false},
+		{"After last entry", time.Date(2030, 1, 1, 0, 0, 0, 0, date.Location), "2021-01-01", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rates, err := schedule.At(tc.date)
+			if tc.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expected nil, got %s", err)
+			}
+			if rates.EffectiveFrom != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, rates.EffectiveFrom)
+			}
+		})
+	}
+}
+
+func TestRateScheduleTaxesAt(t *testing.T) {
+	schedule, err := LoadRates("testdata/rates.toml")
+	if err != nil {
+		t.Fatalf("Error loading rates: %s", err)
+	}
+
+	taxes, err := schedule.TaxesAt(time.Date(2021, 3, 1, 0, 0, 0, 0, date.Location))
+	if err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+	if taxes.ElectricityTax != 0.05 || taxes.Vat != 0.1 {
+		t.Errorf("Expected the taxes from 2021-01-01, but got %+v", taxes)
+	}
+
+	if _, err := schedule.TaxesAt(time.Date(2019, 12, 31, 0, 0, 0, 0, date.Location)); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
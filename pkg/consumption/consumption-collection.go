@@ -0,0 +1,101 @@
+package consumption
+
+import (
+	"context"
+	"electricity-prices/pkg/db"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+)
+
+type Collection interface {
+	db.Collection[Consumption]
+	UpsertMany(ctx context.Context, documents []Consumption) error
+}
+
+type ColReceiver struct {
+	Col *mongo.Collection
+}
+
+func (r ColReceiver) FindOne(ctx context.Context, filter interface{}) (Consumption, error) {
+	var c Consumption
+	err := r.Col.FindOne(ctx, filter).Decode(&c)
+
+	if err != nil {
+		return Consumption{}, err
+	}
+
+	return c, err
+}
+
+func (r ColReceiver) Find(ctx context.Context, filter interface{}) ([]Consumption, error) {
+	opts := options.Find().SetSort(bson.M{"dateTime": 1})
+	cur, err := r.Col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(cur *mongo.Cursor, ctx context.Context) {
+		err := cur.Close(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(cur, ctx)
+
+	var readings = make([]Consumption, 0)
+
+	for cur.Next(ctx) {
+		var c Consumption
+		err := cur.Decode(&c)
+		if err != nil {
+			log.Println("Error decoding consumption:", err)
+			continue
+		}
+		readings = append(readings, c)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return readings, nil
+}
+
+func (r ColReceiver) InsertMany(ctx context.Context, documents []Consumption) error {
+	var documentsInterface []interface{}
+	for _, doc := range documents {
+		documentsInterface = append(documentsInterface, doc)
+	}
+
+	_, err := r.Col.InsertMany(ctx, documentsInterface)
+	return err
+}
+
+// UpsertMany stores the readings, replacing any existing reading for the same user and hour.
+// This makes re-importing an overlapping file safe.
+func (r ColReceiver) UpsertMany(ctx context.Context, documents []Consumption) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(documents))
+	for i, doc := range documents {
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"userId": doc.UserID, "dateTime": doc.DateTime}).
+			SetReplacement(doc).
+			SetUpsert(true)
+	}
+
+	_, err := r.Col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r ColReceiver) Aggregate(ctx context.Context, pipeline interface{}) (*mongo.Cursor, error) {
+	cursor, err := r.Col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor, nil
+}
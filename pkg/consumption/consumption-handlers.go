@@ -0,0 +1,114 @@
+package consumption
+
+import (
+	"electricity-prices/pkg/api"
+	"electricity-prices/pkg/auth"
+	"electricity-prices/pkg/date"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	ConsumptionService Service
+}
+
+// UploadConsumption @Summary Upload hourly consumption
+// @Description Imports an hourly consumption CSV as exported by Datadis or the distributors for the user identified by the bearer token. The file can be sent as the request body or as a multipart form field named file. Existing readings for the same hours are replaced.
+// @Tags Consumption
+// @ID upload-consumption
+// @Accept  text/csv
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file false "Consumption CSV"
+// @Success 200 {object} consumption.ImportResult
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 401 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Security BearerAuth
+// @Router /consumption [post]
+func (h *Handler) UploadConsumption(c *gin.Context) {
+	userID := auth.UserID(c)
+
+	// Read the file from the form if provided, otherwise use the raw body
+	var reader io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to read the uploaded file."})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to read the uploaded file."})
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	result, err := h.ConsumptionService.ImportCSV(ctx, userID, reader)
+	if errors.Is(err, ErrInvalidFile) || errors.Is(err, ErrMissingUser) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// GetConsumption @Summary Get hourly consumption
+// @Description Returns the stored hourly consumption for the user identified by the bearer token between the start and end dates (inclusive). Defaults to the last 30 days. The dates should be given in a string form yyyy-MM-dd
+// @Tags Consumption
+// @ID get-consumption
+// @Produce  json
+// @Param start query string false "Start date in format yyyy-MM-dd"
+// @Param end query string false "End date in format yyyy-MM-dd"
+// @Success 200 {object} []consumption.Consumption
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 401 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Security BearerAuth
+// @Router /consumption [get]
+func (h *Handler) GetConsumption(c *gin.Context) {
+	userID := auth.UserID(c)
+
+	// Get the date strings from the request
+	endStr := c.DefaultQuery("end", time.Now().Format("2006-01-02")) // Default to today if not provided
+	end, err := date.ParseDate(endStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse end date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	startStr := c.DefaultQuery("start", end.AddDate(0, 0, -30).Format("2006-01-02"))
+	start, err := date.ParseDate(startStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse start date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	readings, err := h.ConsumptionService.GetConsumption(ctx, userID, start, end.AddDate(0, 0, 1).Add(-time.Second))
+	if errors.Is(err, ErrMissingUser) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, readings)
+}
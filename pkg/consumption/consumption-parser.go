@@ -0,0 +1,168 @@
+package consumption
+
+import (
+	"electricity-prices/pkg/date"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidFile = errors.New("invalid consumption file")
+
+var dateLayouts = []string{"02/01/2006", "2006/01/02", "2006-01-02", "02-01-2006"}
+
+type columns struct {
+	date        int
+	hour        int
+	consumption int
+}
+
+// ParseCSV
+// Parse an hourly consumption CSV as exported by Datadis or the distributors.
+// The files are semicolon separated, use a comma as the decimal separator and
+// index the hours of each local day from 1 to 24 (23 or 25 on the days the clocks change).
+func ParseCSV(r io.Reader) ([]Consumption, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var cols *columns
+	var result []Consumption
+	seen := make(map[int64]bool)
+	line := 0
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, line, err)
+		}
+		if isEmptyRecord(record) {
+			continue
+		}
+
+		// The first non-empty line is the header
+		if cols == nil {
+			cols, err = parseHeader(record)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		c, err := parseRecord(record, *cols)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, line, err)
+		}
+		if seen[c.DateTime.Unix()] {
+			return nil, fmt.Errorf("%w: line %d: duplicate reading for %s", ErrInvalidFile, line, c.DateTime.In(date.Location).Format("2006-01-02 15:04"))
+		}
+		seen[c.DateTime.Unix()] = true
+		result = append(result, c)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: no readings found", ErrInvalidFile)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DateTime.Before(result[j].DateTime)
+	})
+
+	return result, nil
+}
+
+// parseHeader
+// Find the date, hour and consumption columns from the header names.
+func parseHeader(record []string) (*columns, error) {
+	cols := columns{date: -1, hour: -1, consumption: -1}
+	for i, name := range record {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch {
+		case cols.date == -1 && (strings.Contains(name, "fecha") || name == "date"):
+			cols.date = i
+		case cols.hour == -1 && (strings.Contains(name, "hora") || strings.Contains(name, "hour") || name == "time"):
+			cols.hour = i
+		case cols.consumption == -1 && (strings.Contains(name, "consumo") || strings.Contains(name, "consumption") || strings.Contains(name, "kwh")):
+			cols.consumption = i
+		}
+	}
+	if cols.date == -1 || cols.hour == -1 || cols.consumption == -1 {
+		return nil, fmt.Errorf("%w: header must contain date, hour and consumption columns", ErrInvalidFile)
+	}
+	return &cols, nil
+}
+
+// parseRecord
+// Convert a single line of the file into a consumption reading.
+func parseRecord(record []string, cols columns) (Consumption, error) {
+	if len(record) <= cols.date || len(record) <= cols.hour || len(record) <= cols.consumption {
+		return Consumption{}, fmt.Errorf("expected at least %d fields but got %d", max(cols.date, cols.hour, cols.consumption)+1, len(record))
+	}
+
+	day, err := parseDay(strings.TrimSpace(record[cols.date]))
+	if err != nil {
+		return Consumption{}, err
+	}
+
+	index, err := parseHour(strings.TrimSpace(record[cols.hour]))
+	if err != nil {
+		return Consumption{}, err
+	}
+
+	dateTime, err := date.ParseHourIndex(day, index)
+	if err != nil {
+		return Consumption{}, err
+	}
+
+	kwh, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(record[cols.consumption]), ",", ".", 1), 64)
+	if err != nil {
+		return Consumption{}, fmt.Errorf("invalid consumption %q", record[cols.consumption])
+	}
+
+	return Consumption{DateTime: dateTime, Kwh: kwh}, nil
+}
+
+func parseDay(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if d, err := time.ParseInLocation(layout, s, date.Location); err == nil {
+			return d, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// parseHour
+// Parse the hour index. Some exports use the end of the hour in the form HH:MM, e.g. 01:00 for hour 1.
+func parseHour(s string) (int, error) {
+	if hh, mm, found := strings.Cut(s, ":"); found {
+		if mm != "00" {
+			return 0, fmt.Errorf("invalid hour %q", s)
+		}
+		s = hh
+	}
+	index, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hour %q", s)
+	}
+	return index, nil
+}
+
+func isEmptyRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package consumption
+
+import (
+	"electricity-prices/pkg/date"
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSV(t *testing.T) {
+	testCases := []struct {
+		name          string
+		file          string
+		expectedSize  int
+		expectedFirst Consumption
+		expectedLast  Consumption
+		expectErr     bool
+	}{
+		{
+			name:          "Distributor export",
+			file:          "testdata/distributor.csv",
+			expectedSize:  3,
+			expectedFirst: Consumption{DateTime: time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location), Kwh: 0.123},
+			expectedLast:  Consumption{DateTime: time.Date(2023, 11, 29, 23, 0, 0, 0, date.Location), Kwh: 1.05},
+		},
+		{
+			name:          "Datadis export",
+			file:          "testdata/datadis.csv",
+			expectedSize:  3,
+			expectedFirst: Consumption{DateTime: time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location), Kwh: 0.123},
+			expectedLast:  Consumption{DateTime: time.Date(2023, 11, 29, 23, 0, 0, 0, date.Location), Kwh: 1.05},
+		},
+		{
+			name:          "Day with 25 hours",
+			file:          "testdata/fall-back.csv",
+			expectedSize:  25,
+			expectedFirst: Consumption{DateTime: time.Date(2023, 10, 29, 0, 0, 0, 0, date.Location), Kwh: 0.01},
+			expectedLast:  Consumption{DateTime: time.Date(2023, 10, 29, 23, 0, 0, 0, date.Location), Kwh: 0.25},
+		},
+		{name: "Hour out of range", file: "testdata/invalid-hour.csv", expectErr: true},
+		{name: "Invalid consumption", file: "testdata/invalid-consumption.csv", expectErr: true},
+		{name: "Invalid date", file: "testdata/invalid-date.csv", expectErr: true},
+		{name: "Missing hour column", file: "testdata/missing-column.csv", expectErr: true},
+		{name: "Duplicate hour", file: "testdata/duplicate.csv", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := os.Open(tc.file)
+			if err != nil {
+				t.Fatalf("Error reading file: %s", err)
+			}
+			defer file.Close()
+
+			readings, err := ParseCSV(file)
+			if tc.expectErr {
+				if !errors.Is(err, ErrInvalidFile) {
+					t.Errorf("Expected invalid file error but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got %s", err)
+			}
+			if len(readings) != tc.expectedSize {
+				t.Fatalf("Expected %d readings but got %d", tc.expectedSize, len(readings))
+			}
+			assertReading(t, tc.expectedFirst, readings[0])
+			assertReading(t, tc.expectedLast, readings[len(readings)-1])
+		})
+	}
+}
+
+func TestParseCSV_Empty(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("CUPS;Fecha;Hora;Consumo_kWh\n"))
+	if !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("Expected invalid file error but got %v", err)
+	}
+}
+
+func TestParseCSV_RepeatedHour(t *testing.T) {
+	file, err := os.Open("testdata/fall-back.csv")
+	if err != nil {
+		t.Fatalf("Error reading file: %s", err)
+	}
+	defer file.Close()
+
+	readings, err := ParseCSV(file)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+
+	// Hours 3 and 4 are both 2 AM local time, an hour apart
+	if readings[2].DateTime.In(date.Location).Hour() != 2 || readings[3].DateTime.In(date.Location).Hour() != 2 {
+		t.Errorf("Expected hours 3 and 4 to both be 2 AM but got %v and %v", readings[2].DateTime.In(date.Location), readings[3].DateTime.In(date.Location))
+	}
+	if readings[3].DateTime.Sub(readings[2].DateTime) != time.Hour {
+		t.Errorf("Expected hours 3 and 4 to be an hour apart")
+	}
+}
+
+func assertReading(t *testing.T, expected Consumption, actual Consumption) {
+	t.Helper()
+	if !expected.DateTime.Equal(actual.DateTime) {
+		t.Errorf("Expected %v but got %v", expected.DateTime, actual.DateTime)
+	}
+	if math.Abs(expected.Kwh-actual.Kwh) > 1e-9 {
+		t.Errorf("Expected %f kWh but got %f", expected.Kwh, actual.Kwh)
+	}
+}
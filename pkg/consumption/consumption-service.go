@@ -0,0 +1,65 @@
+package consumption
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"io"
+	"time"
+)
+
+var ErrMissingUser = errors.New("a user id is required")
+
+type Service interface {
+	ImportCSV(ctx context.Context, userID string, r io.Reader) (ImportResult, error)
+	GetConsumption(ctx context.Context, userID string, start time.Time, end time.Time) ([]Consumption, error)
+}
+
+type Receiver struct {
+	Collection Collection
+}
+
+// ImportCSV parses the hourly consumption file and stores the readings against the user.
+func (r *Receiver) ImportCSV(ctx context.Context, userID string, reader io.Reader) (ImportResult, error) {
+	if userID == "" {
+		return ImportResult{}, ErrMissingUser
+	}
+
+	readings, err := ParseCSV(reader)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	for i := range readings {
+		readings[i].UserID = userID
+	}
+
+	err = r.Collection.UpsertMany(ctx, readings)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	return ImportResult{
+		UserID:   userID,
+		Imported: len(readings),
+		Start:    readings[0].DateTime,
+		End:      readings[len(readings)-1].DateTime,
+	}, nil
+}
+
+// GetConsumption returns the readings for the user between the start and end times (inclusive).
+func (r *Receiver) GetConsumption(ctx context.Context, userID string, start time.Time, end time.Time) ([]Consumption, error) {
+	if userID == "" {
+		return nil, ErrMissingUser
+	}
+
+	filter := bson.M{
+		"userId": userID,
+		"dateTime": bson.M{
+			"$gte": start,
+			"$lte": end,
+		},
+	}
+
+	return r.Collection.Find(ctx, filter)
+}
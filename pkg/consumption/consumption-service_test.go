@@ -0,0 +1,144 @@
+package consumption
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportCSV(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		userID           string
+		file             string
+		mockUpsertErr    *[]error
+		expectedImported int
+		expectingError   bool
+	}{
+		{
+			name:             "success",
+			userID:           "user-1",
+			file:             "testdata/distributor.csv",
+			mockUpsertErr:    &[]error{},
+			expectedImported: 3,
+			expectingError:   false,
+		},
+		{
+			name:           "missing user",
+			userID:         "",
+			file:           "testdata/distributor.csv",
+			mockUpsertErr:  &[]error{},
+			expectingError: true,
+		},
+		{
+			name:           "invalid file",
+			userID:         "user-1",
+			file:           "testdata/invalid-hour.csv",
+			mockUpsertErr:  &[]error{},
+			expectingError: true,
+		},
+		{
+			name:           "failure saving",
+			userID:         "user-1",
+			file:           "testdata/distributor.csv",
+			mockUpsertErr:  &[]error{errors.New("failed")},
+			expectingError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatalf("Error reading file: %s", err)
+			}
+			defer file.Close()
+
+			upserted := &[][]Consumption{}
+			mockCollection := &MockCollection{MockUpsertMany: upserted, MockUpsertManyErr: tt.mockUpsertErr}
+			service := &Receiver{Collection: mockCollection}
+
+			result, err := service.ImportCSV(ctx, tt.userID, file)
+
+			if tt.expectingError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedImported, result.Imported)
+				assert.Equal(t, tt.userID, result.UserID)
+				assert.Len(t, *upserted, 1)
+				for _, c := range (*upserted)[0] {
+					assert.Equal(t, tt.userID, c.UserID)
+				}
+			}
+		})
+	}
+}
+
+func TestGetConsumption(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	example := Consumption{UserID: "user-1", DateTime: now, Kwh: 1.0}
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockResult     *[][]Consumption
+		mockError      *[]error
+		expectedResult []Consumption
+		expectingError bool
+	}{
+		{
+			name:           "success",
+			userID:         "user-1",
+			mockResult:     &[][]Consumption{{example}},
+			mockError:      &[]error{},
+			expectedResult: []Consumption{example},
+			expectingError: false,
+		},
+		{
+			name:           "missing user",
+			userID:         "",
+			mockResult:     &[][]Consumption{},
+			mockError:      &[]error{},
+			expectingError: true,
+		},
+		{
+			name:           "failure",
+			userID:         "user-1",
+			mockResult:     &[][]Consumption{},
+			mockError:      &[]error{errors.New("not found")},
+			expectingError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{MockFindResult: tt.mockResult, MockFindErr: tt.mockError}
+			service := &Receiver{Collection: mockCollection}
+
+			result, err := service.GetConsumption(ctx, tt.userID, now, now)
+
+			if tt.expectingError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestImportCSV_ReaderError(t *testing.T) {
+	service := &Receiver{Collection: &MockCollection{MockUpsertMany: &[][]Consumption{}, MockUpsertManyErr: &[]error{}}}
+	_, err := service.ImportCSV(context.Background(), "user-1", strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrInvalidFile)
+}
@@ -0,0 +1,20 @@
+package consumption
+
+import (
+	"time"
+)
+
+// Consumption is the energy a user consumed in the hour starting at DateTime.
+type Consumption struct {
+	ID       string    `bson:"_id,omitempty" json:"-"`
+	UserID   string    `bson:"userId" json:"userId"`
+	DateTime time.Time `bson:"dateTime" json:"dateTime"`
+	Kwh      float64   `bson:"kwh" json:"kwh"`
+}
+
+type ImportResult struct {
+	UserID   string    `json:"userId"`
+	Imported int       `json:"imported"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
@@ -0,0 +1,58 @@
+package consumption
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// A mock implementation of Service
+
+type MockConsumptionService struct {
+	MockImportCSVResult      *[]ImportResult
+	MockImportCSVError       *[]error
+	MockGetConsumptionResult *[][]Consumption
+	MockGetConsumptionError  *[]error
+}
+
+func (m *MockConsumptionService) ImportCSV(ctx context.Context, userID string, r io.Reader) (ImportResult, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result ImportResult
+	if len(*m.MockImportCSVResult) > 0 {
+		result = (*m.MockImportCSVResult)[0]
+		*m.MockImportCSVResult = (*m.MockImportCSVResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockImportCSVError) > 0 {
+		err = (*m.MockImportCSVError)[0]
+		*m.MockImportCSVError = (*m.MockImportCSVError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockConsumptionService) GetConsumption(ctx context.Context, userID string, start time.Time, end time.Time) ([]Consumption, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []Consumption
+	if len(*m.MockGetConsumptionResult) > 0 {
+		result = (*m.MockGetConsumptionResult)[0]
+		*m.MockGetConsumptionResult = (*m.MockGetConsumptionResult)[1:]
+	} else {
+		result = nil
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetConsumptionError) > 0 {
+		err = (*m.MockGetConsumptionError)[0]
+		*m.MockGetConsumptionError = (*m.MockGetConsumptionError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
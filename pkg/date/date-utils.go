@@ -57,6 +57,48 @@ func StartOfDay(date time.Time) time.Time {
 	return time.Date(localisedDate.Year(), localisedDate.Month(), localisedDate.Day(), 0, 0, 0, 0, localisedDate.Location())
 }
 
+// nationalHolidays are the fixed-date national holidays in Spain, as month and day.
+var nationalHolidays = [][2]int{
+	{1, 1}, {1, 6}, {5, 1}, {8, 15}, {10, 12}, {11, 1}, {12, 6}, {12, 8}, {12, 25},
+}
+
+// IsNationalHoliday
+// Returns true if the local day is a fixed-date national holiday.
+// These are the holidays treated as off-peak by the 2.0TD tariff.
+func IsNationalHoliday(date time.Time) bool {
+	local := date.In(Location)
+	for _, h := range nationalHolidays {
+		if int(local.Month()) == h[0] && local.Day() == h[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWorkingDay
+// Returns true if the local day is a weekday that is not a national holiday.
+func IsWorkingDay(date time.Time) bool {
+	weekday := date.In(Location).Weekday()
+	return weekday != time.Saturday && weekday != time.Sunday && !IsNationalHoliday(date)
+}
+
+// HoursInDay
+// Returns the number of hours in the local day, which is 23 or 25 on the days the clocks change.
+func HoursInDay(day time.Time) int {
+	start := StartOfDay(day)
+	return int(start.AddDate(0, 0, 1).Sub(start).Hours())
+}
+
+// ParseHourIndex
+// Convert a local hour index (1 to 24, or 23/25 on the days the clocks change) into the start time of that hour.
+// Hour 1 is the hour starting at midnight. The index counts elapsed hours so repeated hours are handled correctly.
+func ParseHourIndex(day time.Time, index int) (time.Time, error) {
+	if index < 1 || index > HoursInDay(day) {
+		return time.Time{}, fmt.Errorf("hour %d is out of range for %s", index, ParseToLocalDay(day))
+	}
+	return StartOfDay(day).Add(time.Duration(index-1) * time.Hour), nil
+}
+
 func ParseEsiosTime(dateStr string, hourRange string) (time.Time, error) {
 	// Convert hour range to integer
 	hour, err := convertHourRangeToIn(hourRange)
@@ -297,3 +297,140 @@ func TestParseEsiosTime(t *testing.T) {
 		})
 	}
 }
+
+func TestHoursInDay(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Madrid")
+	testCases := []struct {
+		name     string
+		date     time.Time
+		expected int
+	}{
+		{"Normal day", time.Date(2023, 1, 2, 12, 0, 0, 0, location), 24},
+		{"Spring forward", time.Date(2023, 3, 26, 12, 0, 0, 0, location), 23},
+		{"Fall back", time.Date(2023, 10, 29, 12, 0, 0, 0, location), 25},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := HoursInDay(tc.date)
+			if result != tc.expected {
+				t.Errorf("Expected %d but was %d", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseHourIndex(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Madrid")
+	testCases := []struct {
+		name          string
+		date          time.Time
+		index         int
+		expected      time.Time
+		errorExpected bool
+	}{
+		{
+			name:     "First hour",
+			date:     time.Date(2023, 1, 2, 0, 0, 0, 0, location),
+			index:    1,
+			expected: time.Date(2023, 1, 2, 0, 0, 0, 0, location),
+		},
+		{
+			name:     "Last hour",
+			date:     time.Date(2023, 1, 2, 0, 0, 0, 0, location),
+			index:    24,
+			expected: time.Date(2023, 1, 2, 23, 0, 0, 0, location),
+		},
+		{
+			name:     "Spring forward skips 2 AM",
+			date:     time.Date(2023, 3, 26, 0, 0, 0, 0, location),
+			index:    3,
+			expected: time.Date(2023, 3, 26, 3, 0, 0, 0, location),
+		},
+		{
+			name:     "Fall back repeats 2 AM",
+			date:     time.Date(2023, 10, 29, 0, 0, 0, 0, location),
+			index:    4,
+			expected: time.Date(2023, 10, 29, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Fall back last hour",
+			date:     time.Date(2023, 10, 29, 0, 0, 0, 0, location),
+			index:    25,
+			expected: time.Date(2023, 10, 29, 23, 0, 0, 0, location),
+		},
+		{
+			name:          "Hour 25 on a normal day",
+			date:          time.Date(2023, 1, 2, 0, 0, 0, 0, location),
+			index:         25,
+			errorExpected: true,
+		},
+		{
+			name:          "Hour 0",
+			date:          time.Date(2023, 1, 2, 0, 0, 0, 0, location),
+			index:         0,
+			errorExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParseHourIndex(tc.date, tc.index)
+			if tc.errorExpected {
+				if err == nil {
+					t.Errorf("Expected error but was nil")
+				}
+				return
+			}
+			if !result.Equal(tc.expected) {
+				t.Errorf("Expected %v but was %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestIsNationalHoliday(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Madrid")
+	testCases := []struct {
+		name     string
+		date     time.Time
+		expected bool
+	}{
+		{"New year", time.Date(2024, 1, 1, 10, 0, 0, 0, location), true},
+		{"Christmas", time.Date(2024, 12, 25, 23, 0, 0, 0, location), true},
+		{"Normal day", time.Date(2024, 1, 2, 10, 0, 0, 0, location), false},
+		{"Holiday in UTC but not locally", time.Date(2023, 12, 31, 23, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := IsNationalHoliday(tc.date)
+			if result != tc.expected {
+				t.Errorf("Expected %v but was %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestIsWorkingDay(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Madrid")
+	testCases := []struct {
+		name     string
+		date     time.Time
+		expected bool
+	}{
+		{"Weekday", time.Date(2024, 1, 2, 10, 0, 0, 0, location), true},
+		{"Saturday", time.Date(2024, 1, 6, 10, 0, 0, 0, location), false},
+		{"Sunday", time.Date(2024, 1, 7, 10, 0, 0, 0, location), false},
+		{"Holiday on a weekday", time.Date(2024, 1, 1, 10, 0, 0, 0, location), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := IsWorkingDay(tc.date)
+			if result != tc.expected {
+				t.Errorf("Expected %v but was %v", tc.expected, result)
+			}
+		})
+	}
+}
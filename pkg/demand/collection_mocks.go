@@ -0,0 +1,49 @@
+package demand
+
+import (
+	"context"
+)
+
+type MockCollection struct {
+	Collection
+	MockFindResult    *[][]Demand
+	MockFindErr       *[]error
+	MockUpsertMany    *[][]Demand
+	MockUpsertManyErr *[]error
+}
+
+func (m *MockCollection) Find(ctx context.Context, filter interface{}) ([]Demand, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []Demand
+	if len(*m.MockFindResult) > 0 {
+		result = (*m.MockFindResult)[0]
+		*m.MockFindResult = (*m.MockFindResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockFindErr) > 0 {
+		err = (*m.MockFindErr)[0]
+		*m.MockFindErr = (*m.MockFindErr)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockCollection) UpsertMany(ctx context.Context, documents []Demand) error {
+	// Record the documents that were upserted
+	*m.MockUpsertMany = append(*m.MockUpsertMany, documents)
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockUpsertManyErr) > 0 {
+		err = (*m.MockUpsertManyErr)[0]
+		*m.MockUpsertManyErr = (*m.MockUpsertManyErr)[1:]
+	} else {
+		err = nil
+	}
+
+	return err
+}
@@ -0,0 +1,7 @@
+package demand
+
+import "time"
+
+type Client interface {
+	GetDemand(t time.Time) ([]Demand, bool, error)
+}
@@ -0,0 +1,116 @@
+package demand
+
+import (
+	"context"
+	"electricity-prices/pkg/db"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+)
+
+type Collection interface {
+	db.Collection[Demand]
+	UpsertMany(ctx context.Context, documents []Demand) error
+	GetLatestDemand(ctx context.Context) (Demand, bool, error)
+}
+
+type ColReceiver struct {
+	Col *mongo.Collection
+}
+
+func (r ColReceiver) FindOne(ctx context.Context, filter interface{}) (Demand, error) {
+	var d Demand
+	err := r.Col.FindOne(ctx, filter).Decode(&d)
+
+	if err != nil {
+		return Demand{}, err
+	}
+
+	return d, err
+}
+
+func (r ColReceiver) Find(ctx context.Context, filter interface{}) ([]Demand, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "dateTime", Value: 1}})
+	cur, err := r.Col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(cur *mongo.Cursor, ctx context.Context) {
+		err := cur.Close(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(cur, ctx)
+
+	var demand = make([]Demand, 0)
+
+	for cur.Next(ctx) {
+		var d Demand
+		err := cur.Decode(&d)
+		if err != nil {
+			log.Println("Error decoding demand:", err)
+			continue
+		}
+		demand = append(demand, d)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return demand, nil
+}
+
+func (r ColReceiver) InsertMany(ctx context.Context, documents []Demand) error {
+	var documentsInterface []interface{}
+	for _, doc := range documents {
+		documentsInterface = append(documentsInterface, doc)
+	}
+
+	_, err := r.Col.InsertMany(ctx, documentsInterface)
+	return err
+}
+
+// UpsertMany stores the demand, replacing any existing demand for the same hour.
+// The real demand is filled in as the hours pass, so the hours are stored again on each sync.
+func (r ColReceiver) UpsertMany(ctx context.Context, documents []Demand) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(documents))
+	for i, doc := range documents {
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"dateTime": doc.DateTime}).
+			SetReplacement(doc).
+			SetUpsert(true)
+	}
+
+	_, err := r.Col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r ColReceiver) Aggregate(ctx context.Context, pipeline interface{}) (*mongo.Cursor, error) {
+	cursor, err := r.Col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor, nil
+}
+
+// GetLatestDemand gets the latest stored hour.
+// It returns a boolean indicating if nothing was found.
+func (r ColReceiver) GetLatestDemand(ctx context.Context) (Demand, bool, error) {
+	var d Demand
+	err := r.Col.FindOne(ctx, bson.M{}, options.FindOne().SetSort(bson.M{"dateTime": -1})).Decode(&d)
+	if err == mongo.ErrNoDocuments {
+		return Demand{}, true, nil
+	}
+	if err != nil {
+		return Demand{}, false, err
+	}
+	return d, false, nil
+}
@@ -0,0 +1,58 @@
+package demand
+
+import (
+	"electricity-prices/pkg/api"
+	"electricity-prices/pkg/date"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	DemandService Service
+}
+
+// GetDemand @Summary Get demand
+// @Description Returns the real, forecast and scheduled peninsular demand in MW for each hour between the start and end dates (inclusive). Defaults to today and tomorrow.
+// @Description The real demand is only set for the hours that have passed.
+// @Tags Demand
+// @ID get-demand
+// @Produce  json
+// @Param start query string false "Start date in format yyyy-MM-dd"
+// @Param end query string false "End date in format yyyy-MM-dd"
+// @Success 200 {array} demand.Demand
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /demand [get]
+func (h *Handler) GetDemand(c *gin.Context) {
+
+	// Get the date strings from the request
+	startStr := c.DefaultQuery("start", time.Now().Format("2006-01-02")) // Default to today if not provided
+	start, err := date.ParseDate(startStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse start date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	endStr := c.DefaultQuery("end", start.AddDate(0, 0, 1).Format("2006-01-02"))
+	end, err := date.ParseDate(endStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse end date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "End date must not be before the start date."})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	demand, err := h.DemandService.GetDemand(ctx, date.StartOfDay(start), date.StartOfDay(end).AddDate(0, 0, 1).Add(-time.Second))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, demand)
+}
@@ -0,0 +1,39 @@
+package demand
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+type Service interface {
+	SaveDemand(ctx context.Context, demand []Demand) error
+	GetDemand(ctx context.Context, start time.Time, end time.Time) ([]Demand, error)
+	GetLatestDemand(ctx context.Context) (Demand, bool, error)
+}
+
+// Receiver implements the Service using the demand in the Collection.
+type Receiver struct {
+	Collection Collection
+}
+
+// SaveDemand stores the demand, replacing any stored for the same hours.
+func (r *Receiver) SaveDemand(ctx context.Context, demand []Demand) error {
+	return r.Collection.UpsertMany(ctx, demand)
+}
+
+// GetDemand gets the stored demand for the hours between start and end (inclusive).
+func (r *Receiver) GetDemand(ctx context.Context, start time.Time, end time.Time) ([]Demand, error) {
+	return r.Collection.Find(ctx, bson.M{
+		"dateTime": bson.M{
+			"$gte": start,
+			"$lte": end,
+		},
+	})
+}
+
+// GetLatestDemand returns the latest stored hour, which may only have a forecast.
+// It returns a boolean indicating if nothing was found.
+func (r *Receiver) GetLatestDemand(ctx context.Context) (Demand, bool, error) {
+	return r.Collection.GetLatestDemand(ctx)
+}
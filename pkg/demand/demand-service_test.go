@@ -0,0 +1,110 @@
+package demand
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBest(t *testing.T) {
+	actual, forecast, scheduled := 25000.0, 25500.0, 24800.0
+
+	tests := []struct {
+		name     string
+		demand   Demand
+		expected float64
+		found    bool
+	}{
+		{"Real", Demand{Real: &actual, Forecast: &forecast, Scheduled: &scheduled}, actual, true},
+		{"Forecast before the hour has passed", Demand{Forecast: &forecast, Scheduled: &scheduled}, forecast, true},
+		{"Scheduled", Demand{Scheduled: &scheduled}, scheduled, true},
+		{"Nothing", Demand{}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, found := tt.demand.Best()
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestPredicted(t *testing.T) {
+	actual, forecast, scheduled := 25000.0, 25500.0, 24800.0
+
+	tests := []struct {
+		name     string
+		demand   Demand
+		expected float64
+		found    bool
+	}{
+		{"Forecast even once the hour has passed", Demand{Real: &actual, Forecast: &forecast, Scheduled: &scheduled}, forecast, true},
+		{"Scheduled", Demand{Real: &actual, Scheduled: &scheduled}, scheduled, true},
+		{"Only real", Demand{Real: &actual}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, found := tt.demand.Predicted()
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestGetDemand(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	forecast := 25500.0
+
+	tests := []struct {
+		name        string
+		demand      []Demand
+		err         error
+		expectError bool
+	}{
+		{"Demand", []Demand{{DateTime: day, Forecast: &forecast}}, nil, false},
+		{"No demand", []Demand{}, nil, false},
+		{"Error", nil, errors.New("error"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockFindResult: &[][]Demand{tt.demand},
+				MockFindErr:    &[]error{tt.err},
+			}
+			service := &Receiver{Collection: mockCollection}
+
+			result, err := service.GetDemand(ctx, day, day.AddDate(0, 0, 1))
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.demand, result)
+		})
+	}
+}
+
+func TestSaveDemand(t *testing.T) {
+	ctx := context.Background()
+	forecast := 25500.0
+	demand := []Demand{{DateTime: time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location), Forecast: &forecast}}
+
+	mockCollection := &MockCollection{
+		MockUpsertMany:    &[][]Demand{},
+		MockUpsertManyErr: &[]error{nil},
+	}
+	service := &Receiver{Collection: mockCollection}
+
+	err := service.SaveDemand(ctx, demand)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]Demand{demand}, *mockCollection.MockUpsertMany)
+}
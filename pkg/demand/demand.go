@@ -0,0 +1,37 @@
+package demand
+
+import "time"
+
+// Demand is the peninsular electricity demand during an hour, in MW.
+// Real is only set once the hour has passed, while Forecast and Scheduled are published ahead.
+type Demand struct {
+	DateTime  time.Time `bson:"dateTime" json:"dateTime"`
+	Real      *float64  `bson:"real,omitempty" json:"real,omitempty"`
+	Forecast  *float64  `bson:"forecast,omitempty" json:"forecast,omitempty"`
+	Scheduled *float64  `bson:"scheduled,omitempty" json:"scheduled,omitempty"`
+}
+
+// Best is the most reliable value for the hour, which is the real demand once it is known and the forecast before.
+func (d Demand) Best() (float64, bool) {
+	if d.Real != nil {
+		return *d.Real, true
+	}
+	if d.Forecast != nil {
+		return *d.Forecast, true
+	}
+	if d.Scheduled != nil {
+		return *d.Scheduled, true
+	}
+	return 0.0, false
+}
+
+// Predicted is the value known before the hour, which is the forecast or, without one, the scheduled demand.
+func (d Demand) Predicted() (float64, bool) {
+	if d.Forecast != nil {
+		return *d.Forecast, true
+	}
+	if d.Scheduled != nil {
+		return *d.Scheduled, true
+	}
+	return 0.0, false
+}
@@ -0,0 +1,113 @@
+package demand
+
+import (
+	"context"
+	"time"
+)
+
+// A mock implementation of Service
+
+type MockDemandService struct {
+	MockSaveDemand              *[][]Demand
+	MockSaveDemandError         *[]error
+	MockGetDemandResult         *[][]Demand
+	MockGetDemandError          *[]error
+	MockGetLatestDemandResult   *[]Demand
+	MockGetLatestDemandNotFound *[]bool
+	MockGetLatestDemandError    *[]error
+}
+
+func (m *MockDemandService) SaveDemand(ctx context.Context, demand []Demand) error {
+	// Record the demand that was saved
+	*m.MockSaveDemand = append(*m.MockSaveDemand, demand)
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockSaveDemandError) > 0 {
+		err = (*m.MockSaveDemandError)[0]
+		*m.MockSaveDemandError = (*m.MockSaveDemandError)[1:]
+	} else {
+		err = nil
+	}
+
+	return err
+}
+
+func (m *MockDemandService) GetLatestDemand(ctx context.Context) (Demand, bool, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result Demand
+	if len(*m.MockGetLatestDemandResult) > 0 {
+		result = (*m.MockGetLatestDemandResult)[0]
+		*m.MockGetLatestDemandResult = (*m.MockGetLatestDemandResult)[1:]
+	}
+
+	var notFound bool
+	if len(*m.MockGetLatestDemandNotFound) > 0 {
+		notFound = (*m.MockGetLatestDemandNotFound)[0]
+		*m.MockGetLatestDemandNotFound = (*m.MockGetLatestDemandNotFound)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetLatestDemandError) > 0 {
+		err = (*m.MockGetLatestDemandError)[0]
+		*m.MockGetLatestDemandError = (*m.MockGetLatestDemandError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, notFound, err
+}
+
+func (m *MockDemandService) GetDemand(ctx context.Context, start time.Time, end time.Time) ([]Demand, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []Demand
+	if len(*m.MockGetDemandResult) > 0 {
+		result = (*m.MockGetDemandResult)[0]
+		*m.MockGetDemandResult = (*m.MockGetDemandResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetDemandError) > 0 {
+		err = (*m.MockGetDemandError)[0]
+		*m.MockGetDemandError = (*m.MockGetDemandError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+// A mock implementation of Client
+
+type MockDemandClient struct {
+	MockGetDemandResult *[][]Demand
+	MockGetDemandSynced *[]bool
+	MockGetDemandError  *[]error
+}
+
+func (m *MockDemandClient) GetDemand(t time.Time) ([]Demand, bool, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []Demand
+	if len(*m.MockGetDemandResult) > 0 {
+		result = (*m.MockGetDemandResult)[0]
+		*m.MockGetDemandResult = (*m.MockGetDemandResult)[1:]
+	}
+
+	// Get the first element of the synced array and remove it from the array, return false if the array is empty
+	var synced bool
+	if len(*m.MockGetDemandSynced) > 0 {
+		synced = (*m.MockGetDemandSynced)[0]
+		*m.MockGetDemandSynced = (*m.MockGetDemandSynced)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetDemandError) > 0 {
+		err = (*m.MockGetDemandError)[0]
+		*m.MockGetDemandError = (*m.MockGetDemandError)[1:]
+	}
+
+	return result, synced, err
+}
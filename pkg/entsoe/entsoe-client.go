@@ -0,0 +1,85 @@
+package entsoe
+
+import (
+	"electricity-prices/pkg/price"
+	"electricity-prices/pkg/web"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"time"
+)
+
+const defaultBaseURL = "https://web-api.tp.entsoe.eu/api"
+
+// dayAheadPrices is the ENTSO-E document type for day-ahead prices.
+const dayAheadPrices = "A44"
+
+// Client gets the day-ahead prices for a bidding zone from the ENTSO-E Transparency Platform.
+// Zone is the EIC code of the bidding zone and the platform requires a security token.
+type Client struct {
+	Http    web.HTTPClient
+	BaseURL string
+	Token   string
+	Zone    string
+}
+
+// GetPrices returns the day-ahead prices for the given date from the ENTSO-E API.
+// The date is taken in the time zone of t and the prices cover that market day of the zone.
+func (c *Client) GetPrices(t time.Time) ([]price.Price, bool, error) {
+	// Parse date to day string
+	day := t.Format("2006-01-02")
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, MarketLocation(c.Zone))
+	end := start.AddDate(0, 0, 1)
+
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	query := url.Values{}
+	query.Set("securityToken", c.Token)
+	query.Set("documentType", dayAheadPrices)
+	query.Set("in_Domain", c.Zone)
+	query.Set("out_Domain", c.Zone)
+	query.Set("periodStart", start.UTC().Format("200601021504"))
+	query.Set("periodEnd", end.UTC().Format("200601021504"))
+
+	// Call to endpoint
+	resp, err := c.Http.Get(baseURL + "?" + query.Encode())
+	if err != nil {
+		return nil, false, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Fatalf("Error occurred while closing response body: %s", err)
+		}
+	}(resp.Body)
+
+	// Read the response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Check if the status code indicates success
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, false, fmt.Errorf("server responded with a non-successful status code: %d", resp.StatusCode)
+	}
+
+	prices, err := ParseDocument(body, start, end)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(prices) == 0 {
+		log.Printf("No ENTSO-E prices for %s", day)
+		// If the date is in the future, return synced as true
+		if t.After(time.Now()) {
+			return nil, true, nil
+		}
+		return nil, false, fmt.Errorf("no ENTSO-E prices for %s", day)
+	}
+
+	return prices, false, nil
+}
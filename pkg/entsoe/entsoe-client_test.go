@@ -0,0 +1,167 @@
+package entsoe
+
+import (
+	"electricity-prices/pkg/date"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+const epsilon = 1e-6 // Tolerance level
+
+// newTestServer serves the recorded fixtures by the start of the period requested.
+func newTestServer(t *testing.T) *httptest.Server {
+	fixtures := map[string]string{
+		"202311282300": "testdata/day-ahead-2023-11-29.xml",
+		"202510142200": "testdata/day-ahead-2025-10-15.xml",
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("securityToken") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if query.Get("documentType") != "A44" || query.Get("in_Domain") != Zones["FR"] || query.Get("out_Domain") != Zones["FR"] {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if query.Get("periodStart") == "202001012300" {
+			_, _ = w.Write([]byte("not xml"))
+			return
+		}
+		file, ok := fixtures[query.Get("periodStart")]
+		if !ok {
+			file = "testdata/no-data.xml"
+		}
+		body, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Error reading fixture: %s", err)
+		}
+		_, _ = w.Write(body)
+	}))
+}
+
+func TestGetPrices(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name               string
+		testDate           time.Time
+		token              string
+		expectedResultSize int
+		expectedFirstPrice float64
+		expectedFifthPrice float64
+		expectSynced       bool
+		expectingError     bool
+	}{
+		{
+			name:               "Hourly resolution with a repeated position",
+			testDate:           time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			token:              "token",
+			expectedResultSize: 24,
+			expectedFirstPrice: 0.101,
+			expectedFifthPrice: 0.104,
+		},
+		{
+			name:               "Quarter-hour resolution",
+			testDate:           time.Date(2025, 10, 15, 0, 0, 0, 0, date.Location),
+			token:              "token",
+			expectedResultSize: 24,
+			expectedFirstPrice: 0.086,
+			expectedFifthPrice: 0.086,
+		},
+		{
+			name:         "No data - in future",
+			testDate:     time.Now().AddDate(0, 0, 1),
+			token:        "token",
+			expectSynced: true,
+		},
+		{
+			name:           "No data - in past",
+			testDate:       time.Date(2000, 10, 11, 0, 0, 0, 0, date.Location),
+			token:          "token",
+			expectingError: true,
+		},
+		{
+			name:           "Invalid document",
+			testDate:       time.Date(2020, 1, 2, 0, 0, 0, 0, date.Location),
+			token:          "token",
+			expectingError: true,
+		},
+		{
+			name:           "Invalid token",
+			testDate:       time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			token:          "invalid",
+			expectingError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := Client{Http: server.Client(), BaseURL: server.URL, Token: test.token, Zone: Zones["FR"]}
+
+			prices, synced, err := client.GetPrices(test.testDate)
+
+			if test.expectingError && err == nil {
+				t.Errorf("Expected an error but got nil")
+			}
+			if !test.expectingError && err != nil {
+				t.Errorf("Expected no error but got %s", err)
+			}
+			if synced != test.expectSynced {
+				t.Errorf("Expected synced to be %t but got %t", test.expectSynced, synced)
+			}
+			if len(prices) != test.expectedResultSize {
+				t.Fatalf("Expected %d prices but got %d", test.expectedResultSize, len(prices))
+			}
+			if len(prices) == 0 {
+				return
+			}
+			if !prices[0].DateTime.Equal(date.StartOfDay(test.testDate)) {
+				t.Errorf("Expected first price at %s but got %s", date.StartOfDay(test.testDate), prices[0].DateTime)
+			}
+			if diff := prices[0].Price - test.expectedFirstPrice; diff > epsilon || diff < -epsilon {
+				t.Errorf("Expected first price %f but got %f", test.expectedFirstPrice, prices[0].Price)
+			}
+			if diff := prices[4].Price - test.expectedFifthPrice; diff > epsilon || diff < -epsilon {
+				t.Errorf("Expected fifth price %f but got %f", test.expectedFifthPrice, prices[4].Price)
+			}
+		})
+	}
+}
+
+func TestGetPricesMarketDay(t *testing.T) {
+	tests := []struct {
+		zone          string
+		expectedStart string
+		expectedEnd   string
+	}{
+		{"FR", "202311282300", "202311292300"},
+		{"IE-SEM", "202311290000", "202311300000"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.zone, func(t *testing.T) {
+			var start, end string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				start, end = r.URL.Query().Get("periodStart"), r.URL.Query().Get("periodEnd")
+				body, err := os.ReadFile("testdata/no-data.xml")
+				if err != nil {
+					t.Fatalf("Error reading fixture: %s", err)
+				}
+				_, _ = w.Write(body)
+			}))
+			defer server.Close()
+			client := Client{Http: server.Client(), BaseURL: server.URL, Token: "token", Zone: Zones[test.zone]}
+
+			_, _, _ = client.GetPrices(time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location))
+
+			if start != test.expectedStart || end != test.expectedEnd {
+				t.Errorf("Expected the period %s to %s but got %s to %s", test.expectedStart, test.expectedEnd, start, end)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package entsoe
+
+import (
+	"electricity-prices/pkg/price"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrInvalidDocument = errors.New("invalid publication market document")
+
+// noDataReason is the acknowledgement reason code used when there is no data for the period requested.
+const noDataReason = "999"
+
+// ParseDocument
+// Parse a Publication_MarketDocument into hourly prices between start (inclusive) and end (exclusive).
+// Each point's position counts resolution steps from the start of its period. For the A03 curve type
+// positions without a point keep the price of the previous point. Sub-hourly resolutions are averaged into hours.
+// A document can carry the same prices at more than one resolution, such as the quarter-hour prices and their
+// hourly equivalents, so each hour only uses the finest resolution that has prices for it.
+// An acknowledgement that there is no data returns no prices.
+func ParseDocument(body []byte, start time.Time, end time.Time) ([]price.Price, error) {
+	var ack AcknowledgementMarketDocument
+	if xml.Unmarshal(body, &ack) == nil {
+		if ack.Reason.Code == noDataReason {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("request was not fulfilled: %s %s", ack.Reason.Code, ack.Reason.Text)
+	}
+
+	var doc PublicationMarketDocument
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+
+	// Sum the prices of each hour separately for each resolution
+	sums := make(map[int64]map[time.Duration]float64)
+	counts := make(map[int64]map[time.Duration]int)
+	for _, ts := range doc.TimeSeries {
+		if ts.MeasureUnit != "" && ts.MeasureUnit != "MWH" {
+			return nil, fmt.Errorf("%w: unsupported unit %s", ErrInvalidDocument, ts.MeasureUnit)
+		}
+		for _, period := range ts.Periods {
+			values, resolution, err := expandPeriod(period, ts.CurveType)
+			if err != nil {
+				return nil, err
+			}
+			for t, p := range values {
+				if t.Before(start) || !t.Before(end) {
+					continue
+				}
+				hour := t.Truncate(time.Hour).Unix()
+				if sums[hour] == nil {
+					sums[hour] = make(map[time.Duration]float64)
+					counts[hour] = make(map[time.Duration]int)
+				}
+				sums[hour][resolution] += p
+				counts[hour][resolution]++
+			}
+		}
+	}
+
+	prices := make([]price.Price, 0, len(sums))
+	for hour, byResolution := range sums {
+		finest := time.Duration(0)
+		for resolution := range byResolution {
+			if finest == 0 || resolution < finest {
+				finest = resolution
+			}
+		}
+		prices = append(prices, price.Price{
+			DateTime: time.Unix(hour, 0).UTC(),
+			Price:    byResolution[finest] / float64(counts[hour][finest]) / 1000,
+		})
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].DateTime.Before(prices[j].DateTime)
+	})
+
+	return prices, nil
+}
+
+// expandPeriod
+// Return the price for each resolution step of the period and the resolution.
+func expandPeriod(period Period, curveType string) (map[time.Time]float64, time.Duration, error) {
+	start, err := parseTime(period.TimeInterval.Start)
+	if err != nil {
+		return nil, 0, err
+	}
+	end, err := parseTime(period.TimeInterval.End)
+	if err != nil {
+		return nil, 0, err
+	}
+	resolution, err := parseResolution(period.Resolution)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	steps := int(end.Sub(start) / resolution)
+	points := make(map[int]float64)
+	for _, point := range period.Points {
+		if point.Position < 1 || point.Position > steps {
+			return nil, 0, fmt.Errorf("%w: position %d is outside of the period", ErrInvalidDocument, point.Position)
+		}
+		points[point.Position] = point.Price
+	}
+
+	values := make(map[time.Time]float64)
+	var last float64
+	var found bool
+	for position := 1; position <= steps; position++ {
+		p, ok := points[position]
+		if ok {
+			last, found = p, true
+		} else if curveType != "A03" || !found {
+			// Only the A03 curve type allows points to be left out
+			continue
+		}
+		values[start.Add(time.Duration(position-1)*resolution)] = last
+	}
+	return values, resolution, nil
+}
+
+// parseResolution
+// Parse the ISO 8601 durations used for the resolution of a period.
+func parseResolution(resolution string) (time.Duration, error) {
+	switch resolution {
+	case "PT15M":
+		return 15 * time.Minute, nil
+	case "PT30M":
+		return 30 * time.Minute, nil
+	case "PT60M", "PT1H":
+		return time.Hour, nil
+	}
+	return 0, fmt.Errorf("%w: unsupported resolution %s", ErrInvalidDocument, resolution)
+}
+
+// parseTime
+// Parse the UTC times used in time intervals, which are given to the minute.
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02T15:04Z", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid time %s", ErrInvalidDocument, s)
+	}
+	return t, nil
+}
@@ -0,0 +1,179 @@
+package entsoe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDocument(t *testing.T) {
+	start := time.Date(2023, 11, 28, 23, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+
+	document := func(curveType string, resolution string, points string) []byte {
+		return []byte(`<Publication_MarketDocument><TimeSeries>
+			<price_Measure_Unit.name>MWH</price_Measure_Unit.name>
+			<curveType>` + curveType + `</curveType>
+			<Period>
+				<timeInterval><start>2023-11-28T23:00Z</start><end>2023-11-29T03:00Z</end></timeInterval>
+				<resolution>` + resolution + `</resolution>` + points + `
+			</Period>
+		</TimeSeries></Publication_MarketDocument>`)
+	}
+	point := func(position string, amount string) string {
+		return `<Point><position>` + position + `</position><price.amount>` + amount + `</price.amount></Point>`
+	}
+
+	tests := []struct {
+		name           string
+		body           []byte
+		expectedPrices []float64
+		invalid        bool
+	}{
+		{
+			name:           "All positions",
+			body:           document("A01", "PT60M", point("1", "10")+point("2", "20")+point("3", "30")+point("4", "40")),
+			expectedPrices: []float64{0.01, 0.02, 0.03, 0.04},
+		},
+		{
+			name:           "A03 repeats the previous position",
+			body:           document("A03", "PT60M", point("1", "10")+point("3", "30")),
+			expectedPrices: []float64{0.01, 0.01, 0.03, 0.03},
+		},
+		{
+			name:           "A01 leaves missing positions out",
+			body:           document("A01", "PT60M", point("1", "10")+point("3", "30")),
+			expectedPrices: []float64{0.01, 0.03},
+		},
+		{
+			name:           "Half-hour resolution is averaged",
+			body:           document("A03", "PT30M", point("1", "10")+point("2", "20")+point("3", "30")),
+			expectedPrices: []float64{0.015, 0.03, 0.03, 0.03},
+		},
+		{
+			name:    "Position outside the period",
+			body:    document("A03", "PT60M", point("5", "10")),
+			invalid: true,
+		},
+		{
+			name:    "Unsupported resolution",
+			body:    document("A03", "P1D", point("1", "10")),
+			invalid: true,
+		},
+		{
+			name:    "Not a document",
+			body:    []byte("<html></html>"),
+			invalid: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prices, err := ParseDocument(test.body, start, end)
+			if test.invalid {
+				if !errors.Is(err, ErrInvalidDocument) {
+					t.Fatalf("Expected an invalid document error but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got %s", err)
+			}
+			if len(prices) != len(test.expectedPrices) {
+				t.Fatalf("Expected %d prices but got %d", len(test.expectedPrices), len(prices))
+			}
+			for i, p := range prices {
+				if diff := p.Price - test.expectedPrices[i]; diff > epsilon || diff < -epsilon {
+					t.Errorf("Expected price %d to be %f but got %f", i, test.expectedPrices[i], p.Price)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDocumentMixedResolutions(t *testing.T) {
+	start := time.Date(2023, 11, 28, 23, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	// The hourly series repeats the first hour of the quarter-hour series, which only covers that hour
+	body := []byte(`<Publication_MarketDocument>
+		<TimeSeries>
+			<price_Measure_Unit.name>MWH</price_Measure_Unit.name>
+			<curveType>A01</curveType>
+			<Period>
+				<timeInterval><start>2023-11-28T23:00Z</start><end>2023-11-29T01:00Z</end></timeInterval>
+				<resolution>PT60M</resolution>
+				<Point><position>1</position><price.amount>100</price.amount></Point>
+				<Point><position>2</position><price.amount>50</price.amount></Point>
+			</Period>
+		</TimeSeries>
+		<TimeSeries>
+			<price_Measure_Unit.name>MWH</price_Measure_Unit.name>
+			<curveType>A01</curveType>
+			<Period>
+				<timeInterval><start>2023-11-28T23:00Z</start><end>2023-11-29T00:00Z</end></timeInterval>
+				<resolution>PT15M</resolution>
+				<Point><position>1</position><price.amount>10</price.amount></Point>
+				<Point><position>2</position><price.amount>20</price.amount></Point>
+				<Point><position>3</position><price.amount>30</price.amount></Point>
+				<Point><position>4</position><price.amount>40</price.amount></Point>
+			</Period>
+		</TimeSeries>
+	</Publication_MarketDocument>`)
+
+	prices, err := ParseDocument(body, start, end)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	expected := []float64{0.025, 0.05}
+	if len(prices) != len(expected) {
+		t.Fatalf("Expected %d prices but got %d", len(expected), len(prices))
+	}
+	for i, p := range prices {
+		if diff := p.Price - expected[i]; diff > epsilon || diff < -epsilon {
+			t.Errorf("Expected price %d to be %f but got %f", i, expected[i], p.Price)
+		}
+	}
+}
+
+func TestParseZone(t *testing.T) {
+	tests := []struct {
+		zone     string
+		expected string
+		ok       bool
+	}{
+		{"FR", "10YFR-RTE------C", true},
+		{"de-lu", "10Y1001A1001A82H", true},
+		{"10YNL----------L", "10YNL----------L", true},
+		{"XX", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.zone, func(t *testing.T) {
+			eic, ok := ParseZone(test.zone)
+			if eic != test.expected || ok != test.ok {
+				t.Errorf("Expected %s, %t but got %s, %t", test.expected, test.ok, eic, ok)
+			}
+		})
+	}
+}
+
+func TestSeriesFor(t *testing.T) {
+	tests := []struct {
+		zone     string
+		expected string
+	}{
+		{"DE-LU", "entsoe-de-lu"},
+		{"fr", "entsoe-fr"},
+		{"10YFR-RTE------C", "entsoe-fr"},
+		{"10Y1001A1001A59C", "entsoe-ie-sem"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.zone, func(t *testing.T) {
+			if series := SeriesFor(test.zone); string(series) != test.expected {
+				t.Errorf("Expected %s but got %s", test.expected, series)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package entsoe
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// Zones maps the short names of common bidding zones to their EIC codes.
+var Zones = map[string]string{
+	"AT":      "10YAT-APG------L",
+	"BE":      "10YBE----------2",
+	"DE-LU":   "10Y1001A1001A82H",
+	"DK1":     "10YDK-1--------W",
+	"DK2":     "10YDK-2--------M",
+	"ES":      "10YES-REE------0",
+	"FI":      "10YFI-1--------U",
+	"FR":      "10YFR-RTE------C",
+	"IE-SEM":  "10Y1001A1001A59C",
+	"IT-NORD": "10Y1001A1001A73I",
+	"NL":      "10YNL----------L",
+	"PL":      "10YPL-AREA-----S",
+	"PT":      "10YPT-REN------W",
+}
+
+// marketTimeZones are the time zones of the bidding zones whose market days don't follow Central European Time, by EIC code.
+var marketTimeZones = map[string]string{
+	"10Y1001A1001A59C": "Europe/Dublin",
+}
+
+// MarketLocation returns the time zone whose days are the market days of a bidding zone, given its EIC code.
+// Zones that aren't listed follow Central European Time, as Madrid does.
+func MarketLocation(eic string) *time.Location {
+	if name, ok := marketTimeZones[eic]; ok {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return date.Location
+}
+
+// ParseZone returns the EIC code for a bidding zone given either its short name or its EIC code.
+func ParseZone(zone string) (string, bool) {
+	if eic, ok := Zones[strings.ToUpper(zone)]; ok {
+		return eic, true
+	}
+	for _, eic := range Zones {
+		if eic == zone {
+			return eic, true
+		}
+	}
+	return "", false
+}
+
+// ZoneName returns the short name for a bidding zone given either its short name or its EIC code.
+func ZoneName(zone string) (string, bool) {
+	if _, ok := Zones[strings.ToUpper(zone)]; ok {
+		return strings.ToUpper(zone), true
+	}
+	for name, eic := range Zones {
+		if eic == zone {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// SeriesFor returns the price series the day-ahead prices for a bidding zone are stored as, e.g. entsoe-fr.
+// A zone given by its EIC code is stored under its short name, so both select the same series.
+func SeriesFor(zone string) price.Series {
+	if name, ok := ZoneName(zone); ok {
+		zone = name
+	}
+	return price.Series("entsoe-" + strings.ToLower(zone))
+}
+
+// PublicationMarketDocument is the IEC 62325 document returned for day-ahead prices.
+type PublicationMarketDocument struct {
+	XMLName    xml.Name     `xml:"Publication_MarketDocument"`
+	TimeSeries []TimeSeries `xml:"TimeSeries"`
+}
+
+type TimeSeries struct {
+	Currency    string   `xml:"currency_Unit.name"`
+	MeasureUnit string   `xml:"price_Measure_Unit.name"`
+	CurveType   string   `xml:"curveType"`
+	Periods     []Period `xml:"Period"`
+}
+
+type Period struct {
+	TimeInterval TimeInterval `xml:"timeInterval"`
+	Resolution   string       `xml:"resolution"`
+	Points       []Point      `xml:"Point"`
+}
+
+type TimeInterval struct {
+	Start string `xml:"start"`
+	End   string `xml:"end"`
+}
+
+type Point struct {
+	Position int     `xml:"position"`
+	Price    float64 `xml:"price.amount"`
+}
+
+// AcknowledgementMarketDocument is returned instead of prices when a request cannot be fulfilled.
+type AcknowledgementMarketDocument struct {
+	XMLName xml.Name `xml:"Acknowledgement_MarketDocument"`
+	Reason  struct {
+		Code string `xml:"code"`
+		Text string `xml:"text"`
+	} `xml:"Reason"`
+}
@@ -77,9 +77,14 @@ func (e *Client) GetPrices(t time.Time) ([]price.Price, bool, error) {
 			if err != nil {
 				return nil, false, fmt.Errorf("error converting date: %v", err)
 			}
+			components, err := convertComponents(p)
+			if err != nil {
+				return nil, false, fmt.Errorf("error converting price components: %v", err)
+			}
 			prices[i] = price.Price{
-				DateTime: convetedDate,
-				Price:    convertedP / 1000,
+				DateTime:   convetedDate,
+				Price:      convertedP / 1000,
+				Components: components,
 			}
 		}
 
@@ -89,6 +94,38 @@ func (e *Client) GetPrices(t time.Time) ([]price.Price, bool, error) {
 	return nil, false, fmt.Errorf("server responded with a non-successful status code: %d", resp.StatusCode)
 }
 
+// convertComponents
+// Convert the components of the PVPC price from €/MWh to €/kWh.
+// Returns nil if the archive doesn't include the breakdown. Components missing from older archives are zero.
+func convertComponents(p EsioPVPC) (*price.Components, error) {
+	if p.PMHPCB == "" {
+		return nil, nil
+	}
+
+	values := make([]float64, 10)
+	for i, s := range []string{p.PMHPCB, p.SAHPCB, p.PCAPPCB, p.INTPCB, p.FOMPCB, p.FOSPCB, p.TEUPCB, p.CCVPCB, p.EDSRPCB, p.EDCGASPCB} {
+		if s == "" {
+			continue
+		}
+		v, err := convertStringToFloat(s)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v / 1000
+	}
+
+	return &price.Components{
+		Energy:           values[0],
+		Adjustment:       values[1],
+		CapacityPayments: values[2],
+		Interruptibility: values[3],
+		OperatorFees:     values[4] + values[5],
+		TollsAndCharges:  values[6],
+		TradingCosts:     values[7],
+		Other:            values[8] + values[9],
+	}, nil
+}
+
 func convertStringToFloat(s string) (float64, error) {
 	// Replace comma with period
 	s = strings.Replace(s, ",", ".", -1)
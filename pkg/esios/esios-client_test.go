@@ -2,9 +2,11 @@ package esios
 
 import (
 	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
 	"electricity-prices/pkg/testutils"
 	"electricity-prices/pkg/web/testdata"
 	"errors"
+	"math"
 	"net/http"
 	"testing"
 	"time"
@@ -190,3 +192,66 @@ func TestGetPrices(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertComponents(t *testing.T) {
+	tests := []struct {
+		name           string
+		pvpc           EsioPVPC
+		expected       *price.Components
+		expectingError bool
+	}{
+		{
+			name: "Full breakdown",
+			pvpc: EsioPVPC{PMHPCB: "111,42", SAHPCB: "14,34", FOMPCB: "0,04", FOSPCB: "0,19", INTPCB: "0,00",
+				PCAPPCB: "0,00", TEUPCB: "3,18", CCVPCB: "3,30", EDSRPCB: "0,00", EDCGASPCB: "0,00"},
+			expected: &price.Components{Energy: 0.11142, Adjustment: 0.01434, OperatorFees: 0.00023,
+				TollsAndCharges: 0.00318, TradingCosts: 0.0033},
+		},
+		{
+			name:     "Older archive without the newer components",
+			pvpc:     EsioPVPC{PMHPCB: "100,00", TEUPCB: "40,00"},
+			expected: &price.Components{Energy: 0.1, TollsAndCharges: 0.04},
+		},
+		{
+			name:     "No breakdown",
+			pvpc:     EsioPVPC{PCB: "132,47"},
+			expected: nil,
+		},
+		{
+			name:           "Invalid component",
+			pvpc:           EsioPVPC{PMHPCB: "111,42", TEUPCB: "invalid"},
+			expectingError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			components, err := convertComponents(test.pvpc)
+			if test.expectingError {
+				if err == nil {
+					t.Errorf("Expected an error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got %s", err)
+			}
+			if test.expected == nil {
+				if components != nil {
+					t.Errorf("Expected no components but got %+v", components)
+				}
+				return
+			}
+			if components == nil {
+				t.Fatalf("Expected components but got nil")
+			}
+			if math.Abs(components.Energy-test.expected.Energy) > 1e-9 ||
+				math.Abs(components.Adjustment-test.expected.Adjustment) > 1e-9 ||
+				math.Abs(components.OperatorFees-test.expected.OperatorFees) > 1e-9 ||
+				math.Abs(components.TollsAndCharges-test.expected.TollsAndCharges) > 1e-9 ||
+				math.Abs(components.TradingCosts-test.expected.TradingCosts) > 1e-9 {
+				t.Errorf("Expected %+v but got %+v", test.expected, components)
+			}
+		})
+	}
+}
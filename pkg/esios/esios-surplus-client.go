@@ -0,0 +1,97 @@
+package esios
+
+import (
+	"electricity-prices/pkg/price"
+	"electricity-prices/pkg/web"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// surplusIndicator is the ESIOS indicator for the price of surplus self-consumption energy under the simplified compensation mechanism.
+const surplusIndicator = 1739
+
+// peninsulaGeoID is the ESIOS geography for the Spanish peninsula.
+const peninsulaGeoID = 8741
+
+const indicatorUrlTemplate = "https://api.esios.ree.es/indicators/%d?start_date=%sT00:00&end_date=%sT23:59&geo_ids[]=%d"
+
+// SurplusClient gets the regulated surplus compensation prices from the ESIOS indicators API.
+// The indicators API requires a personal token.
+type SurplusClient struct {
+	Http  web.HTTPClient
+	Token string
+}
+
+// GetPrices returns the surplus compensation prices for the given date from the ESIOS API
+func (e *SurplusClient) GetPrices(t time.Time) ([]price.Price, bool, error) {
+	// Parse date to day string
+	day := t.Format("2006-01-02")
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(indicatorUrlTemplate, surplusIndicator, day, day, peninsulaGeoID), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", "application/json; application/vnd.esios-api-v1+json")
+	req.Header.Set("x-api-key", e.Token)
+
+	// Call to endpoint
+	resp, err := e.Http.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Fatalf("Error occurred while closing response body: %s", err)
+		}
+	}(resp.Body)
+
+	// Read the response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Check if the status code indicates success
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, false, fmt.Errorf("server responded with a non-successful status code: %d", resp.StatusCode)
+	}
+
+	// Parse the JSON response body into the response struct
+	var res EsiosIndicatorResponse
+	err = json.Unmarshal(body, &res)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if res.Indicator.ID != surplusIndicator {
+		return nil, false, fmt.Errorf("failed to parse response for day %s", day)
+	}
+
+	var prices []price.Price
+	for _, v := range res.Indicator.Values {
+		if v.GeoID != 0 && v.GeoID != peninsulaGeoID {
+			continue
+		}
+		prices = append(prices, price.Price{
+			DateTime: v.DateTimeUTC,
+			Price:    v.Value / 1000,
+		})
+	}
+
+	if len(prices) == 0 {
+		log.Printf("No surplus prices for %s", day)
+		// If the date is in the future, return synced as true
+		if t.After(time.Now()) {
+			return nil, true, nil
+		}
+		return nil, false, fmt.Errorf("no surplus prices for %s", day)
+	}
+
+	return prices, false, nil
+}
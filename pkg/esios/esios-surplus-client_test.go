@@ -0,0 +1,109 @@
+package esios
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/testutils"
+	"electricity-prices/pkg/web/testdata"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSurplusClientGetPrices(t *testing.T) {
+	tests := []struct {
+		name               string
+		testDate           time.Time
+		mockResponse       *http.Response
+		mockError          error
+		expectedResultSize int
+		expectedFirstPrice float64
+		expectSynced       bool
+		expectingError     bool
+	}{
+		{
+			name:     "Valid response",
+			testDate: time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			mockResponse: &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(
+				testutils.ReadJsonStringFromFile("testdata/surplus-2023-11-29.json"))},
+			expectedResultSize: 24,
+			expectedFirstPrice: 0.06,
+			expectSynced:       false,
+			expectingError:     false,
+		},
+		{
+			name:     "No values - in future",
+			testDate: time.Now().AddDate(0, 0, 1),
+			mockResponse: &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(
+				testutils.ReadJsonStringFromFile("testdata/surplus-no-values.json"))},
+			expectedResultSize: 0,
+			expectSynced:       true,
+			expectingError:     false,
+		},
+		{
+			name:     "No values - in past",
+			testDate: time.Date(2000, 10, 11, 0, 0, 0, 0, date.Location),
+			mockResponse: &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(
+				testutils.ReadJsonStringFromFile("testdata/surplus-no-values.json"))},
+			expectedResultSize: 0,
+			expectSynced:       false,
+			expectingError:     true,
+		},
+		{
+			name:               "Invalid data returned",
+			testDate:           time.Date(2022, 10, 11, 0, 0, 0, 0, date.Location),
+			mockResponse:       &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(`{"data": "invalid"}`)},
+			expectedResultSize: 0,
+			expectSynced:       false,
+			expectingError:     true,
+		},
+		{
+			name:           "401 error",
+			testDate:       time.Date(2022, 10, 11, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 401, Body: testdata.NewMockReadCloser("")},
+			expectSynced:   false,
+			expectingError: true,
+		},
+		{
+			name:           "Error calling to API",
+			testDate:       time.Date(2022, 10, 11, 0, 0, 0, 0, date.Location),
+			mockResponse:   nil,
+			mockError:      errors.New("mock error"),
+			expectSynced:   false,
+			expectingError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := SurplusClient{Http: &testdata.MockHTTPClient{
+				MockResp: test.mockResponse,
+				MockErr:  test.mockError,
+			}, Token: "token"}
+			prices, synced, err := client.GetPrices(test.testDate)
+			if test.expectingError {
+				if err == nil {
+					t.Errorf("Expected error but got nil")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Expected no error but got %s", err)
+				}
+			}
+			if synced != test.expectSynced {
+				t.Errorf("Expected synced to be %t but got %t", test.expectSynced, synced)
+			}
+			if len(prices) != test.expectedResultSize {
+				t.Errorf("Expected %d prices but got %d", test.expectedResultSize, len(prices))
+			}
+			if len(prices) > 0 {
+				if prices[0].Price != test.expectedFirstPrice {
+					t.Errorf("Expected first price to be %f but got %f", test.expectedFirstPrice, prices[0].Price)
+				}
+				if !prices[0].DateTime.Equal(test.testDate) {
+					t.Errorf("Expected first price at %v but got %v", test.testDate, prices[0].DateTime)
+				}
+			}
+		})
+	}
+}
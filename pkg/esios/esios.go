@@ -1,13 +1,41 @@
 package esios
 
+import "time"
+
 type EsioPVPC struct {
-	Day  string `json:"Dia"`
-	Hour string `json:"Hora"`
-	PCB  string `json:"PCB"`
-	GEN  string `json:"GEN"`
+	Day       string `json:"Dia"`
+	Hour      string `json:"Hora"`
+	PCB       string `json:"PCB"`
+	GEN       string `json:"GEN"`
+	PMHPCB    string `json:"PMHPCB"`
+	SAHPCB    string `json:"SAHPCB"`
+	FOMPCB    string `json:"FOMPCB"`
+	FOSPCB    string `json:"FOSPCB"`
+	INTPCB    string `json:"INTPCB"`
+	PCAPPCB   string `json:"PCAPPCB"`
+	TEUPCB    string `json:"TEUPCB"`
+	CCVPCB    string `json:"CCVPCB"`
+	EDSRPCB   string `json:"EDSRPCB"`
+	EDCGASPCB string `json:"EDCGASPCB"`
 }
 
 type EsiosResponse struct {
 	PVPC    []EsioPVPC `json:"PVPC"`
 	Message string     `json:"message"`
 }
+
+type EsiosIndicatorValue struct {
+	Value       float64   `json:"value"`
+	DateTimeUTC time.Time `json:"datetime_utc"`
+	GeoID       int       `json:"geo_id"`
+}
+
+type EsiosIndicator struct {
+	ID     int                   `json:"id"`
+	Name   string                `json:"name"`
+	Values []EsiosIndicatorValue `json:"values"`
+}
+
+type EsiosIndicatorResponse struct {
+	Indicator EsiosIndicator `json:"indicator"`
+}
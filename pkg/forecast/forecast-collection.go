@@ -0,0 +1,102 @@
+package forecast
+
+import (
+	"context"
+	"electricity-prices/pkg/db"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+)
+
+type Collection interface {
+	db.Collection[Forecast]
+	UpsertMany(ctx context.Context, documents []Forecast) error
+}
+
+type ColReceiver struct {
+	Col *mongo.Collection
+}
+
+func (r ColReceiver) FindOne(ctx context.Context, filter interface{}) (Forecast, error) {
+	var f Forecast
+	err := r.Col.FindOne(ctx, filter).Decode(&f)
+
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	f.Forecast = true
+	return f, err
+}
+
+func (r ColReceiver) Find(ctx context.Context, filter interface{}) ([]Forecast, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "model", Value: 1}})
+	cur, err := r.Col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(cur *mongo.Cursor, ctx context.Context) {
+		err := cur.Close(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(cur, ctx)
+
+	var forecasts = make([]Forecast, 0)
+
+	for cur.Next(ctx) {
+		var f Forecast
+		err := cur.Decode(&f)
+		if err != nil {
+			log.Println("Error decoding forecast:", err)
+			continue
+		}
+		f.Forecast = true
+		forecasts = append(forecasts, f)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return forecasts, nil
+}
+
+func (r ColReceiver) InsertMany(ctx context.Context, documents []Forecast) error {
+	var documentsInterface []interface{}
+	for _, doc := range documents {
+		documentsInterface = append(documentsInterface, doc)
+	}
+
+	_, err := r.Col.InsertMany(ctx, documentsInterface)
+	return err
+}
+
+// UpsertMany stores the forecasts, replacing any existing forecast for the same day and model.
+func (r ColReceiver) UpsertMany(ctx context.Context, documents []Forecast) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(documents))
+	for i, doc := range documents {
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"date": doc.Date, "model": doc.Model}).
+			SetReplacement(doc).
+			SetUpsert(true)
+	}
+
+	_, err := r.Col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r ColReceiver) Aggregate(ctx context.Context, pipeline interface{}) (*mongo.Cursor, error) {
+	cursor, err := r.Col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,140 @@
+package forecast
+
+import (
+	"electricity-prices/pkg/api"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the forecast endpoints. Taxes is optional and is needed to return prices with taxes.
+type Handler struct {
+	ForecastService Service
+	Taxes           price.TaxSchedule
+}
+
+// GetForecast @Summary Get price forecast
+// @Description Returns a forecast of the hourly prices for the date, made from the prices before it. Defaults to tomorrow so it can be used before the official prices are published around 20:15.
+// @Description The prices are estimates and are labelled with forecast true. The lower and upper bounds hold 80% of the model's errors on the previous four weeks.
+// @Tags Forecast
+// @ID get-forecast
+// @Produce  json
+// @Param date query string false "Date in format yyyy-MM-dd"
+// @Param model query string false "Forecasting model, regression (default), regression-demand, same-weekday or seasonal-naive"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Success 200 {object} forecast.Forecast
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /forecast [get]
+func (h *Handler) GetForecast(c *gin.Context) {
+
+	// Get the date string from the request
+	dateStr := c.DefaultQuery("date", time.Now().AddDate(0, 0, 1).Format("2006-01-02")) // Default to tomorrow if not provided
+
+	// Parse the date string
+	d, err := date.ParseDate(dateStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+
+	// Parse the model
+	model, ok := ParseModel(c.DefaultQuery("model", string(Regression)))
+	if !ok {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Unknown model. Use regression, regression-demand, same-weekday or seasonal-naive."})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := price.ParseOutput(c.Query("unit"), c.Query("taxes"), c.Query("timezone"), h.Taxes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	forecast, err := h.ForecastService.GetForecast(ctx, d, model)
+	if errors.Is(err, ErrInsufficientHistory) {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	forecast, err = forecastWithOutput(forecast, output)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, forecast)
+}
+
+// GetAccuracy @Summary Get forecast accuracy
+// @Description Returns how close each model's recorded forecasts were to the prices once they were published, for the days between the start and end dates (inclusive). Defaults to the last 30 days.
+// @Tags Forecast
+// @ID get-forecast-accuracy
+// @Produce  json
+// @Param start query string false "Start date in format yyyy-MM-dd"
+// @Param end query string false "End date in format yyyy-MM-dd"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT"
+// @Success 200 {object} forecast.AccuracyReport
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /forecast/accuracy [get]
+func (h *Handler) GetAccuracy(c *gin.Context) {
+
+	// Get the date strings from the request
+	endStr := c.DefaultQuery("end", time.Now().Format("2006-01-02")) // Default to today if not provided
+	end, err := date.ParseDate(endStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse end date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	startStr := c.DefaultQuery("start", end.AddDate(0, 0, -30).Format("2006-01-02"))
+	start, err := date.ParseDate(startStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse start date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "End date must not be before the start date."})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := price.ParseOutput(c.Query("unit"), c.Query("taxes"), c.Query("timezone"), h.Taxes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	report, err := h.ForecastService.GetAccuracy(ctx, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	report, err = accuracyWithOutput(report, output)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, report)
+}
@@ -0,0 +1,122 @@
+package forecast
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/demand"
+	"electricity-prices/pkg/price"
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+type Service interface {
+	GetForecast(ctx context.Context, day time.Time, model Model) (Forecast, error)
+	RecordForecasts(ctx context.Context, day time.Time) ([]Forecast, error)
+	GetAccuracy(ctx context.Context, start time.Time, end time.Time) (AccuracyReport, error)
+}
+
+// Receiver forecasts from the prices in the PriceService and stores the forecasts in the Collection to track their accuracy.
+// The DemandService is optional, and without it the RegressionDemand model has no demand to forecast with.
+type Receiver struct {
+	PriceService  price.Service
+	DemandService demand.Service
+	Collection    Collection
+}
+
+// GetForecast forecasts the day's hourly prices with the model from the prices before it.
+func (r *Receiver) GetForecast(ctx context.Context, day time.Time, model Model) (Forecast, error) {
+	history, err := r.getHistory(ctx, day)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	d, err := r.getDemand(ctx, day)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	forecast, err := CalculateDemandForecast(history, d, day, model)
+	if err != nil {
+		return Forecast{}, err
+	}
+	forecast.CreatedAt = time.Now()
+	return forecast, nil
+}
+
+// RecordForecasts forecasts the day with every model and stores the forecasts, replacing any made earlier.
+// Models without enough history are skipped.
+func (r *Receiver) RecordForecasts(ctx context.Context, day time.Time) ([]Forecast, error) {
+	history, err := r.getHistory(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := r.getDemand(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts := make([]Forecast, 0, len(Models))
+	for _, model := range Models {
+		forecast, err := CalculateDemandForecast(history, d, day, model)
+		if err != nil {
+			continue
+		}
+		forecast.CreatedAt = time.Now()
+		forecasts = append(forecasts, forecast)
+	}
+
+	err = r.Collection.UpsertMany(ctx, forecasts)
+	if err != nil {
+		return nil, err
+	}
+	return forecasts, nil
+}
+
+// GetAccuracy compares the stored forecasts for the days between start and end (inclusive) with the actual prices.
+func (r *Receiver) GetAccuracy(ctx context.Context, start time.Time, end time.Time) (AccuracyReport, error) {
+	from := date.StartOfDay(start)
+	to := date.StartOfDay(end).AddDate(0, 0, 1).Add(-time.Second)
+
+	forecasts, err := r.Collection.Find(ctx, bson.M{
+		"date": bson.M{
+			"$gte": date.ParseToLocalDay(from),
+			"$lte": date.ParseToLocalDay(to),
+		},
+	})
+	if err != nil {
+		return AccuracyReport{}, err
+	}
+
+	actual, err := r.PriceService.GetPrices(ctx, from, to)
+	if err != nil {
+		return AccuracyReport{}, err
+	}
+
+	report := AccuracyReport{
+		Start:  date.ParseToLocalDay(from),
+		End:    date.ParseToLocalDay(to),
+		Models: make([]Accuracy, 0, len(Models)),
+	}
+	for _, model := range Models {
+		report.Models = append(report.Models, CalculateAccuracy(model, forecasts, actual))
+	}
+	return report, nil
+}
+
+// getHistory
+// Get the prices the forecasts for the day need.
+func (r *Receiver) getHistory(ctx context.Context, day time.Time) ([]price.Price, error) {
+	start := date.StartOfDay(day)
+	return r.PriceService.GetPrices(ctx, start.AddDate(0, 0, -HistoryDays), start.Add(-time.Second))
+}
+
+// getDemand
+// Get the demand over the history and the day itself, which is published ahead as a forecast.
+func (r *Receiver) getDemand(ctx context.Context, day time.Time) ([]demand.Demand, error) {
+	if r.DemandService == nil {
+		return nil, nil
+	}
+	start := date.StartOfDay(day)
+	return r.DemandService.GetDemand(ctx, start.AddDate(0, 0, -HistoryDays), start.AddDate(0, 0, 1).Add(-time.Second))
+}
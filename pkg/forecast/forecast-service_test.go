@@ -0,0 +1,128 @@
+package forecast
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/demand"
+	"electricity-prices/pkg/price"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetForecast(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+
+	tests := []struct {
+		name        string
+		history     []price.Price
+		historyErr  error
+		expectedErr error
+	}{
+		{"Forecast", history(day, HistoryDays), nil, nil},
+		{"No history", []price.Price{}, nil, ErrInsufficientHistory},
+		{"Error", nil, errors.New("error"), errors.New("error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPriceService := &price.MockPriceService{
+				MockGetPricesResult: &[][]price.Price{tt.history},
+				MockGetPricesError:  &[]error{tt.historyErr},
+			}
+			service := &Receiver{PriceService: mockPriceService}
+
+			forecast, err := service.GetForecast(ctx, day, Regression)
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, forecast.Forecast)
+			assert.Equal(t, "2024-05-15", forecast.Date)
+			assert.Len(t, forecast.Prices, 24)
+			assert.False(t, forecast.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestRecordForecasts(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+
+	tests := []struct {
+		name           string
+		history        []price.Price
+		demand         []demand.Demand
+		expectedModels []Model
+	}{
+		{"All models", history(day, HistoryDays), demandHistory(day, HistoryDays), Models},
+		{"Only the models with enough history", history(day, 7), demandHistory(day, 7), []Model{SeasonalNaive, SameWeekday}},
+		{"Only the models without demand", history(day, HistoryDays), nil, []Model{SeasonalNaive, SameWeekday, Regression}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPriceService := &price.MockPriceService{
+				MockGetPricesResult: &[][]price.Price{tt.history},
+				MockGetPricesError:  &[]error{nil},
+			}
+			mockCollection := &MockCollection{
+				MockUpsertMany:    &[][]Forecast{},
+				MockUpsertManyErr: &[]error{},
+			}
+			mockDemandService := &demand.MockDemandService{
+				MockGetDemandResult: &[][]demand.Demand{tt.demand},
+				MockGetDemandError:  &[]error{nil},
+			}
+			service := &Receiver{PriceService: mockPriceService, DemandService: mockDemandService, Collection: mockCollection}
+
+			forecasts, err := service.RecordForecasts(ctx, day)
+
+			assert.NoError(t, err)
+			var models []Model
+			for _, f := range forecasts {
+				models = append(models, f.Model)
+			}
+			assert.Equal(t, tt.expectedModels, models)
+			assert.Len(t, *mockCollection.MockUpsertMany, 1)
+			assert.Equal(t, forecasts, (*mockCollection.MockUpsertMany)[0])
+		})
+	}
+}
+
+func TestGetAccuracy(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+
+	mockPriceService := &price.MockPriceService{
+		MockGetPricesResult: &[][]price.Price{{{DateTime: day, Price: 0.12}}},
+		MockGetPricesError:  &[]error{nil},
+	}
+	mockCollection := &MockCollection{
+		MockFindResult: &[][]Forecast{{
+			{Model: Regression, Prices: []HourForecast{{DateTime: day, Price: 0.1, Lower: 0.05, Upper: 0.15}}},
+		}},
+		MockFindErr: &[]error{nil},
+	}
+	service := &Receiver{PriceService: mockPriceService, Collection: mockCollection}
+
+	report, err := service.GetAccuracy(ctx, day, day.AddDate(0, 0, 6))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-05-15", report.Start)
+	assert.Equal(t, "2024-05-21", report.End)
+	assert.Len(t, report.Models, len(Models))
+	for _, a := range report.Models {
+		if a.Model == Regression {
+			assert.Equal(t, 1, a.Hours)
+			assert.InDelta(t, 0.02, a.MAE, 1e-9)
+			assert.Equal(t, 1.0, a.Coverage)
+		} else {
+			assert.Equal(t, 0, a.Hours)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package forecast
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/demand"
+	"electricity-prices/pkg/price"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// confidence is the width of the band in percent
+	confidence = 80.0
+	// bandDays is the number of previous days the forecast error is measured on
+	bandDays = 28
+	// sameWeekdayWeeks is the number of previous weeks averaged by the same weekday model
+	sameWeekdayWeeks = 4
+	// regressionDays is the number of previous days the regression is fitted on
+	regressionDays = 56
+	// HistoryDays is the number of days of history the forecasts need
+	HistoryDays = bandDays + regressionDays + 7
+)
+
+// dayIndex holds the prices by local day and hour.
+type dayIndex map[string]map[int]float64
+
+// CalculateForecast
+// Forecast the day's hourly prices with the model from the history before it.
+func CalculateForecast(history []price.Price, day time.Time, model Model) (Forecast, error) {
+	return CalculateDemandForecast(history, nil, day, model)
+}
+
+// CalculateDemandForecast
+// Forecast the day's hourly prices with the model from the history before it and the demand up to the end of the day.
+// Only the RegressionDemand model uses the demand, and it needs the demand of the day itself.
+// The model is fitted on the best known demand, but the previous days used for the band are predicted from their
+// forecast demand, as only the forecast is known for the day itself.
+func CalculateDemandForecast(history []price.Price, d []demand.Demand, day time.Time, model Model) (Forecast, error) {
+	index := indexPrices(history, day)
+	demandIndex := indexDemand(d, demand.Demand.Best)
+	predictedDemandIndex := indexDemand(d, demand.Demand.Predicted)
+	point, ok := predict(model, index, demandIndex, demandIndex, day)
+	if !ok {
+		return Forecast{}, ErrInsufficientHistory
+	}
+
+	// Measure the model's error on the previous days to get the band
+	var errs []float64
+	for k := 1; k <= bandDays; k++ {
+		d := date.StartOfDay(day).AddDate(0, 0, -k)
+		predicted, ok := predict(model, index, demandIndex, predictedDemandIndex, d)
+		if !ok {
+			continue
+		}
+		for hour, actual := range index[date.ParseToLocalDay(d)] {
+			if p, ok := predicted[hour]; ok {
+				errs = append(errs, actual-p)
+			}
+		}
+	}
+	sort.Float64s(errs)
+	lowerErr := price.Percentile(errs, (100-confidence)/2)
+	upperErr := price.Percentile(errs, 100-(100-confidence)/2)
+
+	forecast := Forecast{
+		Date:       date.ParseToLocalDay(day),
+		Model:      model,
+		Forecast:   true,
+		Confidence: confidence,
+		Prices:     make([]HourForecast, 0, 25),
+	}
+	for i := 1; i <= date.HoursInDay(day); i++ {
+		t, _ := date.ParseHourIndex(day, i)
+		p := point[t.In(date.Location).Hour()]
+		forecast.Prices = append(forecast.Prices, HourForecast{DateTime: t, Price: p, Lower: p + lowerErr, Upper: p + upperErr})
+		forecast.DayAverage += p
+		forecast.DayLower += p + lowerErr
+		forecast.DayUpper += p + upperErr
+	}
+	n := float64(len(forecast.Prices))
+	forecast.DayAverage /= n
+	forecast.DayLower /= n
+	forecast.DayUpper /= n
+
+	return forecast, nil
+}
+
+// CalculateAccuracy
+// Compare the model's forecasts with the actual prices. Hours without an actual price are skipped.
+func CalculateAccuracy(model Model, forecasts []Forecast, actual []price.Price) Accuracy {
+	actualByTime := make(map[int64]float64, len(actual))
+	for _, p := range actual {
+		actualByTime[p.DateTime.Unix()] = p.Price
+	}
+
+	accuracy := Accuracy{Model: model}
+	var absErr, sqErr, err float64
+	var inside int
+	for _, f := range forecasts {
+		if f.Model != model {
+			continue
+		}
+		var hours int
+		for _, h := range f.Prices {
+			a, ok := actualByTime[h.DateTime.Unix()]
+			if !ok {
+				continue
+			}
+			hours++
+			e := h.Price - a
+			absErr += math.Abs(e)
+			sqErr += e * e
+			err += e
+			if a >= h.Lower && a <= h.Upper {
+				inside++
+			}
+		}
+		if hours > 0 {
+			accuracy.Days++
+			accuracy.Hours += hours
+		}
+	}
+	if accuracy.Hours == 0 {
+		return accuracy
+	}
+
+	n := float64(accuracy.Hours)
+	accuracy.MAE = absErr / n
+	accuracy.RMSE = math.Sqrt(sqErr / n)
+	accuracy.Bias = err / n
+	accuracy.Coverage = float64(inside) / n
+	return accuracy
+}
+
+// indexPrices
+// Index the prices before the day by local day and hour.
+func indexPrices(prices []price.Price, day time.Time) dayIndex {
+	start := date.StartOfDay(day)
+	times := make([]time.Time, 0, len(prices))
+	values := make([]float64, 0, len(prices))
+	for _, p := range prices {
+		if !p.DateTime.Before(start) {
+			continue
+		}
+		times = append(times, p.DateTime)
+		values = append(values, p.Price)
+	}
+	return averageIndex(times, values)
+}
+
+// indexDemand
+// Index the demand by local day and hour, in GW, using the value of each hour, such as Demand.Best.
+func indexDemand(d []demand.Demand, value func(demand.Demand) (float64, bool)) dayIndex {
+	times := make([]time.Time, 0, len(d))
+	values := make([]float64, 0, len(d))
+	for _, h := range d {
+		v, ok := value(h)
+		if !ok {
+			continue
+		}
+		times = append(times, h.DateTime)
+		values = append(values, v/1000)
+	}
+	return averageIndex(times, values)
+}
+
+// averageIndex
+// Index the values by local day and hour, averaging the two hours that share a local hour when the clocks go back.
+func averageIndex(times []time.Time, values []float64) dayIndex {
+	sums := make(dayIndex)
+	counts := make(map[string]map[int]int)
+	for i, t := range times {
+		local := t.In(date.Location)
+		key := date.ParseToLocalDay(local)
+		if sums[key] == nil {
+			sums[key] = make(map[int]float64)
+			counts[key] = make(map[int]int)
+		}
+		sums[key][local.Hour()] += values[i]
+		counts[key][local.Hour()]++
+	}
+	for key, hours := range sums {
+		for hour, sum := range hours {
+			hours[hour] = sum / float64(counts[key][hour])
+		}
+	}
+	return sums
+}
+
+// predict
+// Get the model's price for each local hour of the day using only the prices of the days before it.
+func predict(model Model, index dayIndex, demandIndex dayIndex, dayDemandIndex dayIndex, day time.Time) (map[int]float64, bool) {
+	switch model {
+	case SeasonalNaive:
+		return predictSeasonalNaive(index, day)
+	case SameWeekday:
+		return predictSameWeekday(index, day)
+	case Regression:
+		return predictRegression(index, nil, nil, day)
+	case RegressionDemand:
+		return predictRegression(index, demandIndex, dayDemandIndex, day)
+	}
+	return nil, false
+}
+
+func predictSeasonalNaive(index dayIndex, day time.Time) (map[int]float64, bool) {
+	previous, ok := index[date.ParseToLocalDay(date.StartOfDay(day).AddDate(0, 0, -1))]
+	if !ok || len(previous) == 0 {
+		return nil, false
+	}
+	return fillHours(previous), true
+}
+
+func predictSameWeekday(index dayIndex, day time.Time) (map[int]float64, bool) {
+	sums := make(map[int]float64)
+	counts := make(map[int]int)
+	for week := 1; week <= sameWeekdayWeeks; week++ {
+		for hour, p := range index[date.ParseToLocalDay(date.StartOfDay(day).AddDate(0, 0, -7*week))] {
+			sums[hour] += p
+			counts[hour]++
+		}
+	}
+	if len(sums) == 0 {
+		return nil, false
+	}
+	result := make(map[int]float64, len(sums))
+	for hour, sum := range sums {
+		result[hour] = sum / float64(counts[hour])
+	}
+	return fillHours(result), true
+}
+
+// predictRegression
+// Fit price = b0 + b1 * day before + b2 * week before + b3 * working day on the previous days and apply it to the day.
+// When the demand is given it is added as a fifth input, and hours without demand are skipped. The fit uses the
+// demand of the demandIndex and the day uses its demand in the dayDemandIndex.
+func predictRegression(index dayIndex, demandIndex dayIndex, dayDemandIndex dayIndex, day time.Time) (map[int]float64, bool) {
+	start := date.StartOfDay(day)
+	var xs [][]float64
+	var ys []float64
+	for k := 1; k <= regressionDays; k++ {
+		d := start.AddDate(0, 0, -k)
+		for hour, y := range index[date.ParseToLocalDay(d)] {
+			x, ok := regressionFeatures(index, demandIndex, d, hour)
+			if ok {
+				xs = append(xs, x)
+				ys = append(ys, y)
+			}
+		}
+	}
+	coefficients, ok := leastSquares(xs, ys)
+	if !ok {
+		return nil, false
+	}
+
+	result := make(map[int]float64)
+	for hour := 0; hour < 24; hour++ {
+		x, ok := regressionFeatures(index, dayDemandIndex, start, hour)
+		if !ok {
+			continue
+		}
+		var p float64
+		for i := range x {
+			p += coefficients[i] * x[i]
+		}
+		result[hour] = p
+	}
+	if len(result) == 0 {
+		return nil, false
+	}
+	return fillHours(result), true
+}
+
+// regressionFeatures
+// Get the regression inputs for the hour of the day, including its demand when the demand is given.
+func regressionFeatures(index dayIndex, demandIndex dayIndex, day time.Time, hour int) ([]float64, bool) {
+	dayBefore, ok := index[date.ParseToLocalDay(day.AddDate(0, 0, -1))][hour]
+	if !ok {
+		return nil, false
+	}
+	weekBefore, ok := index[date.ParseToLocalDay(day.AddDate(0, 0, -7))][hour]
+	if !ok {
+		return nil, false
+	}
+	working := 0.0
+	if date.IsWorkingDay(day) {
+		working = 1.0
+	}
+	if demandIndex == nil {
+		return []float64{1, dayBefore, weekBefore, working}, true
+	}
+	d, ok := demandIndex[date.ParseToLocalDay(day)][hour]
+	if !ok {
+		return nil, false
+	}
+	return []float64{1, dayBefore, weekBefore, working, d}, true
+}
+
+// leastSquares
+// Solve the normal equations of an ordinary least squares fit with Gaussian elimination.
+// Returns false when there are too few rows or the inputs are collinear.
+func leastSquares(xs [][]float64, ys []float64) ([]float64, bool) {
+	if len(xs) == 0 || len(xs) <= len(xs[0]) {
+		return nil, false
+	}
+	n := len(xs[0])
+
+	// Build the augmented matrix [X'X | X'y]
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, n+1)
+	}
+	for r, x := range xs {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a[i][j] += x[i] * x[j]
+			}
+			a[i][n] += x[i] * ys[r]
+		}
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := 0; r < n; r++ {
+			if r == col {
+				continue
+			}
+			factor := a[r][col] / a[col][col]
+			for c := col; c <= n; c++ {
+				a[r][c] -= factor * a[col][c]
+			}
+		}
+	}
+
+	coefficients := make([]float64, n)
+	for i := range coefficients {
+		coefficients[i] = a[i][n] / a[i][i]
+	}
+	return coefficients, true
+}
+
+// fillHours
+// Fill any missing local hours with the nearest known hour, preferring the earlier one.
+func fillHours(hours map[int]float64) map[int]float64 {
+	result := make(map[int]float64, 24)
+	for h := 0; h < 24; h++ {
+		if p, ok := hours[h]; ok {
+			result[h] = p
+			continue
+		}
+		for offset := 1; offset < 24; offset++ {
+			if p, ok := hours[h-offset]; ok {
+				result[h] = p
+				break
+			}
+			if p, ok := hours[h+offset]; ok {
+				result[h] = p
+				break
+			}
+		}
+	}
+	return result
+}
+
+// forecastWithOutput
+// Convert the forecast prices and their bands to the output unit and time zone, using the taxes in effect on the day.
+func forecastWithOutput(forecast Forecast, output price.Output) (Forecast, error) {
+	factor, err := output.DayFactor(forecast.Date)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	forecast.DayAverage *= factor
+	forecast.DayLower *= factor
+	forecast.DayUpper *= factor
+	forecast.CreatedAt = output.Time(forecast.CreatedAt)
+
+	prices := make([]HourForecast, len(forecast.Prices))
+	for i, h := range forecast.Prices {
+		prices[i] = HourForecast{
+			DateTime: output.Time(h.DateTime),
+			Price:    h.Price * factor,
+			Lower:    h.Lower * factor,
+			Upper:    h.Upper * factor,
+		}
+	}
+	forecast.Prices = prices
+	return forecast, nil
+}
+
+// accuracyWithOutput
+// Convert the forecast errors to the output unit, using the taxes in effect on the last day.
+func accuracyWithOutput(report AccuracyReport, output price.Output) (AccuracyReport, error) {
+	factor, err := output.DayFactor(report.End)
+	if err != nil {
+		return AccuracyReport{}, err
+	}
+
+	models := make([]Accuracy, len(report.Models))
+	for i, a := range report.Models {
+		a.MAE *= factor
+		a.RMSE *= factor
+		a.Bias *= factor
+		models[i] = a
+	}
+	report.Models = models
+	return report, nil
+}
@@ -0,0 +1,288 @@
+package forecast
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/demand"
+	"electricity-prices/pkg/price"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// history builds hourly prices for the days before the day, with a daily shape, a working day premium and some noise.
+func history(day time.Time, days int) []price.Price {
+	var prices []price.Price
+	start := date.StartOfDay(day).AddDate(0, 0, -days)
+	for d := start; d.Before(date.StartOfDay(day)); d = d.AddDate(0, 0, 1) {
+		for i := 1; i <= date.HoursInDay(d); i++ {
+			t, _ := date.ParseHourIndex(d, i)
+			p := 0.1 + 0.005*float64(t.In(date.Location).Hour())
+			if date.IsWorkingDay(d) {
+				p += 0.02
+			}
+			p += 0.002 * math.Sin(float64(d.YearDay()*24+i))
+			prices = append(prices, price.Price{DateTime: t, Price: p})
+		}
+	}
+	return prices
+}
+
+// demandHistory builds the hourly demand for the days before the day and the day itself, in MW.
+func demandHistory(day time.Time, days int) []demand.Demand {
+	var result []demand.Demand
+	start := date.StartOfDay(day).AddDate(0, 0, -days)
+	for d := start; !d.After(date.StartOfDay(day)); d = d.AddDate(0, 0, 1) {
+		for i := 1; i <= date.HoursInDay(d); i++ {
+			t, _ := date.ParseHourIndex(d, i)
+			mw := 25000 + 300*float64(t.In(date.Location).Hour()) + 800*math.Cos(float64(d.YearDay()*7+i))
+			result = append(result, demand.Demand{DateTime: t, Forecast: &mw})
+		}
+	}
+	return result
+}
+
+func TestCalculateForecast(t *testing.T) {
+	// A Wednesday
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	prices := history(day, HistoryDays)
+	demands := demandHistory(day, HistoryDays)
+
+	testCases := []struct {
+		name          string
+		model         Model
+		day           time.Time
+		history       []price.Price
+		demand        []demand.Demand
+		expectedHours int
+		expectErr     bool
+	}{
+		{name: "Seasonal naive", model: SeasonalNaive, day: day, history: prices, expectedHours: 24},
+		{name: "Same weekday", model: SameWeekday, day: day, history: prices, expectedHours: 24},
+		{name: "Regression", model: Regression, day: day, history: prices, expectedHours: 24},
+		{name: "Regression with demand", model: RegressionDemand, day: day, history: prices, demand: demands, expectedHours: 24},
+		{name: "Regression ignores the demand", model: Regression, day: day, history: prices, demand: demands, expectedHours: 24},
+		{name: "Clocks go forward", model: Regression, day: time.Date(2024, 3, 31, 0, 0, 0, 0, date.Location), history: history(time.Date(2024, 3, 31, 0, 0, 0, 0, date.Location), HistoryDays), expectedHours: 23},
+		{name: "No history", model: SeasonalNaive, day: day, history: []price.Price{}, expectErr: true},
+		{name: "Too little history for the regression", model: Regression, day: day, history: history(day, 7), expectErr: true},
+		{name: "No demand", model: RegressionDemand, day: day, history: prices, expectErr: true},
+		{name: "No demand for the day", model: RegressionDemand, day: day, history: prices, demand: demandHistory(day.AddDate(0, 0, -1), HistoryDays), expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			forecast, err := CalculateDemandForecast(tc.history, tc.demand, tc.day, tc.model)
+			if tc.expectErr {
+				if !errors.Is(err, ErrInsufficientHistory) {
+					t.Errorf("Expected an insufficient history error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if !forecast.Forecast || forecast.Model != tc.model || forecast.Date != date.ParseToLocalDay(tc.day) {
+				t.Errorf("Expected a %s forecast for %s, but got %+v", tc.model, date.ParseToLocalDay(tc.day), forecast)
+			}
+			if len(forecast.Prices) != tc.expectedHours {
+				t.Fatalf("Expected %d hours, but got %d", tc.expectedHours, len(forecast.Prices))
+			}
+			for _, h := range forecast.Prices {
+				if h.Lower > h.Price || h.Upper < h.Price {
+					t.Errorf("Expected %f to be inside the band %f to %f", h.Price, h.Lower, h.Upper)
+				}
+				// The forecast should be close to the working day shape
+				expected := 0.12 + 0.005*float64(h.DateTime.In(date.Location).Hour())
+				if tc.day == day && math.Abs(h.Price-expected) > 0.01 {
+					t.Errorf("Expected around %f at %s, but got %f", expected, h.DateTime, h.Price)
+				}
+			}
+			if forecast.DayLower > forecast.DayAverage || forecast.DayUpper < forecast.DayAverage {
+				t.Errorf("Expected the day average %f to be inside the band %f to %f", forecast.DayAverage, forecast.DayLower, forecast.DayUpper)
+			}
+		})
+	}
+}
+
+func TestSeasonalNaiveRepeatsTheDayBefore(t *testing.T) {
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	prices := history(day, 2)
+
+	forecast, err := CalculateForecast(prices, day, SeasonalNaive)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	for i, h := range forecast.Prices {
+		if !floatEquals(h.Price, prices[24+i].Price) {
+			t.Errorf("Expected %f at hour %d, but got %f", prices[24+i].Price, i, h.Price)
+		}
+	}
+}
+
+func TestCalculateAccuracy(t *testing.T) {
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	forecasts := []Forecast{
+		{Model: SeasonalNaive, Prices: []HourForecast{
+			{DateTime: day, Price: 0.1, Lower: 0.05, Upper: 0.15},
+			{DateTime: day.Add(time.Hour), Price: 0.2, Lower: 0.18, Upper: 0.22},
+		}},
+		{Model: SeasonalNaive, Prices: []HourForecast{
+			{DateTime: day.AddDate(0, 0, 1), Price: 0.1, Lower: 0.05, Upper: 0.15},
+		}},
+		{Model: Regression, Prices: []HourForecast{
+			{DateTime: day, Price: 0.3, Lower: 0.25, Upper: 0.35},
+		}},
+	}
+	actual := []price.Price{
+		{DateTime: day, Price: 0.12},
+		{DateTime: day.Add(time.Hour), Price: 0.26},
+	}
+
+	accuracy := CalculateAccuracy(SeasonalNaive, forecasts, actual)
+
+	if accuracy.Days != 1 || accuracy.Hours != 2 {
+		t.Errorf("Expected 1 day and 2 hours, but got %d and %d", accuracy.Days, accuracy.Hours)
+	}
+	if !floatEquals(accuracy.MAE, 0.04) {
+		t.Errorf("Expected a MAE of 0.04, but got %f", accuracy.MAE)
+	}
+	if !floatEquals(accuracy.RMSE, math.Sqrt((0.02*0.02+0.06*0.06)/2)) {
+		t.Errorf("Unexpected RMSE %f", accuracy.RMSE)
+	}
+	if !floatEquals(accuracy.Bias, -0.04) {
+		t.Errorf("Expected a bias of -0.04, but got %f", accuracy.Bias)
+	}
+	if !floatEquals(accuracy.Coverage, 0.5) {
+		t.Errorf("Expected a coverage of 0.5, but got %f", accuracy.Coverage)
+	}
+
+	empty := CalculateAccuracy(SameWeekday, forecasts, actual)
+	if empty.Hours != 0 || empty.MAE != 0 {
+		t.Errorf("Expected no accuracy for a model without forecasts, but got %+v", empty)
+	}
+}
+
+func TestLeastSquares(t *testing.T) {
+	xs := [][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}}
+	ys := []float64{1, 3, 5, 7}
+
+	coefficients, ok := leastSquares(xs, ys)
+	if !ok {
+		t.Fatal("Expected a fit")
+	}
+	if !floatEquals(coefficients[0], 1) || !floatEquals(coefficients[1], 2) {
+		t.Errorf("Expected 1 and 2, but got %v", coefficients)
+	}
+
+	if _, ok := leastSquares([][]float64{{1, 2}, {1, 2}, {1, 2}}, []float64{1, 2, 3}); ok {
+		t.Error("Expected collinear inputs to fail")
+	}
+	if _, ok := leastSquares([][]float64{{1, 2}}, []float64{1}); ok {
+		t.Error("Expected too few rows to fail")
+	}
+}
+
+func TestRegressionDemandFollowsTheDemand(t *testing.T) {
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	demands := demandHistory(day, HistoryDays)
+
+	// Prices that depend on the demand of the hour, which the day and week before cannot explain
+	var prices []price.Price
+	for _, d := range demands {
+		if !d.DateTime.Before(date.StartOfDay(day)) {
+			continue
+		}
+		prices = append(prices, price.Price{DateTime: d.DateTime, Price: *d.Forecast / 200000})
+	}
+
+	forecast, err := CalculateDemandForecast(prices, demands, day, RegressionDemand)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	for i, h := range forecast.Prices {
+		expected := *demands[len(demands)-24+i].Forecast / 200000
+		if math.Abs(h.Price-expected) > 1e-6 {
+			t.Errorf("Expected %f at %s, but got %f", expected, h.DateTime, h.Price)
+		}
+	}
+}
+
+func TestRegressionDemandBandUsesTheForecastDemand(t *testing.T) {
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	demands := demandHistory(day, HistoryDays)
+
+	// The real demand of the past days misses the forecast and sets the prices, so the forecast demand can't explain them exactly
+	var prices []price.Price
+	for i, d := range demands {
+		if !d.DateTime.Before(date.StartOfDay(day)) {
+			continue
+		}
+		real := *d.Forecast + 1500*math.Sin(float64(i))
+		demands[i].Real = &real
+		prices = append(prices, price.Price{DateTime: d.DateTime, Price: real / 200000})
+	}
+
+	forecast, err := CalculateDemandForecast(prices, demands, day, RegressionDemand)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if width := forecast.DayUpper - forecast.DayLower; width < 0.005 {
+		t.Errorf("Expected the band to include the demand forecast error, but it is %f wide", width)
+	}
+}
+
+func TestIndexDemandAveragesTheRepeatedHour(t *testing.T) {
+	// The clocks go back at 03:00 on the 29th of October 2023, so 02:00 happens twice
+	first := time.Date(2023, 10, 29, 0, 0, 0, 0, time.UTC)
+	low, high := 20000.0, 22000.0
+	d := []demand.Demand{
+		{DateTime: first, Real: &low},
+		{DateTime: first.Add(time.Hour), Real: &high},
+	}
+
+	index := indexDemand(d, demand.Demand.Best)
+
+	if value := index["2023-10-29"][2]; math.Abs(value-21) > 1e-9 {
+		t.Errorf("Expected the repeated hour to average 21 GW, but got %f", value)
+	}
+}
+
+func TestForecastWithOutput(t *testing.T) {
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	forecast, err := CalculateForecast(history(day, HistoryDays), day, Regression)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	result, err := forecastWithOutput(forecast, price.Output{Unit: price.CentPerKwh, Location: date.Location})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if !floatEquals(result.DayAverage, forecast.DayAverage*100) || !floatEquals(result.DayUpper, forecast.DayUpper*100) {
+		t.Errorf("Expected the day average and band in cents, but got %f and %f", result.DayAverage, result.DayUpper)
+	}
+	for i, h := range result.Prices {
+		original := forecast.Prices[i]
+		if !floatEquals(h.Price, original.Price*100) || !floatEquals(h.Lower, original.Lower*100) || !floatEquals(h.Upper, original.Upper*100) {
+			t.Errorf("Expected the hour in cents, but got %+v", h)
+		}
+		if h.DateTime.Location() != date.Location || !h.DateTime.Equal(original.DateTime) {
+			t.Errorf("Expected the same hour in Madrid, but got %s", h.DateTime)
+		}
+	}
+}
+
+func TestParseModel(t *testing.T) {
+	for _, m := range Models {
+		if parsed, ok := ParseModel(string(m)); !ok || parsed != m {
+			t.Errorf("Expected %s to parse", m)
+		}
+	}
+	if _, ok := ParseModel("arima"); ok {
+		t.Error("Expected an unknown model not to parse")
+	}
+}
+
+func floatEquals(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
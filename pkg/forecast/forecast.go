@@ -0,0 +1,75 @@
+package forecast
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInsufficientHistory = errors.New("not enough price history to forecast")
+
+type Model string
+
+const (
+	// SeasonalNaive repeats the price of the same hour the day before
+	SeasonalNaive Model = "seasonal-naive"
+	// SameWeekday averages the price of the same hour on the same weekday over the previous weeks
+	SameWeekday Model = "same-weekday"
+	// Regression fits a linear regression on the price of the same hour the day and the week before and whether the day is a working day
+	Regression Model = "regression"
+	// RegressionDemand adds the demand of the hour to the regression inputs
+	RegressionDemand Model = "regression-demand"
+)
+
+// Models are the available forecasting models.
+var Models = []Model{SeasonalNaive, SameWeekday, Regression, RegressionDemand}
+
+// ParseModel returns the model with the given name.
+func ParseModel(name string) (Model, bool) {
+	for _, m := range Models {
+		if string(m) == name {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+// HourForecast is the estimated price of an hour with its confidence band.
+type HourForecast struct {
+	DateTime time.Time `bson:"dateTime" json:"dateTime"`
+	Price    float64   `bson:"price" json:"price"`
+	Lower    float64   `bson:"lower" json:"lower"`
+	Upper    float64   `bson:"upper" json:"upper"`
+}
+
+// Forecast is an estimate of a day's hourly prices made before they are published.
+// The band holds the forecast error the model made on the previous days, between the 10th and 90th percentiles.
+type Forecast struct {
+	ID         string         `bson:"_id,omitempty" json:"-"`
+	Date       string         `bson:"date" json:"date"`
+	Model      Model          `bson:"model" json:"model"`
+	Forecast   bool           `bson:"-" json:"forecast"`
+	Confidence float64        `bson:"confidence" json:"confidence"`
+	DayAverage float64        `bson:"dayAverage" json:"dayAverage"`
+	DayLower   float64        `bson:"dayLower" json:"dayLower"`
+	DayUpper   float64        `bson:"dayUpper" json:"dayUpper"`
+	Prices     []HourForecast `bson:"prices" json:"prices"`
+	CreatedAt  time.Time      `bson:"createdAt" json:"createdAt"`
+}
+
+// Accuracy is how close a model's stored forecasts were to the prices once they were published.
+// Coverage is the share of hours whose price fell inside the confidence band.
+type Accuracy struct {
+	Model    Model   `json:"model"`
+	Days     int     `json:"days"`
+	Hours    int     `json:"hours"`
+	MAE      float64 `json:"mae"`
+	RMSE     float64 `json:"rmse"`
+	Bias     float64 `json:"bias"`
+	Coverage float64 `json:"coverage"`
+}
+
+type AccuracyReport struct {
+	Start  string     `json:"start"`
+	End    string     `json:"end"`
+	Models []Accuracy `json:"models"`
+}
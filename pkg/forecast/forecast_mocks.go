@@ -0,0 +1,77 @@
+package forecast
+
+import (
+	"context"
+	"time"
+)
+
+// A mock implementation of Service
+
+type MockForecastService struct {
+	MockGetForecastResult     *[]Forecast
+	MockGetForecastError      *[]error
+	MockRecordForecastsResult *[][]Forecast
+	MockRecordForecastsError  *[]error
+	MockGetAccuracyResult     *[]AccuracyReport
+	MockGetAccuracyError      *[]error
+}
+
+func (m *MockForecastService) GetForecast(ctx context.Context, day time.Time, model Model) (Forecast, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result Forecast
+	if len(*m.MockGetForecastResult) > 0 {
+		result = (*m.MockGetForecastResult)[0]
+		*m.MockGetForecastResult = (*m.MockGetForecastResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetForecastError) > 0 {
+		err = (*m.MockGetForecastError)[0]
+		*m.MockGetForecastError = (*m.MockGetForecastError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockForecastService) RecordForecasts(ctx context.Context, day time.Time) ([]Forecast, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []Forecast
+	if len(*m.MockRecordForecastsResult) > 0 {
+		result = (*m.MockRecordForecastsResult)[0]
+		*m.MockRecordForecastsResult = (*m.MockRecordForecastsResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockRecordForecastsError) > 0 {
+		err = (*m.MockRecordForecastsError)[0]
+		*m.MockRecordForecastsError = (*m.MockRecordForecastsError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockForecastService) GetAccuracy(ctx context.Context, start time.Time, end time.Time) (AccuracyReport, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result AccuracyReport
+	if len(*m.MockGetAccuracyResult) > 0 {
+		result = (*m.MockGetAccuracyResult)[0]
+		*m.MockGetAccuracyResult = (*m.MockGetAccuracyResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetAccuracyError) > 0 {
+		err = (*m.MockGetAccuracyError)[0]
+		*m.MockGetAccuracyError = (*m.MockGetAccuracyError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
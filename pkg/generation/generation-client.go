@@ -0,0 +1,8 @@
+package generation
+
+import "time"
+
+type Client interface {
+	GetGeneration(t time.Time) ([]Generation, bool, error)
+	GetEmissions(t time.Time) ([]Emission, bool, error)
+}
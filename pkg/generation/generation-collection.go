@@ -0,0 +1,116 @@
+package generation
+
+import (
+	"context"
+	"electricity-prices/pkg/db"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+)
+
+type Collection interface {
+	db.Collection[Generation]
+	UpsertMany(ctx context.Context, documents []Generation) error
+	GetLatestGeneration(ctx context.Context) (Generation, bool, error)
+}
+
+type ColReceiver struct {
+	Col *mongo.Collection
+}
+
+func (r ColReceiver) FindOne(ctx context.Context, filter interface{}) (Generation, error) {
+	var g Generation
+	err := r.Col.FindOne(ctx, filter).Decode(&g)
+
+	if err != nil {
+		return Generation{}, err
+	}
+
+	return g, err
+}
+
+func (r ColReceiver) Find(ctx context.Context, filter interface{}) ([]Generation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "dateTime", Value: 1}})
+	cur, err := r.Col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(cur *mongo.Cursor, ctx context.Context) {
+		err := cur.Close(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(cur, ctx)
+
+	var generation = make([]Generation, 0)
+
+	for cur.Next(ctx) {
+		var g Generation
+		err := cur.Decode(&g)
+		if err != nil {
+			log.Println("Error decoding generation:", err)
+			continue
+		}
+		generation = append(generation, g)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return generation, nil
+}
+
+func (r ColReceiver) InsertMany(ctx context.Context, documents []Generation) error {
+	var documentsInterface []interface{}
+	for _, doc := range documents {
+		documentsInterface = append(documentsInterface, doc)
+	}
+
+	_, err := r.Col.InsertMany(ctx, documentsInterface)
+	return err
+}
+
+// UpsertMany stores the generation, replacing any existing generation for the same hour.
+// The current day is only partly published, so its hours are stored again as they are filled in.
+func (r ColReceiver) UpsertMany(ctx context.Context, documents []Generation) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(documents))
+	for i, doc := range documents {
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"dateTime": doc.DateTime}).
+			SetReplacement(doc).
+			SetUpsert(true)
+	}
+
+	_, err := r.Col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r ColReceiver) Aggregate(ctx context.Context, pipeline interface{}) (*mongo.Cursor, error) {
+	cursor, err := r.Col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor, nil
+}
+
+// GetLatestGeneration gets the latest stored hour.
+// It returns a boolean indicating if nothing was found.
+func (r ColReceiver) GetLatestGeneration(ctx context.Context) (Generation, bool, error) {
+	var g Generation
+	err := r.Col.FindOne(ctx, bson.M{}, options.FindOne().SetSort(bson.M{"dateTime": -1})).Decode(&g)
+	if err == mongo.ErrNoDocuments {
+		return Generation{}, true, nil
+	}
+	if err != nil {
+		return Generation{}, false, err
+	}
+	return g, false, nil
+}
@@ -0,0 +1,135 @@
+package generation
+
+import (
+	"electricity-prices/pkg/api"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultWindowHours = 3
+
+// Handler serves the generation endpoints. Taxes is optional and is needed to return prices with taxes.
+type Handler struct {
+	GenerationService Service
+	Taxes             price.TaxSchedule
+}
+
+// GetGridHours @Summary Get generation mix
+// @Description Returns the renewable share and carbon intensity of the peninsular generation for each hour of the date, alongside the price. Defaults to today.
+// @Description The generation is published during the day, so today only has the hours so far. The carbon intensity is in grams of CO2 equivalent per kWh.
+// @Tags Generation
+// @ID get-generation
+// @Produce  json
+// @Param date query string false "Date in format yyyy-MM-dd"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Success 200 {array} generation.GridHour
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /generation [get]
+func (h *Handler) GetGridHours(c *gin.Context) {
+
+	// Get the date string from the request
+	dateStr := c.DefaultQuery("date", time.Now().Format("2006-01-02")) // Default to today if not provided
+
+	// Parse the date string
+	d, err := date.ParseDate(dateStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := price.ParseOutput(c.Query("unit"), c.Query("taxes"), c.Query("timezone"), h.Taxes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	hours, err := h.GenerationService.GetGridHours(ctx, d)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	hours, err = gridHoursWithOutput(hours, output)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, hours)
+}
+
+// GetGreenestWindow @Summary Get greenest window
+// @Description Returns the consecutive hours of the date with the lowest average carbon intensity, for running appliances when emissions are lowest rather than when prices are. Defaults to the 3 hours of today.
+// @Tags Generation
+// @ID get-greenest-window
+// @Produce  json
+// @Param date query string false "Date in format yyyy-MM-dd"
+// @Param hours query int false "Length of the window in hours, between 1 and 24"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Success 200 {object} generation.Window
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /generation/greenest [get]
+func (h *Handler) GetGreenestWindow(c *gin.Context) {
+
+	// Get the date string from the request
+	dateStr := c.DefaultQuery("date", time.Now().Format("2006-01-02")) // Default to today if not provided
+
+	// Parse the date string
+	d, err := date.ParseDate(dateStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+
+	// Parse the window length
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", strconv.Itoa(defaultWindowHours)))
+	if err != nil || hours < 1 || hours > 24 {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Hours must be a number between 1 and 24."})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := price.ParseOutput(c.Query("unit"), c.Query("taxes"), c.Query("timezone"), h.Taxes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	window, err := h.GenerationService.GetGreenestWindow(ctx, d, hours)
+	if errors.Is(err, ErrNoGeneration) {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Message: "Not enough generation data for the date."})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	window, err = windowWithOutput(window, output)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, window)
+}
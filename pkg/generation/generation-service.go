@@ -0,0 +1,76 @@
+package generation
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+type Service interface {
+	SaveGeneration(ctx context.Context, generation []Generation) error
+	GetGeneration(ctx context.Context, start time.Time, end time.Time) ([]Generation, error)
+	GetLatestGeneration(ctx context.Context) (Generation, bool, error)
+	GetGridHours(ctx context.Context, day time.Time) ([]GridHour, error)
+	GetGreenestWindow(ctx context.Context, day time.Time, hours int) (Window, error)
+}
+
+// Receiver implements the Service using the generation in the Collection and the prices in the PriceService.
+type Receiver struct {
+	Collection   Collection
+	PriceService price.Service
+}
+
+// SaveGeneration stores the generation, replacing any stored for the same hours.
+func (r *Receiver) SaveGeneration(ctx context.Context, generation []Generation) error {
+	return r.Collection.UpsertMany(ctx, generation)
+}
+
+// GetGeneration gets the stored generation for the hours between start and end (inclusive).
+func (r *Receiver) GetGeneration(ctx context.Context, start time.Time, end time.Time) ([]Generation, error) {
+	return r.Collection.Find(ctx, bson.M{
+		"dateTime": bson.M{
+			"$gte": start,
+			"$lte": end,
+		},
+	})
+}
+
+// GetLatestGeneration returns the latest stored hour.
+// It returns a boolean indicating if nothing was found.
+func (r *Receiver) GetLatestGeneration(ctx context.Context) (Generation, bool, error) {
+	return r.Collection.GetLatestGeneration(ctx)
+}
+
+// GetGridHours gets the renewable share and carbon intensity of each hour of the day alongside its price.
+func (r *Receiver) GetGridHours(ctx context.Context, day time.Time) ([]GridHour, error) {
+	start := date.StartOfDay(day)
+	end := start.AddDate(0, 0, 1).Add(-time.Second)
+
+	generation, err := r.GetGeneration(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := r.PriceService.GetPrices(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return CalculateGridHours(generation, prices), nil
+}
+
+// GetGreenestWindow finds the consecutive hours of the day with the lowest carbon intensity.
+func (r *Receiver) GetGreenestWindow(ctx context.Context, day time.Time, hours int) (Window, error) {
+	gridHours, err := r.GetGridHours(ctx, day)
+	if err != nil {
+		return Window{}, err
+	}
+
+	window, found := FindGreenestWindow(gridHours, hours)
+	if !found {
+		return Window{}, ErrNoGeneration
+	}
+	return window, nil
+}
@@ -0,0 +1,95 @@
+package generation
+
+import (
+	"context"
+	"electricity-prices/pkg/price"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetGridHours(t *testing.T) {
+	ctx := context.Background()
+	generation := hourlyGeneration(day, []float64{500, 600}, []float64{200, 100})
+
+	tests := []struct {
+		name          string
+		generation    []Generation
+		generationErr error
+		prices        []price.Price
+		pricesErr     error
+		expectedHours int
+		expectError   bool
+	}{
+		{"Hours", generation, nil, []price.Price{{DateTime: day, Price: 0.1}}, nil, 2, false},
+		{"No generation", []Generation{}, nil, []price.Price{}, nil, 0, false},
+		{"Generation error", nil, errors.New("error"), nil, nil, 0, true},
+		{"Prices error", generation, nil, nil, errors.New("error"), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockFindResult: &[][]Generation{tt.generation},
+				MockFindErr:    &[]error{tt.generationErr},
+			}
+			mockPriceService := &price.MockPriceService{
+				MockGetPricesResult: &[][]price.Price{tt.prices},
+				MockGetPricesError:  &[]error{tt.pricesErr},
+			}
+			service := &Receiver{Collection: mockCollection, PriceService: mockPriceService}
+
+			hours, err := service.GetGridHours(ctx, day)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, hours, tt.expectedHours)
+		})
+	}
+}
+
+func TestGetGreenestWindow(t *testing.T) {
+	ctx := context.Background()
+	generation := hourlyGeneration(day, []float64{500, 600, 900}, []float64{200, 100, 20})
+
+	tests := []struct {
+		name          string
+		generation    []Generation
+		hours         int
+		expectedStart time.Time
+		expectedErr   error
+	}{
+		{"Window", generation, 2, day.Add(time.Hour), nil},
+		{"Not enough hours", generation, 4, time.Time{}, ErrNoGeneration},
+		{"No generation", []Generation{}, 1, time.Time{}, ErrNoGeneration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockFindResult: &[][]Generation{tt.generation},
+				MockFindErr:    &[]error{nil},
+			}
+			mockPriceService := &price.MockPriceService{
+				MockGetPricesResult: &[][]price.Price{{}},
+				MockGetPricesError:  &[]error{nil},
+			}
+			service := &Receiver{Collection: mockCollection, PriceService: mockPriceService}
+
+			window, err := service.GetGreenestWindow(ctx, day, tt.hours)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.expectedStart.Equal(window.Start))
+			assert.Equal(t, tt.hours, window.Hours)
+			assert.Nil(t, window.Price)
+		})
+	}
+}
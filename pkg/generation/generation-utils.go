@@ -0,0 +1,142 @@
+package generation
+
+import (
+	"electricity-prices/pkg/price"
+	"sort"
+	"time"
+)
+
+// MergeEmissions
+// Add the emissions to the generation of the same hour. Hours without emissions are left unchanged.
+func MergeEmissions(generation []Generation, emissions []Emission) []Generation {
+	byHour := make(map[int64]float64, len(emissions))
+	for _, e := range emissions {
+		byHour[e.DateTime.Unix()] = e.Emissions
+	}
+
+	result := make([]Generation, len(generation))
+	for i, g := range generation {
+		if e, ok := byHour[g.DateTime.Unix()]; ok {
+			g.Emissions = e
+		}
+		result[i] = g
+	}
+	return result
+}
+
+// CalculateGridHours
+// Combine the generation of each hour with its price, in time order.
+func CalculateGridHours(generation []Generation, prices []price.Price) []GridHour {
+	byHour := make(map[int64]float64, len(prices))
+	for _, p := range prices {
+		byHour[p.DateTime.Unix()] = p.Price
+	}
+
+	hours := make([]GridHour, 0, len(generation))
+	for _, g := range generation {
+		hour := GridHour{
+			DateTime:        g.DateTime,
+			Generation:      g.Total(),
+			RenewableShare:  g.RenewableShare(),
+			CarbonIntensity: g.CarbonIntensity(),
+		}
+		if p, ok := byHour[g.DateTime.Unix()]; ok {
+			hour.Price = &p
+		}
+		hours = append(hours, hour)
+	}
+	sort.Slice(hours, func(i, j int) bool { return hours[i].DateTime.Before(hours[j].DateTime) })
+	return hours
+}
+
+// FindGreenestWindow
+// Find the consecutive hours with the lowest average carbon intensity, preferring the earliest on a tie.
+// The hours must be in time order. Returns false when there are not enough consecutive hours.
+func FindGreenestWindow(hours []GridHour, length int) (Window, bool) {
+	var best Window
+	found := false
+	for i := 0; i+length <= len(hours); i++ {
+		window := hours[i : i+length]
+		if !consecutive(window) {
+			continue
+		}
+		w := toWindow(window)
+		if !found || w.CarbonIntensity < best.CarbonIntensity {
+			best = w
+			found = true
+		}
+	}
+	return best, found
+}
+
+// consecutive
+// Check that each hour follows the one before.
+func consecutive(hours []GridHour) bool {
+	for i := 1; i < len(hours); i++ {
+		if hours[i].DateTime.Sub(hours[i-1].DateTime) != time.Hour {
+			return false
+		}
+	}
+	return true
+}
+
+// toWindow
+// Average the hours into a window. The price is only set when every hour has one.
+func toWindow(hours []GridHour) Window {
+	w := Window{
+		Start: hours[0].DateTime,
+		End:   hours[len(hours)-1].DateTime.Add(time.Hour),
+		Hours: len(hours),
+	}
+
+	pricesSum := 0.0
+	priced := 0
+	for _, h := range hours {
+		w.CarbonIntensity += h.CarbonIntensity / float64(len(hours))
+		w.RenewableShare += h.RenewableShare / float64(len(hours))
+		if h.Price != nil {
+			pricesSum += *h.Price
+			priced++
+		}
+	}
+	if priced == len(hours) {
+		avg := pricesSum / float64(priced)
+		w.Price = &avg
+	}
+	return w
+}
+
+// gridHoursWithOutput
+// Convert the price of each hour to the output unit and its time to the output time zone.
+func gridHoursWithOutput(hours []GridHour, output price.Output) ([]GridHour, error) {
+	result := make([]GridHour, len(hours))
+	for i, h := range hours {
+		if h.Price != nil {
+			factor, err := output.Factor(h.DateTime)
+			if err != nil {
+				return nil, err
+			}
+			p := *h.Price * factor
+			h.Price = &p
+		}
+		h.DateTime = output.Time(h.DateTime)
+		result[i] = h
+	}
+	return result, nil
+}
+
+// windowWithOutput
+// Convert the average price of the window to the output unit and its times to the output time zone.
+func windowWithOutput(window Window, output price.Output) (Window, error) {
+	if window.Price != nil {
+		factor, err := output.Factor(window.Start)
+		if err != nil {
+			return Window{}, err
+		}
+		p := *window.Price * factor
+		window.Price = &p
+	}
+	window.Start = output.Time(window.Start)
+	window.End = output.Time(window.End)
+	return window, nil
+}
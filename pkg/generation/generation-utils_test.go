@@ -0,0 +1,161 @@
+package generation
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"math"
+	"testing"
+	"time"
+)
+
+var day = time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+
+// hourlyGeneration builds consecutive hours of generation from the renewable MWh and tonnes of CO2 of each hour.
+// The total generation is always 1000 MWh.
+func hourlyGeneration(start time.Time, renewable []float64, emissions []float64) []Generation {
+	generation := make([]Generation, len(renewable))
+	for i := range renewable {
+		generation[i] = Generation{
+			DateTime:     start.Add(time.Duration(i) * time.Hour),
+			Renewable:    renewable[i],
+			NonRenewable: 1000 - renewable[i],
+			Emissions:    emissions[i],
+		}
+	}
+	return generation
+}
+
+func TestGenerationMetrics(t *testing.T) {
+	testCases := []struct {
+		name              string
+		generation        Generation
+		expectedShare     float64
+		expectedIntensity float64
+	}{
+		{"Mixed", Generation{Renewable: 600, NonRenewable: 400, Emissions: 100}, 0.6, 100},
+		{"All renewable", Generation{Renewable: 1000}, 1, 0},
+		{"Nothing generated", Generation{}, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if share := tc.generation.RenewableShare(); math.Abs(share-tc.expectedShare) > 1e-9 {
+				t.Errorf("Expected a renewable share of %f, but got %f", tc.expectedShare, share)
+			}
+			if intensity := tc.generation.CarbonIntensity(); math.Abs(intensity-tc.expectedIntensity) > 1e-9 {
+				t.Errorf("Expected a carbon intensity of %f, but got %f", tc.expectedIntensity, intensity)
+			}
+		})
+	}
+}
+
+func TestMergeEmissions(t *testing.T) {
+	generation := hourlyGeneration(day, []float64{500, 600, 700}, []float64{0, 0, 0})
+	emissions := []Emission{
+		{DateTime: day, Emissions: 150},
+		{DateTime: day.Add(2 * time.Hour).UTC(), Emissions: 80},
+	}
+
+	result := MergeEmissions(generation, emissions)
+
+	expected := []float64{150, 0, 80}
+	for i := range expected {
+		if result[i].Emissions != expected[i] {
+			t.Errorf("Expected %f tonnes at hour %d, but got %f", expected[i], i, result[i].Emissions)
+		}
+	}
+	if generation[0].Emissions != 0 {
+		t.Errorf("Expected the generation to be left unchanged")
+	}
+}
+
+func TestCalculateGridHours(t *testing.T) {
+	generation := hourlyGeneration(day, []float64{500, 600}, []float64{200, 100})
+	prices := []price.Price{{DateTime: day.Add(time.Hour), Price: 0.12}}
+
+	hours := CalculateGridHours(generation, prices)
+
+	if len(hours) != 2 {
+		t.Fatalf("Expected 2 hours, but got %d", len(hours))
+	}
+	if hours[0].Price != nil {
+		t.Errorf("Expected no price for the first hour, but got %f", *hours[0].Price)
+	}
+	if hours[1].Price == nil || *hours[1].Price != 0.12 {
+		t.Errorf("Expected a price of 0.12 for the second hour")
+	}
+	if hours[0].Generation != 1000 || hours[0].RenewableShare != 0.5 || hours[0].CarbonIntensity != 200 {
+		t.Errorf("Unexpected first hour %+v", hours[0])
+	}
+}
+
+func TestFindGreenestWindow(t *testing.T) {
+	generation := hourlyGeneration(day, []float64{300, 500, 800, 900, 600, 400}, []float64{300, 200, 50, 40, 150, 250})
+	prices := make([]price.Price, len(generation))
+	for i, g := range generation {
+		prices[i] = price.Price{DateTime: g.DateTime, Price: 0.1 + 0.01*float64(i)}
+	}
+	hours := CalculateGridHours(generation, prices)
+
+	// Leave a gap after the fourth hour
+	gapped := append(append([]GridHour{}, hours[:3]...), hours[4:]...)
+
+	testCases := []struct {
+		name              string
+		hours             []GridHour
+		length            int
+		expectedFound     bool
+		expectedStart     time.Time
+		expectedIntensity float64
+	}{
+		{"Single hour", hours, 1, true, day.Add(3 * time.Hour), 40},
+		{"Two hours", hours, 2, true, day.Add(2 * time.Hour), 45},
+		{"Whole day", hours, 6, true, day, 165},
+		{"Longer than the day", hours, 7, false, time.Time{}, 0},
+		{"Skips windows with a gap", gapped, 3, true, day, 183.33333333333334},
+		{"No hours", []GridHour{}, 1, false, time.Time{}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			window, found := FindGreenestWindow(tc.hours, tc.length)
+			if found != tc.expectedFound {
+				t.Fatalf("Expected found to be %t, but got %t", tc.expectedFound, found)
+			}
+			if !found {
+				return
+			}
+			if !window.Start.Equal(tc.expectedStart) {
+				t.Errorf("Expected the window to start at %s, but got %s", tc.expectedStart, window.Start)
+			}
+			if !window.End.Equal(tc.expectedStart.Add(time.Duration(tc.length) * time.Hour)) {
+				t.Errorf("Expected the window to last %d hours, but it ends at %s", tc.length, window.End)
+			}
+			if math.Abs(window.CarbonIntensity-tc.expectedIntensity) > 1e-9 {
+				t.Errorf("Expected a carbon intensity of %f, but got %f", tc.expectedIntensity, window.CarbonIntensity)
+			}
+			if window.Price == nil {
+				t.Errorf("Expected the window to have a price")
+			}
+		})
+	}
+}
+
+func TestGridHoursWithOutput(t *testing.T) {
+	p := 0.1
+	hours := []GridHour{{DateTime: day, Price: &p, CarbonIntensity: 150}, {DateTime: day.Add(time.Hour), CarbonIntensity: 120}}
+
+	result, err := gridHoursWithOutput(hours, price.Output{Unit: price.CentPerKwh, Location: date.Location})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if result[0].Price == nil || math.Abs(*result[0].Price-10) > 1e-9 || result[0].CarbonIntensity != 150 {
+		t.Errorf("Expected only the price to be in cents, but got %+v", result[0])
+	}
+	if result[1].Price != nil {
+		t.Errorf("Expected the hour without a price to stay without one")
+	}
+	if result[0].DateTime.Location() != date.Location || *hours[0].Price != 0.1 {
+		t.Errorf("Expected the time in Madrid and the original hours unchanged")
+	}
+}
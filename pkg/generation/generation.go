@@ -0,0 +1,66 @@
+package generation
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrNoGeneration = errors.New("no generation data found")
+
+// Generation is the electricity generated in the peninsular system during an hour, in MWh, by technology.
+// Emissions are the tonnes of CO2 equivalent emitted generating it.
+type Generation struct {
+	DateTime     time.Time          `bson:"dateTime" json:"dateTime"`
+	Renewable    float64            `bson:"renewable" json:"renewable"`
+	NonRenewable float64            `bson:"nonRenewable" json:"nonRenewable"`
+	Technologies map[string]float64 `bson:"technologies" json:"technologies"`
+	Emissions    float64            `bson:"emissions" json:"emissions"`
+}
+
+// Total is the MWh generated by all technologies.
+func (g Generation) Total() float64 {
+	return g.Renewable + g.NonRenewable
+}
+
+// RenewableShare is the share of the generation from renewable technologies, between 0 and 1.
+func (g Generation) RenewableShare() float64 {
+	if g.Total() <= 0 {
+		return 0.0
+	}
+	return g.Renewable / g.Total()
+}
+
+// CarbonIntensity is the grams of CO2 equivalent emitted for each kWh generated.
+func (g Generation) CarbonIntensity() float64 {
+	if g.Total() <= 0 {
+		return 0.0
+	}
+	// Tonnes per MWh is the same as kilograms per kWh
+	return g.Emissions / g.Total() * 1000
+}
+
+// Emission is the tonnes of CO2 equivalent emitted during an hour.
+type Emission struct {
+	DateTime  time.Time
+	Emissions float64
+}
+
+// GridHour is the renewable share and carbon intensity of an hour alongside its price.
+// The price is omitted when it is not available for the hour.
+type GridHour struct {
+	DateTime        time.Time `json:"dateTime"`
+	Price           *float64  `json:"price,omitempty"`
+	Generation      float64   `json:"generation"`
+	RenewableShare  float64   `json:"renewableShare"`
+	CarbonIntensity float64   `json:"carbonIntensity"`
+}
+
+// Window is a run of consecutive hours with their average carbon intensity, renewable share and price.
+type Window struct {
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	Hours           int       `json:"hours"`
+	CarbonIntensity float64   `json:"carbonIntensity"`
+	RenewableShare  float64   `json:"renewableShare"`
+	Price           *float64  `json:"price,omitempty"`
+}
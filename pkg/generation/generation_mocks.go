@@ -0,0 +1,177 @@
+package generation
+
+import (
+	"context"
+	"time"
+)
+
+// A mock implementation of Service
+
+type MockGenerationService struct {
+	MockSaveGeneration              *[][]Generation
+	MockSaveGenerationError         *[]error
+	MockGetGenerationResult         *[][]Generation
+	MockGetGenerationError          *[]error
+	MockGetLatestGenerationResult   *[]Generation
+	MockGetLatestGenerationNotFound *[]bool
+	MockGetLatestGenerationError    *[]error
+	MockGetGridHoursResult          *[][]GridHour
+	MockGetGridHoursError           *[]error
+	MockGetGreenestWindowResult     *[]Window
+	MockGetGreenestWindowError      *[]error
+}
+
+func (m *MockGenerationService) SaveGeneration(ctx context.Context, generation []Generation) error {
+	// Record the generation that was saved
+	*m.MockSaveGeneration = append(*m.MockSaveGeneration, generation)
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockSaveGenerationError) > 0 {
+		err = (*m.MockSaveGenerationError)[0]
+		*m.MockSaveGenerationError = (*m.MockSaveGenerationError)[1:]
+	} else {
+		err = nil
+	}
+
+	return err
+}
+
+func (m *MockGenerationService) GetLatestGeneration(ctx context.Context) (Generation, bool, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result Generation
+	if len(*m.MockGetLatestGenerationResult) > 0 {
+		result = (*m.MockGetLatestGenerationResult)[0]
+		*m.MockGetLatestGenerationResult = (*m.MockGetLatestGenerationResult)[1:]
+	}
+
+	var notFound bool
+	if len(*m.MockGetLatestGenerationNotFound) > 0 {
+		notFound = (*m.MockGetLatestGenerationNotFound)[0]
+		*m.MockGetLatestGenerationNotFound = (*m.MockGetLatestGenerationNotFound)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetLatestGenerationError) > 0 {
+		err = (*m.MockGetLatestGenerationError)[0]
+		*m.MockGetLatestGenerationError = (*m.MockGetLatestGenerationError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, notFound, err
+}
+
+func (m *MockGenerationService) GetGeneration(ctx context.Context, start time.Time, end time.Time) ([]Generation, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []Generation
+	if len(*m.MockGetGenerationResult) > 0 {
+		result = (*m.MockGetGenerationResult)[0]
+		*m.MockGetGenerationResult = (*m.MockGetGenerationResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetGenerationError) > 0 {
+		err = (*m.MockGetGenerationError)[0]
+		*m.MockGetGenerationError = (*m.MockGetGenerationError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockGenerationService) GetGridHours(ctx context.Context, day time.Time) ([]GridHour, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []GridHour
+	if len(*m.MockGetGridHoursResult) > 0 {
+		result = (*m.MockGetGridHoursResult)[0]
+		*m.MockGetGridHoursResult = (*m.MockGetGridHoursResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetGridHoursError) > 0 {
+		err = (*m.MockGetGridHoursError)[0]
+		*m.MockGetGridHoursError = (*m.MockGetGridHoursError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockGenerationService) GetGreenestWindow(ctx context.Context, day time.Time, hours int) (Window, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result Window
+	if len(*m.MockGetGreenestWindowResult) > 0 {
+		result = (*m.MockGetGreenestWindowResult)[0]
+		*m.MockGetGreenestWindowResult = (*m.MockGetGreenestWindowResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetGreenestWindowError) > 0 {
+		err = (*m.MockGetGreenestWindowError)[0]
+		*m.MockGetGreenestWindowError = (*m.MockGetGreenestWindowError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+// A mock implementation of Client
+
+type MockGenerationClient struct {
+	MockGetGenerationResult *[][]Generation
+	MockGetGenerationSynced *[]bool
+	MockGetGenerationError  *[]error
+	MockGetEmissionsResult  *[][]Emission
+	MockGetEmissionsError   *[]error
+}
+
+func (m *MockGenerationClient) GetGeneration(t time.Time) ([]Generation, bool, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []Generation
+	if len(*m.MockGetGenerationResult) > 0 {
+		result = (*m.MockGetGenerationResult)[0]
+		*m.MockGetGenerationResult = (*m.MockGetGenerationResult)[1:]
+	}
+
+	// Get the first element of the synced array and remove it from the array, return false if the array is empty
+	var synced bool
+	if len(*m.MockGetGenerationSynced) > 0 {
+		synced = (*m.MockGetGenerationSynced)[0]
+		*m.MockGetGenerationSynced = (*m.MockGetGenerationSynced)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetGenerationError) > 0 {
+		err = (*m.MockGetGenerationError)[0]
+		*m.MockGetGenerationError = (*m.MockGetGenerationError)[1:]
+	}
+
+	return result, synced, err
+}
+
+func (m *MockGenerationClient) GetEmissions(t time.Time) ([]Emission, bool, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []Emission
+	if len(*m.MockGetEmissionsResult) > 0 {
+		result = (*m.MockGetEmissionsResult)[0]
+		*m.MockGetEmissionsResult = (*m.MockGetEmissionsResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetEmissionsError) > 0 {
+		err = (*m.MockGetEmissionsError)[0]
+		*m.MockGetEmissionsError = (*m.MockGetEmissionsError)[1:]
+	}
+
+	return result, false, err
+}
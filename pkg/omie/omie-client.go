@@ -0,0 +1,146 @@
+package omie
+
+import (
+	"bytes"
+	"electricity-prices/pkg/price"
+	"electricity-prices/pkg/web"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	urlTemplate           = "https://www.omie.es/es/file-download?parents%%5B0%%5D=marginalpdbc&filename=marginalpdbc_%s.1"
+	intradayUrlTemplate   = "https://www.omie.es/es/file-download?parents%%5B0%%5D=marginalpibc&filename=marginalpibc_%s%02d.1"
+	continuousUrlTemplate = "https://www.omie.es/es/file-download?parents%%5B0%%5D=precios_pibcic&filename=precios_pibcic_%s.1"
+)
+
+// Client gets the day-ahead marginal prices for a zone from the files published by OMIE.
+type Client struct {
+	Http web.HTTPClient
+	Zone Zone
+}
+
+// GetPrices returns the day-ahead marginal prices for the given date from OMIE
+func (c *Client) GetPrices(t time.Time) ([]price.Price, bool, error) {
+	body, found, err := fetch(c.Http, fmt.Sprintf(urlTemplate, t.Format("20060102")))
+	if err != nil {
+		return nil, false, err
+	}
+
+	var prices []price.Price
+	if found {
+		prices, err = ParseMarginalPrices(bytes.NewReader(body), c.Zone)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return checkPrices(t, prices, "OMIE")
+}
+
+// IntradayClient gets the marginal prices of every intraday auction session for a zone from the files published by OMIE.
+type IntradayClient struct {
+	Http web.HTTPClient
+	Zone Zone
+}
+
+// GetPrices returns the prices of the intraday sessions for the given date that have been held so far.
+// Each hour has a price for every session that covered it.
+func (c *IntradayClient) GetPrices(t time.Time) ([]price.Price, bool, error) {
+	var prices []price.Price
+	for session := 1; session <= price.IntradaySessions; session++ {
+		body, found, err := fetch(c.Http, fmt.Sprintf(intradayUrlTemplate, t.Format("20060102"), session))
+		if err != nil {
+			return nil, false, err
+		}
+		if !found {
+			continue
+		}
+
+		sessionPrices, err := ParseIntradayPrices(bytes.NewReader(body), c.Zone, session)
+		if err != nil {
+			return nil, false, err
+		}
+		prices = append(prices, sessionPrices...)
+	}
+
+	return checkPrices(t, prices, "OMIE intraday")
+}
+
+// ContinuousClient gets the prices of the continuous intraday market (MIC) for a zone from the files published by OMIE.
+type ContinuousClient struct {
+	Http web.HTTPClient
+	Zone Zone
+}
+
+// GetPrices returns the weighted average price of each hour traded on the continuous market for the given date.
+func (c *ContinuousClient) GetPrices(t time.Time) ([]price.Price, bool, error) {
+	body, found, err := fetch(c.Http, fmt.Sprintf(continuousUrlTemplate, t.Format("20060102")))
+	if err != nil {
+		return nil, false, err
+	}
+
+	var prices []price.Price
+	if found {
+		prices, err = ParseContinuousPrices(bytes.NewReader(body), c.Zone)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return checkPrices(t, prices, "OMIE continuous")
+}
+
+// fetch
+// Download an OMIE file. It returns false if the file has not been published yet.
+func fetch(client web.HTTPClient, url string) ([]byte, bool, error) {
+	// Call to endpoint
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Fatalf("Error occurred while closing response body: %s", err)
+		}
+	}(resp.Body)
+
+	// Read the response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// A missing file means the prices have not been published yet
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	// Check if the status code indicates success
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, false, fmt.Errorf("server responded with a non-successful status code: %d", resp.StatusCode)
+	}
+
+	return body, true, nil
+}
+
+// checkPrices
+// Return the prices, or if there are none whether the date is in the future and so synced.
+func checkPrices(t time.Time, prices []price.Price, source string) ([]price.Price, bool, error) {
+	if len(prices) == 0 {
+		// Parse date to day string
+		day := t.Format("2006-01-02")
+		log.Printf("No %s prices for %s", source, day)
+		// If the date is in the future, return synced as true
+		if t.After(time.Now()) {
+			return nil, true, nil
+		}
+		return nil, false, fmt.Errorf("no %s prices for %s", source, day)
+	}
+
+	return prices, false, nil
+}
@@ -0,0 +1,211 @@
+package omie
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/testutils"
+	"electricity-prices/pkg/web/testdata"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetPrices(t *testing.T) {
+	tests := []struct {
+		name               string
+		testDate           time.Time
+		mockResponse       *http.Response
+		mockError          error
+		expectedResultSize int
+		expectSynced       bool
+		expectingError     bool
+	}{
+		{
+			name:     "Valid response",
+			testDate: time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			mockResponse: &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(
+				testutils.ReadJsonStringFromFile("testdata/marginalpdbc_20231129.1"))},
+			expectedResultSize: 24,
+		},
+		{
+			name:         "Not published - in future",
+			testDate:     time.Now().AddDate(0, 0, 1),
+			mockResponse: &http.Response{StatusCode: 404, Body: testdata.NewMockReadCloser("")},
+			expectSynced: true,
+		},
+		{
+			name:           "Not published - in past",
+			testDate:       time.Date(2000, 10, 11, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 404, Body: testdata.NewMockReadCloser("")},
+			expectingError: true,
+		},
+		{
+			name:     "Invalid file",
+			testDate: time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			mockResponse: &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(
+				testutils.ReadJsonStringFromFile("testdata/marginalpdbc_invalid.1"))},
+			expectingError: true,
+		},
+		{
+			name:           "500 error",
+			testDate:       time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 500, Body: testdata.NewMockReadCloser("")},
+			expectingError: true,
+		},
+		{
+			name:           "Error calling to API",
+			testDate:       time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			mockError:      errors.New("mock error"),
+			expectingError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := Client{Http: &testdata.MockHTTPClient{
+				MockResp: test.mockResponse,
+				MockErr:  test.mockError,
+			}, Zone: Spain}
+
+			prices, synced, err := client.GetPrices(test.testDate)
+
+			if test.expectingError && err == nil {
+				t.Errorf("Expected an error but got nil")
+			}
+			if !test.expectingError && err != nil {
+				t.Errorf("Expected no error but got %s", err)
+			}
+			if synced != test.expectSynced {
+				t.Errorf("Expected synced to be %t but got %t", test.expectSynced, synced)
+			}
+			if len(prices) != test.expectedResultSize {
+				t.Errorf("Expected %d prices but got %d", test.expectedResultSize, len(prices))
+			}
+		})
+	}
+}
+
+func TestGetIntradayPrices(t *testing.T) {
+	tests := []struct {
+		name               string
+		testDate           time.Time
+		mockResponse       *http.Response
+		mockError          error
+		expectedResultSize int
+		expectSynced       bool
+		expectingError     bool
+	}{
+		{
+			// The mock body can only be read once, so the later sessions are empty
+			name:     "Valid response",
+			testDate: time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse: &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(
+				testutils.ReadJsonStringFromFile("testdata/marginalpibc_2024051501.1"))},
+			expectedResultSize: 24,
+		},
+		{
+			name:         "Not published - in future",
+			testDate:     time.Now().AddDate(0, 0, 1),
+			mockResponse: &http.Response{StatusCode: 404, Body: testdata.NewMockReadCloser("")},
+			expectSynced: true,
+		},
+		{
+			name:           "Not published - in past",
+			testDate:       time.Date(2000, 10, 11, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 404, Body: testdata.NewMockReadCloser("")},
+			expectingError: true,
+		},
+		{
+			name:           "500 error",
+			testDate:       time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 500, Body: testdata.NewMockReadCloser("")},
+			expectingError: true,
+		},
+		{
+			name:           "Error calling to API",
+			testDate:       time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockError:      errors.New("mock error"),
+			expectingError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := IntradayClient{Http: &testdata.MockHTTPClient{
+				MockResp: test.mockResponse,
+				MockErr:  test.mockError,
+			}, Zone: Spain}
+
+			prices, synced, err := client.GetPrices(test.testDate)
+
+			if test.expectingError && err == nil {
+				t.Errorf("Expected an error but got nil")
+			}
+			if !test.expectingError && err != nil {
+				t.Errorf("Expected no error but got %s", err)
+			}
+			if synced != test.expectSynced {
+				t.Errorf("Expected synced to be %t but got %t", test.expectSynced, synced)
+			}
+			if len(prices) != test.expectedResultSize {
+				t.Errorf("Expected %d prices but got %d", test.expectedResultSize, len(prices))
+			}
+		})
+	}
+}
+
+func TestGetContinuousPrices(t *testing.T) {
+	tests := []struct {
+		name               string
+		testDate           time.Time
+		mockResponse       *http.Response
+		mockError          error
+		expectedResultSize int
+		expectSynced       bool
+		expectingError     bool
+	}{
+		{
+			name:     "Valid response",
+			testDate: time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse: &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(
+				testutils.ReadJsonStringFromFile("testdata/precios_pibcic_20240515.1"))},
+			expectedResultSize: 14,
+		},
+		{
+			name:         "Not published - in future",
+			testDate:     time.Now().AddDate(0, 0, 1),
+			mockResponse: &http.Response{StatusCode: 404, Body: testdata.NewMockReadCloser("")},
+			expectSynced: true,
+		},
+		{
+			name:           "500 error",
+			testDate:       time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 500, Body: testdata.NewMockReadCloser("")},
+			expectingError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := ContinuousClient{Http: &testdata.MockHTTPClient{
+				MockResp: test.mockResponse,
+				MockErr:  test.mockError,
+			}, Zone: Spain}
+
+			prices, synced, err := client.GetPrices(test.testDate)
+
+			if test.expectingError && err == nil {
+				t.Errorf("Expected an error but got nil")
+			}
+			if !test.expectingError && err != nil {
+				t.Errorf("Expected no error but got %s", err)
+			}
+			if synced != test.expectSynced {
+				t.Errorf("Expected synced to be %t but got %t", test.expectSynced, synced)
+			}
+			if len(prices) != test.expectedResultSize {
+				t.Errorf("Expected %d prices but got %d", test.expectedResultSize, len(prices))
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package omie
+
+import (
+	"bufio"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidFile = errors.New("invalid OMIE file")
+
+// ParseMarginalPrices
+// Parse an OMIE marginalpdbc file into hourly prices for the zone.
+// Each row is year;month;day;period;price PT;price ES; with prices in €/MWh.
+// Files use hourly periods, or quarter-hour periods since the 15 minute market began, which are averaged into hours.
+func ParseMarginalPrices(r io.Reader, zone Zone) ([]price.Price, error) {
+	day, periods, err := readPeriods(r, zone, "MARGINALPDBC")
+	if err != nil || len(periods) == 0 {
+		return nil, err
+	}
+
+	// The day-ahead file always has every period of the day
+	perHour := periodsPerHour(day)
+	if expected := date.HoursInDay(day) * perHour; len(periods) != expected {
+		return nil, fmt.Errorf("%w: expected %d periods but got %d", ErrInvalidFile, expected, len(periods))
+	}
+
+	return averageHours(day, periods, perHour)
+}
+
+// ParseIntradayPrices
+// Parse an OMIE marginalpibc file for an intraday auction session into hourly prices for the zone.
+// The rows are laid out as in the marginalpdbc file, but a session may only cover the later hours of the day.
+func ParseIntradayPrices(r io.Reader, zone Zone, session int) ([]price.Price, error) {
+	day, periods, err := readPeriods(r, zone, "MARGINALPIBC")
+	if err != nil || len(periods) == 0 {
+		return nil, err
+	}
+
+	prices, err := averageHours(day, periods, periodsPerHour(day))
+	if err != nil {
+		return nil, err
+	}
+	for i := range prices {
+		prices[i].Session = session
+	}
+	return prices, nil
+}
+
+// ParseContinuousPrices
+// Parse an OMIE precios_pibcic file for the continuous intraday market into hourly prices for the zone.
+// Each row is year;month;day;period;price PT;price ES; with the weighted average price of the period's trades in €/MWh.
+// Periods are only listed once they have closed, so the current day may not have every hour.
+func ParseContinuousPrices(r io.Reader, zone Zone) ([]price.Price, error) {
+	day, periods, err := readPeriods(r, zone, "PRECIOS_PIBCIC")
+	if err != nil || len(periods) == 0 {
+		return nil, err
+	}
+
+	return averageHours(day, periods, periodsPerHour(day))
+}
+
+// readPeriods
+// Read the zone's price of each period in the file, skipping the header and the end of file marker.
+// Every row must be for the same day and each period can only be listed once.
+func readPeriods(r io.Reader, zone Zone, header string) (time.Time, map[int]float64, error) {
+	periods := make(map[int]float64)
+	var day time.Time
+
+	scanner := bufio.NewScanner(r)
+	for line := 0; scanner.Scan(); line++ {
+		row := strings.TrimSpace(scanner.Text())
+
+		// Skip the header and the end of file marker
+		if row == "" || row == "*" || strings.HasPrefix(row, header) {
+			continue
+		}
+
+		fields := strings.Split(strings.TrimSuffix(row, ";"), ";")
+		if len(fields) < 6 {
+			return time.Time{}, nil, fmt.Errorf("%w: line %d has %d fields", ErrInvalidFile, line+1, len(fields))
+		}
+
+		d, err := parseDay(fields[0], fields[1], fields[2])
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("%w: line %d has an invalid date", ErrInvalidFile, line+1)
+		}
+		if day.IsZero() {
+			day = d
+		} else if !day.Equal(d) {
+			return time.Time{}, nil, fmt.Errorf("%w: line %d is for a different day", ErrInvalidFile, line+1)
+		}
+
+		period, err := strconv.Atoi(strings.TrimSpace(fields[3]))
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("%w: line %d has an invalid period", ErrInvalidFile, line+1)
+		}
+		if _, ok := periods[period]; ok {
+			return time.Time{}, nil, fmt.Errorf("%w: period %d is repeated", ErrInvalidFile, period)
+		}
+
+		p, err := strconv.ParseFloat(strings.TrimSpace(fields[zone.column()]), 64)
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("%w: line %d has an invalid price", ErrInvalidFile, line+1)
+		}
+		periods[period] = p
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, nil, err
+	}
+
+	return day, periods, nil
+}
+
+// periodsPerHour
+// Get the number of periods in each hour of the day, four from the start of the 15 minute market and one before.
+// This is worked out from the day rather than the periods, as a file that only covers part of the day may not list
+// any period past the number of hours in the day.
+func periodsPerHour(day time.Time) int {
+	if day.Before(QuarterHourStart) {
+		return 1
+	}
+	return 4
+}
+
+// averageHours
+// Average the periods in each hour into prices in €/kWh, in time order.
+func averageHours(day time.Time, periods map[int]float64, perHour int) ([]price.Price, error) {
+	hours := date.HoursInDay(day)
+	sums := make(map[int]float64)
+	counts := make(map[int]int)
+	for period, p := range periods {
+		if period < 1 || period > hours*perHour {
+			return nil, fmt.Errorf("%w: period %d is out of range", ErrInvalidFile, period)
+		}
+		sums[(period-1)/perHour+1] += p
+		counts[(period-1)/perHour+1]++
+	}
+
+	prices := make([]price.Price, 0, len(sums))
+	for index, sum := range sums {
+		t, err := date.ParseHourIndex(day, index)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, price.Price{DateTime: t, Price: sum / float64(counts[index]) / 1000})
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].DateTime.Before(prices[j].DateTime)
+	})
+
+	return prices, nil
+}
+
+// parseDay
+// Parse the year, month and day fields into the start of the local day.
+func parseDay(year string, month string, day string) (time.Time, error) {
+	var parts [3]int
+	for i, field := range []string{year, month, day} {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return time.Time{}, err
+		}
+		parts[i] = n
+	}
+	d := time.Date(parts[0], time.Month(parts[1]), parts[2], 0, 0, 0, 0, date.Location)
+	if d.Year() != parts[0] || int(d.Month()) != parts[1] || d.Day() != parts[2] {
+		return time.Time{}, fmt.Errorf("%s-%s-%s is not a valid date", year, month, day)
+	}
+	return d, nil
+}
@@ -0,0 +1,272 @@
+package omie
+
+import (
+	"electricity-prices/pkg/date"
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const epsilon = 1e-6 // Tolerance level
+
+func TestParseMarginalPrices(t *testing.T) {
+	tests := []struct {
+		name               string
+		file               string
+		zone               Zone
+		expectedResultSize int
+		expectedFirstTime  time.Time
+		expectedFirstPrice float64
+		expectedLastPrice  float64
+		expectingError     bool
+	}{
+		{
+			name:               "Hourly file - Spain",
+			file:               "testdata/marginalpdbc_20231129.1",
+			zone:               Spain,
+			expectedResultSize: 24,
+			expectedFirstTime:  time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			expectedFirstPrice: 0.091,
+			expectedLastPrice:  0.114,
+		},
+		{
+			name:               "Hourly file - Portugal",
+			file:               "testdata/marginalpdbc_20231129.1",
+			zone:               Portugal,
+			expectedResultSize: 24,
+			expectedFirstTime:  time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			expectedFirstPrice: 0.081,
+			expectedLastPrice:  0.104,
+		},
+		{
+			name:               "Hourly file - clocks go forward",
+			file:               "testdata/marginalpdbc_20230326.1",
+			zone:               Spain,
+			expectedResultSize: 23,
+			expectedFirstTime:  time.Date(2023, 3, 26, 0, 0, 0, 0, date.Location),
+			expectedFirstPrice: 0.11,
+			expectedLastPrice:  0.11,
+		},
+		{
+			name:               "Quarter-hour file",
+			file:               "testdata/marginalpdbc_20251015.1",
+			zone:               Spain,
+			expectedResultSize: 24,
+			expectedFirstTime:  time.Date(2025, 10, 15, 0, 0, 0, 0, date.Location),
+			expectedFirstPrice: 0.063,
+			expectedLastPrice:  0.063,
+		},
+		{
+			name:           "Invalid price",
+			file:           "testdata/marginalpdbc_invalid.1",
+			zone:           Spain,
+			expectingError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := os.Open(test.file)
+			if err != nil {
+				t.Fatalf("Error opening file: %s", err)
+			}
+			defer f.Close()
+
+			prices, err := ParseMarginalPrices(f, test.zone)
+
+			if test.expectingError {
+				if !errors.Is(err, ErrInvalidFile) {
+					t.Fatalf("Expected an invalid file error but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got %s", err)
+			}
+			if len(prices) != test.expectedResultSize {
+				t.Fatalf("Expected %d prices but got %d", test.expectedResultSize, len(prices))
+			}
+			if !prices[0].DateTime.Equal(test.expectedFirstTime) {
+				t.Errorf("Expected first price at %s but got %s", test.expectedFirstTime, prices[0].DateTime)
+			}
+			if diff := prices[0].Price - test.expectedFirstPrice; diff > epsilon || diff < -epsilon {
+				t.Errorf("Expected first price %f but got %f", test.expectedFirstPrice, prices[0].Price)
+			}
+			last := prices[len(prices)-1].Price
+			if diff := last - test.expectedLastPrice; diff > epsilon || diff < -epsilon {
+				t.Errorf("Expected last price %f but got %f", test.expectedLastPrice, last)
+			}
+		})
+	}
+}
+
+func TestParseMarginalPricesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"Too few fields", "MARGINALPDBC;\n2023;11;29;1;80.00;\n*\n"},
+		{"Invalid date", "MARGINALPDBC;\n2023;13;29;1;80.00;90.00;\n*\n"},
+		{"Repeated period", "MARGINALPDBC;\n2023;11;29;1;80.00;90.00;\n2023;11;29;1;80.00;90.00;\n*\n"},
+		{"Different days", "MARGINALPDBC;\n2023;11;29;1;80.00;90.00;\n2023;11;30;2;80.00;90.00;\n*\n"},
+		{"Missing periods", "MARGINALPDBC;\n2023;11;29;1;80.00;90.00;\n2023;11;29;2;80.00;90.00;\n*\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseMarginalPrices(strings.NewReader(test.file), Spain)
+			if !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Expected an invalid file error but got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseIntradayPrices(t *testing.T) {
+	tests := []struct {
+		name               string
+		file               string
+		zone               Zone
+		session            int
+		expectedResultSize int
+		expectedFirstTime  time.Time
+		expectedFirstPrice float64
+		expectedLastPrice  float64
+	}{
+		{
+			name:               "Hourly session - Spain",
+			file:               "testdata/marginalpibc_2024051501.1",
+			zone:               Spain,
+			session:            1,
+			expectedResultSize: 24,
+			expectedFirstTime:  time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			expectedFirstPrice: 0.061,
+			expectedLastPrice:  0.084,
+		},
+		{
+			name:               "Hourly session - Portugal",
+			file:               "testdata/marginalpibc_2024051501.1",
+			zone:               Portugal,
+			session:            1,
+			expectedResultSize: 24,
+			expectedFirstTime:  time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			expectedFirstPrice: 0.051,
+			expectedLastPrice:  0.074,
+		},
+		{
+			name:               "Quarter-hour session covering the later hours",
+			file:               "testdata/marginalpibc_2025101503.1",
+			zone:               Spain,
+			session:            3,
+			expectedResultSize: 12,
+			expectedFirstTime:  time.Date(2025, 10, 15, 12, 0, 0, 0, date.Location),
+			expectedFirstPrice: 0.0645,
+			expectedLastPrice:  0.0755,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := os.Open(test.file)
+			if err != nil {
+				t.Fatalf("Error opening file: %s", err)
+			}
+			defer f.Close()
+
+			prices, err := ParseIntradayPrices(f, test.zone, test.session)
+
+			if err != nil {
+				t.Fatalf("Expected no error but got %s", err)
+			}
+			if len(prices) != test.expectedResultSize {
+				t.Fatalf("Expected %d prices but got %d", test.expectedResultSize, len(prices))
+			}
+			if !prices[0].DateTime.Equal(test.expectedFirstTime) {
+				t.Errorf("Expected first price at %s but got %s", test.expectedFirstTime, prices[0].DateTime)
+			}
+			if diff := prices[0].Price - test.expectedFirstPrice; diff > epsilon || diff < -epsilon {
+				t.Errorf("Expected first price %f but got %f", test.expectedFirstPrice, prices[0].Price)
+			}
+			last := prices[len(prices)-1].Price
+			if diff := last - test.expectedLastPrice; diff > epsilon || diff < -epsilon {
+				t.Errorf("Expected last price %f but got %f", test.expectedLastPrice, last)
+			}
+			for _, p := range prices {
+				if p.Session != test.session {
+					t.Errorf("Expected session %d but got %d", test.session, p.Session)
+				}
+			}
+		})
+	}
+}
+
+func TestParseContinuousPrices(t *testing.T) {
+	f, err := os.Open("testdata/precios_pibcic_20240515.1")
+	if err != nil {
+		t.Fatalf("Error opening file: %s", err)
+	}
+	defer f.Close()
+
+	prices, err := ParseContinuousPrices(f, Spain)
+
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	// Only the hours that have closed are listed
+	if len(prices) != 14 {
+		t.Fatalf("Expected 14 prices but got %d", len(prices))
+	}
+	if diff := prices[0].Price - 0.081; diff > epsilon || diff < -epsilon {
+		t.Errorf("Expected first price 0.081 but got %f", prices[0].Price)
+	}
+	if last := prices[13]; !last.DateTime.Equal(time.Date(2024, 5, 15, 13, 0, 0, 0, date.Location)) || last.Session != 0 {
+		t.Errorf("Expected the last price at 13:00 without a session but got %+v", last)
+	}
+}
+
+func TestParseContinuousPricesPartialQuarterHourDay(t *testing.T) {
+	f, err := os.Open("testdata/precios_pibcic_20251016.1")
+	if err != nil {
+		t.Fatalf("Error opening file: %s", err)
+	}
+	defer f.Close()
+
+	prices, err := ParseContinuousPrices(f, Spain)
+
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err)
+	}
+	// Periods 1 to 24 are the quarter hours of the first six hours, not 24 hourly prices
+	if len(prices) != 6 {
+		t.Fatalf("Expected 6 prices but got %d", len(prices))
+	}
+	if first := prices[0]; !first.DateTime.Equal(time.Date(2025, 10, 16, 0, 0, 0, 0, date.Location)) || math.Abs(first.Price-0.0625) > epsilon {
+		t.Errorf("Expected the average of the first four quarter hours, 0.0625, at midnight but got %+v", first)
+	}
+	if last := prices[5]; !last.DateTime.Equal(time.Date(2025, 10, 16, 5, 0, 0, 0, date.Location)) || math.Abs(last.Price-0.0825) > epsilon {
+		t.Errorf("Expected the average of the last four quarter hours, 0.0825, at 05:00 but got %+v", last)
+	}
+}
+
+func TestParseIntradayPricesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"Invalid price", "MARGINALPIBC;\n2024;05;15;1;80.00;abc;\n*\n"},
+		{"Period out of range", "MARGINALPIBC;\n2024;05;15;97;80.00;90.00;\n*\n"},
+		{"Different days", "MARGINALPIBC;\n2024;05;15;1;80.00;90.00;\n2024;05;16;2;80.00;90.00;\n*\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseIntradayPrices(strings.NewReader(test.file), Spain, 1)
+			if !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Expected an invalid file error but got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package omie
+
+import (
+	"electricity-prices/pkg/date"
+	"time"
+)
+
+// QuarterHourStart is the first delivery day of the 15 minute market, from when OMIE files list quarter-hour periods.
+var QuarterHourStart = time.Date(2025, 10, 1, 0, 0, 0, 0, date.Location)
+
+// Zone is a bidding zone of the Iberian day-ahead market.
+type Zone string
+
+const (
+	Spain    Zone = "es"
+	Portugal Zone = "pt"
+)
+
+// column returns the column of the marginalpdbc file that holds the zone's price.
+// The file lists the Portuguese price before the Spanish one.
+func (z Zone) column() int {
+	if z == Portugal {
+		return 4
+	}
+	return 5
+}
@@ -0,0 +1,56 @@
+package price
+
+import "time"
+
+type AnomalyType string
+
+const (
+	// Spike is an hour far above the rolling distribution of prices
+	Spike AnomalyType = "SPIKE"
+	// Dip is an hour far below the rolling distribution of prices
+	Dip AnomalyType = "DIP"
+	// NonPositive is an hour with a zero or negative price
+	NonPositive AnomalyType = "NON_POSITIVE"
+	// DayJump is a day whose average moved sharply from the day before. It is flagged on the first hour of the day.
+	DayJump AnomalyType = "DAY_JUMP"
+)
+
+const (
+	defaultAnomalyWindowDays = 30
+	defaultAnomalyZScore     = 3.0
+	defaultAnomalyDayJump    = 0.5
+)
+
+// Anomaly is an unusual price flagged when it was synced.
+// Baseline is what the price was compared against: the rolling mean for spikes and dips and the previous day's
+// average for day jumps. Score is the number of standard deviations from the mean or the relative change in the average.
+type Anomaly struct {
+	DateTime time.Time   `bson:"dateTime" json:"dateTime"`
+	Type     AnomalyType `bson:"type" json:"type"`
+	Price    float64     `bson:"price" json:"price"`
+	Baseline float64     `bson:"baseline" json:"baseline"`
+	Score    float64     `bson:"score" json:"score"`
+}
+
+// AnomalyDetector flags unusual prices against the rolling distribution of the previous WindowDays.
+// An hour is a spike or dip when it is at least ZScore standard deviations from the rolling mean and a day jumps when
+// its average changes by at least DayJump (a fraction) from the previous day. Zero values use 30 days, 3 and 0.5.
+type AnomalyDetector struct {
+	WindowDays int
+	ZScore     float64
+	DayJump    float64
+}
+
+// WithDefaults returns the detector with the zero values replaced by the defaults.
+func (d AnomalyDetector) WithDefaults() AnomalyDetector {
+	if d.WindowDays == 0 {
+		d.WindowDays = defaultAnomalyWindowDays
+	}
+	if d.ZScore == 0 {
+		d.ZScore = defaultAnomalyZScore
+	}
+	if d.DayJump == 0 {
+		d.DayJump = defaultAnomalyDayJump
+	}
+	return d
+}
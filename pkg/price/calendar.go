@@ -0,0 +1,153 @@
+package price
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// PeriodType is a kind of period, cheap or expensive.
+type PeriodType string
+
+const (
+	CheapPeriod     PeriodType = "cheap"
+	ExpensivePeriod PeriodType = "expensive"
+)
+
+// ParsePeriodTypes parses the period type to include, where empty includes both.
+func ParsePeriodTypes(periodType string) ([]PeriodType, bool) {
+	switch PeriodType(periodType) {
+	case "":
+		return []PeriodType{CheapPeriod, ExpensivePeriod}, true
+	case CheapPeriod, ExpensivePeriod:
+		return []PeriodType{PeriodType(periodType)}, true
+	}
+	return nil, false
+}
+
+// ZoneSeries returns the series of a bidding zone's prices: the PVPC for Spain (es), the OMIE day-ahead price for
+// Portugal (pt) and the ENTSO-E prices, stored as entsoe-{zone}, for any other zone.
+func ZoneSeries(zone string) Series {
+	switch strings.ToLower(zone) {
+	case "", "es":
+		return Pvpc
+	case "pt":
+		return OmiePortugal
+	}
+	return Series("entsoe-" + strings.ToLower(zone))
+}
+
+// CalendarEvent is a cheap or expensive period as a calendar event.
+type CalendarEvent struct {
+	Type    PeriodType
+	Start   time.Time
+	End     time.Time
+	Average float64
+}
+
+// CalculateCalendarEvents gets the events of the cheap and expensive periods of the prices, sorted by start.
+// The prices are grouped together, so a period can continue past midnight.
+func CalculateCalendarEvents(prices []Price, thirtyDayAvg float64, types []PeriodType) []CalendarEvent {
+	events := make([]CalendarEvent, 0)
+	for _, t := range types {
+		var periods [][]Price
+		if t == CheapPeriod {
+			periods = CalculateCheapPeriods(prices, thirtyDayAvg)
+		} else {
+			periods = CalculateExpensivePeriods(prices, thirtyDayAvg)
+		}
+		for _, period := range periods {
+			if len(period) == 0 {
+				continue
+			}
+			events = append(events, CalendarEvent{
+				Type:    t,
+				Start:   period[0].DateTime,
+				End:     period[len(period)-1].DateTime.Add(time.Hour),
+				Average: CalculateAverage(period),
+			})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events
+}
+
+// WriteCalendar writes the events as an iCalendar feed, with the summaries in the language.
+// The UIDs are made from the zone, type and start so calendars update an event rather than duplicating it when the feed is refreshed.
+func WriteCalendar(events []CalendarEvent, zone string, lang language.Tag, now time.Time) string {
+	p := message.NewPrinter(lang)
+	zone = strings.ToLower(zone)
+	if zone == "" {
+		zone = "es"
+	}
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//electricity-prices//calendar//" + strings.ToUpper(lang.String()),
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + escapeCalendarText(p.Sprintf("calendar_name")),
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H",
+		"X-PUBLISHED-TTL:PT1H",
+	}
+	for _, e := range events {
+		summary := p.Sprintf("calendar_cheap_summary", FormatPrice(e.Average))
+		if e.Type == ExpensivePeriod {
+			summary = p.Sprintf("calendar_expensive_summary", FormatPrice(e.Average))
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-%s-%s@electricity-prices", zone, e.Type, formatCalendarTime(e.Start)),
+			"DTSTAMP:"+formatCalendarTime(now),
+			"DTSTART:"+formatCalendarTime(e.Start),
+			"DTEND:"+formatCalendarTime(e.End),
+			"SUMMARY:"+escapeCalendarText(summary),
+			"CATEGORIES:"+strings.ToUpper(string(e.Type)),
+			"TRANSP:TRANSPARENT",
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldCalendarLine(line))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// formatCalendarTime
+// Format the time in UTC as an iCalendar date-time.
+func formatCalendarTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeCalendarText
+// Escape the backslashes, semicolons, commas and newlines of an iCalendar text value.
+func escapeCalendarText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// foldCalendarLine
+// Fold a content line longer than 75 octets onto continuation lines starting with a space, without splitting a character.
+func foldCalendarLine(line string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > 75 {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return b.String()
+}
@@ -0,0 +1,188 @@
+package price
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/i18n"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestParsePeriodTypes(t *testing.T) {
+	tests := []struct {
+		periodType string
+		expected   []PeriodType
+		expectOk   bool
+	}{
+		{"", []PeriodType{CheapPeriod, ExpensivePeriod}, true},
+		{"cheap", []PeriodType{CheapPeriod}, true},
+		{"expensive", []PeriodType{ExpensivePeriod}, true},
+		{"normal", nil, false},
+	}
+
+	for _, tt := range tests {
+		types, ok := ParsePeriodTypes(tt.periodType)
+		if ok != tt.expectOk || strings.Join(typeNames(types), ",") != strings.Join(typeNames(tt.expected), ",") {
+			t.Errorf("Expected %v and %t for %q, but got %v and %t", tt.expected, tt.expectOk, tt.periodType, types, ok)
+		}
+	}
+}
+
+// typeNames
+// Get the names of the period types.
+func typeNames(types []PeriodType) []string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return names
+}
+
+func TestZoneSeries(t *testing.T) {
+	tests := []struct {
+		zone     string
+		expected Series
+	}{
+		{"", Pvpc},
+		{"es", Pvpc},
+		{"PT", OmiePortugal},
+		{"FR", Series("entsoe-fr")},
+		{"de-lu", Series("entsoe-de-lu")},
+	}
+
+	for _, tt := range tests {
+		if series := ZoneSeries(tt.zone); series != tt.expected {
+			t.Errorf("Expected %s for %q, but got %s", tt.expected, tt.zone, series)
+		}
+	}
+}
+
+// calendarPrices
+// Get two days of prices that are cheap at night and expensive in the evening, from the start of the day.
+func calendarPrices(start time.Time) []Price {
+	var prices []Price
+	for i := 0; i < 48; i++ {
+		p := 0.15
+		switch hour := i % 24; {
+		case hour < 6:
+			p = 0.05
+		case hour >= 19 && hour < 22:
+			p = 0.3
+		}
+		prices = append(prices, Price{DateTime: start.Add(time.Duration(i) * time.Hour), Price: p})
+	}
+	return prices
+}
+
+func TestCalculateCalendarEvents(t *testing.T) {
+	start := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	prices := calendarPrices(start)
+
+	events := CalculateCalendarEvents(prices, 0.15, []PeriodType{CheapPeriod, ExpensivePeriod})
+	cheap := CalculateCheapPeriods(prices, 0.15)
+	expensive := CalculateExpensivePeriods(prices, 0.15)
+	if len(events) != len(cheap)+len(expensive) {
+		t.Fatalf("Expected an event per period, %d, but got %d", len(cheap)+len(expensive), len(events))
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Start.Before(events[i-1].Start) {
+			t.Errorf("Expected the events to be sorted by start, but %s is before %s", events[i].Start, events[i-1].Start)
+		}
+	}
+
+	first := events[0]
+	if first.Type != CheapPeriod || !first.Start.Equal(cheap[0][0].DateTime) || !first.End.Equal(cheap[0][len(cheap[0])-1].DateTime.Add(time.Hour)) {
+		t.Errorf("Expected the first event to be the first cheap period, but got %+v", first)
+	}
+	if first.Average != CalculateAverage(cheap[0]) {
+		t.Errorf("Expected the average of the period, %f, but got %f", CalculateAverage(cheap[0]), first.Average)
+	}
+
+	for _, e := range CalculateCalendarEvents(prices, 0.15, []PeriodType{ExpensivePeriod}) {
+		if e.Type != ExpensivePeriod {
+			t.Errorf("Expected only expensive events, but got %s", e.Type)
+		}
+	}
+}
+
+func TestWriteCalendar(t *testing.T) {
+	err := i18n.InitialiseTranslations([]i18n.File{
+		{Filename: "en.toml", Lang: language.English},
+		{Filename: "es.toml", Lang: language.Spanish},
+	})
+	if err != nil {
+		t.Fatalf("Failed to load the translations: %s", err)
+	}
+
+	start := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	events := []CalendarEvent{
+		{Type: CheapPeriod, Start: start, End: start.Add(6 * time.Hour), Average: 0.05},
+		{Type: ExpensivePeriod, Start: start.Add(19 * time.Hour), End: start.Add(22 * time.Hour), Average: 0.3123},
+	}
+	now := time.Date(2024, 5, 15, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		lang     language.Tag
+		zone     string
+		expected []string
+	}{
+		{
+			name: "Spanish",
+			lang: language.Spanish,
+			expected: []string{
+				"BEGIN:VCALENDAR\r\n",
+				"X-WR-CALNAME:Precio de la electricidad\r\n",
+				"BEGIN:VEVENT\r\nUID:es-cheap-20240514T220000Z@electricity-prices\r\nDTSTAMP:20240515T093000Z\r\nDTSTART:20240514T220000Z\r\nDTEND:20240515T040000Z\r\n",
+				"SUMMARY:Luz barata\\, 5 céntimos/kWh de media\r\n",
+				"UID:es-expensive-20240515T170000Z@electricity-prices\r\n",
+				"SUMMARY:Luz cara\\, 31.2 céntimos/kWh de media\r\n",
+				"END:VCALENDAR\r\n",
+			},
+		},
+		{
+			name: "English in another zone",
+			lang: language.English,
+			zone: "PT",
+			expected: []string{
+				"X-WR-CALNAME:Electricity prices\r\n",
+				"UID:pt-cheap-20240514T220000Z@electricity-prices\r\n",
+				"SUMMARY:Cheap electricity\\, 5 cents/kWh on average\r\n",
+				"SUMMARY:Expensive electricity\\, 31.2 cents/kWh on average\r\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := WriteCalendar(events, tt.zone, tt.lang, now)
+			for _, expected := range tt.expected {
+				if !strings.Contains(calendar, expected) {
+					t.Errorf("Expected the calendar to contain %q, but got\n%s", expected, calendar)
+				}
+			}
+			if strings.Count(calendar, "BEGIN:VEVENT") != len(events) {
+				t.Errorf("Expected %d events, but got\n%s", len(events), calendar)
+			}
+		})
+	}
+}
+
+func TestFoldCalendarLine(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("é", 40)
+	folded := foldCalendarLine(line)
+
+	for _, l := range strings.Split(folded, "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("Expected lines of at most 75 octets, but got %d", len(l))
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != line {
+		t.Errorf("Expected unfolding to give the original line, but got %q", folded)
+	}
+	if foldCalendarLine("DTSTART:20240514T220000Z") != "DTSTART:20240514T220000Z" {
+		t.Errorf("Expected a short line to be unchanged")
+	}
+}
@@ -12,11 +12,15 @@ type MockCollection struct {
 	MockFindResult     *[][]Price
 	MockFindErr        *[]error
 	MockInsertManyErr  *[]error
+	MockUpsertMany     *[][]Price
+	MockUpsertManyErr  *[]error
 	MockThirtyDayAvg   *[]float64
 	MockThirtyDayErr   *[]error
 	MockLatestPrice    *[]Price
 	MockLatestPriceOk  *[]bool
 	MockLatestPriceErr *[]error
+	MockStats          *[]Stats
+	MockStatsErr       *[]error
 }
 
 func (m *MockCollection) FindOne(ctx context.Context, filter interface{}) (Price, error) {
@@ -72,6 +76,22 @@ func (m *MockCollection) InsertMany(ctx context.Context, documents []Price) erro
 	return err
 }
 
+func (m *MockCollection) UpsertMany(ctx context.Context, documents []Price) error {
+	// Record the documents that were upserted
+	*m.MockUpsertMany = append(*m.MockUpsertMany, documents)
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockUpsertManyErr) > 0 {
+		err = (*m.MockUpsertManyErr)[0]
+		*m.MockUpsertManyErr = (*m.MockUpsertManyErr)[1:]
+	} else {
+		err = nil
+	}
+
+	return err
+}
+
 func (m *MockCollection) GetThirtyDayAverage(ctx context.Context, t time.Time) (float64, error) {
 	// Get the first element of the result array and remove it from the array, return nil if the array is empty
 	var result float64
@@ -118,3 +138,23 @@ func (m *MockCollection) GetLatestPrice(ctx context.Context) (Price, bool, error
 
 	return result, ok, err
 }
+
+func (m *MockCollection) GetStats(ctx context.Context, start time.Time, end time.Time) (Stats, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result Stats
+	if len(*m.MockStats) > 0 {
+		result = (*m.MockStats)[0]
+		*m.MockStats = (*m.MockStats)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockStatsErr) > 0 {
+		err = (*m.MockStatsErr)[0]
+		*m.MockStatsErr = (*m.MockStatsErr)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
@@ -3,4 +3,5 @@ package price
 type DailyAverage struct {
 	Date    string  `json:"date"`
 	Average float64 `json:"average"`
+	Shape   string  `json:"shape,omitempty"`
 }
@@ -10,10 +10,25 @@ const (
 )
 
 type DailyPriceInfo struct {
-	DayRating        DayRating `json:"dayRating"`
-	DayAverage       float64   `json:"dayAverage"`
-	ThirtyDayAverage float64   `json:"thirtyDayAverage"`
-	Prices           []Price   `json:"prices"`
-	CheapPeriods     [][]Price `json:"cheapestPeriods"`
-	ExpensivePeriods [][]Price `json:"expensivePeriods"`
+	DayRating        DayRating        `json:"dayRating"`
+	DayAverage       float64          `json:"dayAverage"`
+	ThirtyDayAverage float64          `json:"thirtyDayAverage"`
+	Prices           []Price          `json:"prices"`
+	CheapPeriods     [][]Price        `json:"cheapestPeriods"`
+	ExpensivePeriods [][]Price        `json:"expensivePeriods"`
+	Components       *Components      `json:"components,omitempty"`
+	PercentileRanks  []PercentileRank `json:"percentileRanks"`
+	Rating           RatingDetails    `json:"rating"`
+	PeriodStrategy   string           `json:"periodStrategy"`
+	Explanation      *Explanation     `json:"explanation,omitempty"`
+	Anomalies        []Anomaly        `json:"anomalies"`
+	Shape            string           `json:"shape,omitempty"`
+}
+
+// Options are the per-request settings for the daily info. Zero values use the deployment defaults.
+// Explain adds the intermediate values of the rating and period detection to the daily info.
+type Options struct {
+	Rating  RatingConfig
+	Periods PeriodStrategy
+	Explain bool
 }
@@ -0,0 +1,87 @@
+package price
+
+import "time"
+
+// HourExplanation is how an hour's price compared against the cheap and expensive thresholds.
+type HourExplanation struct {
+	DateTime           time.Time `json:"dateTime"`
+	Price              float64   `json:"price"`
+	CheapThreshold     float64   `json:"cheapThreshold"`
+	ExpensiveThreshold float64   `json:"expensiveThreshold"`
+	Cheap              bool      `json:"cheap"`
+	Expensive          bool      `json:"expensive"`
+}
+
+// Explanation holds the values the period strategy compared each hour against, so the flags on the hours always match
+// the cheap and expensive periods returned with it.
+//
+// For the variance heuristic an hour is cheap when its price is at or below the minimum price plus the cheap variance
+// and expensive when it is at or above the maximum price minus the expensive variance. Both variances are clamped
+// between the minimum and maximum variance. There are no expensive hours when the maximum price is at or below the
+// expensive cut-off. The variance fields are zero for the other strategies.
+//
+// For the quantile strategy the thresholds are the cheap and expensive percentiles of the day and for k-means they are
+// the midpoints between the centroids of the cheap, normal and expensive bands. The smoothed strategy keeps the
+// thresholds of the strategy it smooths, but its hours are flagged by the periods left after smoothing.
+type Explanation struct {
+	Strategy           string            `json:"strategy"`
+	ThirtyDayAverage   float64           `json:"thirtyDayAverage"`
+	DayAverage         float64           `json:"dayAverage"`
+	CombinedAverage    float64           `json:"combinedAverage"`
+	MinPrice           float64           `json:"minPrice"`
+	MaxPrice           float64           `json:"maxPrice"`
+	MinVariance        float64           `json:"minVariance"`
+	MaxVariance        float64           `json:"maxVariance"`
+	CheapVariance      float64           `json:"cheapVariance"`
+	ExpensiveVariance  float64           `json:"expensiveVariance"`
+	CheapThreshold     float64           `json:"cheapThreshold"`
+	ExpensiveThreshold float64           `json:"expensiveThreshold"`
+	ExpensiveCutOff    float64           `json:"expensiveCutOff"`
+	Centroids          []float64         `json:"centroids,omitempty"`
+	MinLength          int               `json:"minLength,omitempty"`
+	Hours              []HourExplanation `json:"hours"`
+}
+
+// newExplanation
+// Get the explanation of the strategy with the day's average, minimum and maximum prices but no hours.
+func newExplanation(strategy string, prices []Price, thirtyDayAvg float64) Explanation {
+	explanation := Explanation{Strategy: strategy, ThirtyDayAverage: thirtyDayAvg, Hours: make([]HourExplanation, 0, len(prices))}
+	if len(prices) == 0 {
+		return explanation
+	}
+	explanation.DayAverage = CalculateAverage(prices)
+	explanation.MinPrice, explanation.MaxPrice = getMinAndMaxPrices(prices)
+	return explanation
+}
+
+// explainHours
+// Get the hours compared against the thresholds, flagged by whether they are in the cheap or expensive periods.
+func explainHours(prices []Price, cheapPeriods [][]Price, expensivePeriods [][]Price, cheapThreshold float64, expensiveThreshold float64) []HourExplanation {
+	cheap := hoursInPeriods(cheapPeriods)
+	expensive := hoursInPeriods(expensivePeriods)
+
+	hours := make([]HourExplanation, 0, len(prices))
+	for _, p := range prices {
+		hours = append(hours, HourExplanation{
+			DateTime:           p.DateTime,
+			Price:              p.Price,
+			CheapThreshold:     cheapThreshold,
+			ExpensiveThreshold: expensiveThreshold,
+			Cheap:              cheap[p.DateTime.UnixNano()],
+			Expensive:          expensive[p.DateTime.UnixNano()],
+		})
+	}
+	return hours
+}
+
+// hoursInPeriods
+// Get the set of hours in the periods.
+func hoursInPeriods(periods [][]Price) map[int64]bool {
+	hours := make(map[int64]bool)
+	for _, period := range periods {
+		for _, p := range period {
+			hours[p.DateTime.UnixNano()] = true
+		}
+	}
+	return hours
+}
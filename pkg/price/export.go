@@ -0,0 +1,370 @@
+package price
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+var ErrInvalidFormat = errors.New("invalid format")
+
+// Format is the format a response is returned in.
+type Format string
+
+const (
+	JSON Format = "json"
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// ContentType is the media type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// ParseFormat gets the format from the format query param or, when it is empty, the Accept header.
+// The Accept header falls back to JSON when it has no supported media type.
+func ParseFormat(format string, accept string) (Format, error) {
+	switch Format(format) {
+	case JSON, CSV, XLSX:
+		return Format(format), nil
+	case "":
+		return acceptedFormat(accept), nil
+	}
+	return "", fmt.Errorf("%w: unknown format %q, use json, csv or xlsx", ErrInvalidFormat, format)
+}
+
+// acceptedFormat
+// Get the supported format with the highest quality in the Accept header, keeping the header order for ties.
+func acceptedFormat(accept string) Format {
+	best, bestQ := JSON, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		var format Format
+		switch mediaType {
+		case "application/json":
+			format = JSON
+		case "text/csv":
+			format = CSV
+		case XLSX.ContentType():
+			format = XLSX
+		default:
+			continue
+		}
+		q := 1.0
+		if qStr, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qStr, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+	return best
+}
+
+// ParseLocale gets the locale from the locale query param or, when it is empty, the first language in the
+// Accept-Language header. It is only used for the decimal separator so unknown locales use a point.
+func ParseLocale(locale string, acceptLanguage string) language.Tag {
+	if locale != "" {
+		tag, _ := language.Parse(locale)
+		return tag
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return language.Und
+	}
+	return tags[0]
+}
+
+// DecimalSeparator is the character the locale separates the decimals of a number with.
+func DecimalSeparator(locale language.Tag) string {
+	formatted := message.NewPrinter(locale).Sprint(number.Decimal(1.5))
+	if strings.Contains(formatted, ",") {
+		return ","
+	}
+	return "."
+}
+
+// Table is a flat view of a response for the spreadsheet formats. The values are strings, float64s, ints,
+// bools, times or nil for an empty cell.
+type Table struct {
+	Name   string
+	Header []string
+	Rows   [][]any
+}
+
+var componentsHeader = []string{
+	"energy", "adjustment", "capacityPayments", "interruptibility", "operatorFees", "tollsAndCharges", "tradingCosts", "other",
+}
+
+// values
+// Get the components in the order of the components header, or empty cells when there are none.
+func (c *Components) values() []any {
+	if c == nil {
+		return make([]any, len(componentsHeader))
+	}
+	return []any{c.Energy, c.Adjustment, c.CapacityPayments, c.Interruptibility, c.OperatorFees, c.TollsAndCharges, c.TradingCosts, c.Other}
+}
+
+// PricesTables is the prices as a table with one row per price. The session and component columns are only
+// included when a price has them.
+func PricesTables(prices []Price) []Table {
+	var withSession, withComponents bool
+	for _, p := range prices {
+		withSession = withSession || p.Session != 0
+		withComponents = withComponents || p.Components != nil
+	}
+
+	header := []string{"dateTime"}
+	if withSession {
+		header = append(header, "session")
+	}
+	header = append(header, "price")
+	if withComponents {
+		header = append(header, componentsHeader...)
+	}
+
+	rows := make([][]any, len(prices))
+	for i, p := range prices {
+		row := []any{p.DateTime}
+		if withSession {
+			row = append(row, p.Session)
+		}
+		row = append(row, p.Price)
+		if withComponents {
+			row = append(row, p.Components.values()...)
+		}
+		rows[i] = row
+	}
+	return []Table{{Name: "Prices", Header: header, Rows: rows}}
+}
+
+// DailyAveragesTables is the averages as a table with one row per day, with the shape of the day when it is labelled.
+func DailyAveragesTables(averages []DailyAverage) []Table {
+	rows := make([][]any, len(averages))
+	for i, a := range averages {
+		rows[i] = []any{a.Date, a.Average, a.Shape}
+	}
+	return []Table{{Name: "Averages", Header: []string{"date", "average", "shape"}, Rows: rows}}
+}
+
+// HeatmapTables is the heatmap as three 7×24 matrices, the mean, median and count, with a row per weekday
+// (Monday first) and a column per local hour. The first header cell names the matrix so the CSV sections can be
+// told apart.
+func HeatmapTables(heatmap Heatmap) []Table {
+	matrix := func(name string, value func(weekday int, hour int) any) Table {
+		header := []string{strings.ToLower(name)}
+		for hour := 0; hour < 24; hour++ {
+			header = append(header, strconv.Itoa(hour))
+		}
+		rows := make([][]any, len(heatmap.Weekdays))
+		for weekday, day := range heatmap.Weekdays {
+			row := []any{day}
+			for hour := 0; hour < 24; hour++ {
+				row = append(row, value(weekday, hour))
+			}
+			rows[weekday] = row
+		}
+		return Table{Name: name, Header: header, Rows: rows}
+	}
+
+	return []Table{
+		matrix("Mean", func(weekday int, hour int) any { return heatmap.Mean[weekday][hour] }),
+		matrix("Median", func(weekday int, hour int) any { return heatmap.Median[weekday][hour] }),
+		matrix("Count", func(weekday int, hour int) any { return heatmap.Count[weekday][hour] }),
+	}
+}
+
+// DailyInfoTables is the daily info as three tables: the day's values as name and value rows, one row per hour
+// and one row per anomaly. The hours are labelled with the period they are in, their percentile rank in each
+// window and, when included, their components and the thresholds they were compared against.
+func DailyInfoTables(info DailyPriceInfo, day string) []Table {
+	dayRows := [][]any{
+		{"date", day},
+		{"dayRating", string(info.DayRating)},
+		{"dayAverage", info.DayAverage},
+		{"thirtyDayAverage", info.ThirtyDayAverage},
+		{"periodStrategy", info.PeriodStrategy},
+		{"shape", info.Shape},
+		{"rating.method", string(info.Rating.Method)},
+		{"rating.threshold", info.Rating.Threshold},
+		{"rating.baselineDays", info.Rating.BaselineDays},
+		{"rating.baseline", info.Rating.Baseline},
+		{"rating.goodBelow", info.Rating.GoodBelow},
+		{"rating.badAbove", info.Rating.BadAbove},
+	}
+	for _, r := range info.PercentileRanks {
+		dayRows = append(dayRows,
+			[]any{"percentileRank." + r.Window + ".days", r.Days},
+			[]any{"percentileRank." + r.Window + ".dayAverage", r.DayAverage},
+		)
+	}
+	if info.Components != nil {
+		for i, v := range info.Components.values() {
+			dayRows = append(dayRows, []any{"components." + componentsHeader[i], v})
+		}
+	}
+	if e := info.Explanation; e != nil {
+		dayRows = append(dayRows,
+			[]any{"explanation.strategy", e.Strategy},
+			[]any{"explanation.combinedAverage", e.CombinedAverage},
+			[]any{"explanation.minPrice", e.MinPrice},
+			[]any{"explanation.maxPrice", e.MaxPrice},
+			[]any{"explanation.minVariance", e.MinVariance},
+			[]any{"explanation.maxVariance", e.MaxVariance},
+			[]any{"explanation.cheapVariance", e.CheapVariance},
+			[]any{"explanation.expensiveVariance", e.ExpensiveVariance},
+			[]any{"explanation.cheapThreshold", e.CheapThreshold},
+			[]any{"explanation.expensiveThreshold", e.ExpensiveThreshold},
+			[]any{"explanation.expensiveCutOff", e.ExpensiveCutOff},
+		)
+	}
+
+	return []Table{
+		{Name: "Day", Header: []string{"name", "value"}, Rows: dayRows},
+		hoursTable(info),
+		anomaliesTable(info.Anomalies),
+	}
+}
+
+// hoursTable
+// Get the daily info's prices as a table with one row per hour.
+func hoursTable(info DailyPriceInfo) Table {
+	periods := make(map[time.Time]string)
+	for _, period := range info.CheapPeriods {
+		for _, p := range period {
+			periods[p.DateTime] = "cheap"
+		}
+	}
+	for _, period := range info.ExpensivePeriods {
+		for _, p := range period {
+			periods[p.DateTime] = "expensive"
+		}
+	}
+	thresholds := make(map[time.Time]HourExplanation)
+	if info.Explanation != nil {
+		for _, h := range info.Explanation.Hours {
+			thresholds[h.DateTime] = h
+		}
+	}
+	var withComponents bool
+	for _, p := range info.Prices {
+		withComponents = withComponents || p.Components != nil
+	}
+
+	header := []string{"dateTime", "price", "period"}
+	for _, r := range info.PercentileRanks {
+		header = append(header, "percentileRank."+r.Window)
+	}
+	if withComponents {
+		header = append(header, componentsHeader...)
+	}
+	if info.Explanation != nil {
+		header = append(header, "cheapThreshold", "expensiveThreshold")
+	}
+
+	rows := make([][]any, len(info.Prices))
+	for i, p := range info.Prices {
+		row := []any{p.DateTime, p.Price, periods[p.DateTime]}
+		for _, r := range info.PercentileRanks {
+			if i < len(r.Hours) {
+				row = append(row, r.Hours[i])
+			} else {
+				row = append(row, nil)
+			}
+		}
+		if withComponents {
+			row = append(row, p.Components.values()...)
+		}
+		if info.Explanation != nil {
+			if h, ok := thresholds[p.DateTime]; ok {
+				row = append(row, h.CheapThreshold, h.ExpensiveThreshold)
+			} else {
+				row = append(row, nil, nil)
+			}
+		}
+		rows[i] = row
+	}
+	return Table{Name: "Hours", Header: header, Rows: rows}
+}
+
+// anomaliesTable
+// Get the anomalies as a table with one row per anomaly.
+func anomaliesTable(anomalies []Anomaly) Table {
+	rows := make([][]any, len(anomalies))
+	for i, a := range anomalies {
+		rows[i] = []any{a.DateTime, string(a.Type), a.Price, a.Baseline, a.Score}
+	}
+	return Table{Name: "Anomalies", Header: []string{"dateTime", "type", "price", "baseline", "score"}, Rows: rows}
+}
+
+// WriteCSV writes the tables as CSV, separated by an empty line. When the decimal separator is a comma the
+// fields are separated by semicolons, as spreadsheets in those locales expect.
+func WriteCSV(w io.Writer, tables []Table, decimalSeparator string) error {
+	writer := csv.NewWriter(w)
+	if decimalSeparator == "," {
+		writer.Comma = ';'
+	}
+	for i, table := range tables {
+		if i > 0 {
+			writer.Flush()
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(table.Header); err != nil {
+			return err
+		}
+		for _, row := range table.Rows {
+			record := make([]string, len(row))
+			for j, v := range row {
+				record[j] = formatValue(v, decimalSeparator)
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatValue
+// Format a table value as text, with times in RFC 3339 like the JSON responses.
+func formatValue(v any, decimalSeparator string) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strings.Replace(strconv.FormatFloat(v, 'f', -1, 64), ".", decimalSeparator, 1)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
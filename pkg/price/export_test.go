@@ -0,0 +1,202 @@
+package price
+
+import (
+	"electricity-prices/pkg/date"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		accept    string
+		expected  Format
+		expectErr bool
+	}{
+		{name: "Defaults to JSON", expected: JSON},
+		{name: "Format param", format: "csv", expected: CSV},
+		{name: "Format param wins over Accept", format: "json", accept: "text/csv", expected: JSON},
+		{name: "Accept CSV", accept: "text/csv", expected: CSV},
+		{name: "Accept XLSX", accept: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", expected: XLSX},
+		{name: "Highest quality", accept: "application/json;q=0.5, text/csv;q=0.9", expected: CSV},
+		{name: "First of equal quality", accept: "text/csv, application/json", expected: CSV},
+		{name: "Unsupported Accept", accept: "text/html, */*", expected: JSON},
+		{name: "Unknown format", format: "pdf", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := ParseFormat(tt.format, tt.accept)
+			if tt.expectErr {
+				if !errors.Is(err, ErrInvalidFormat) {
+					t.Errorf("Expected an invalid format error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if format != tt.expected {
+				t.Errorf("Expected %s, but got %s", tt.expected, format)
+			}
+		})
+	}
+}
+
+func TestDecimalSeparator(t *testing.T) {
+	tests := []struct {
+		name           string
+		locale         string
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "Defaults to a point", expected: "."},
+		{name: "Spanish", locale: "es", expected: ","},
+		{name: "Mexican Spanish", locale: "es-MX", expected: "."},
+		{name: "English", locale: "en-GB", expected: "."},
+		{name: "Locale wins over Accept-Language", locale: "en", acceptLanguage: "es-ES", expected: "."},
+		{name: "Accept-Language", acceptLanguage: "fr-FR,en;q=0.8", expected: ","},
+		{name: "Unknown locale", locale: "not a locale", expected: "."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			separator := DecimalSeparator(ParseLocale(tt.locale, tt.acceptLanguage))
+			if separator != tt.expected {
+				t.Errorf("Expected %q, but got %q", tt.expected, separator)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	prices := []Price{
+		{DateTime: time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC), Price: 0.12345},
+		{DateTime: time.Date(2024, 5, 15, 1, 0, 0, 0, time.UTC), Price: 0.1, Components: &Components{Energy: 0.08, TollsAndCharges: 0.02}},
+	}
+
+	tests := []struct {
+		name      string
+		separator string
+		expected  string
+	}{
+		{
+			name:      "Decimal point",
+			separator: ".",
+			expected: "dateTime,price,energy,adjustment,capacityPayments,interruptibility,operatorFees,tollsAndCharges,tradingCosts,other\n" +
+				"2024-05-15T00:00:00Z,0.12345,,,,,,,,\n" +
+				"2024-05-15T01:00:00Z,0.1,0.08,0,0,0,0,0.02,0,0\n",
+		},
+		{
+			name:      "Decimal comma",
+			separator: ",",
+			expected: "dateTime;price;energy;adjustment;capacityPayments;interruptibility;operatorFees;tollsAndCharges;tradingCosts;other\n" +
+				"2024-05-15T00:00:00Z;0,12345;;;;;;;;\n" +
+				"2024-05-15T01:00:00Z;0,1;0,08;0;0;0;0;0,02;0;0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := WriteCSV(&buf, PricesTables(prices), tt.separator); err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Expected\n%s\nbut got\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestDailyInfoTables(t *testing.T) {
+	start := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+	prices := []Price{
+		{DateTime: start, Price: 0.05},
+		{DateTime: start.Add(time.Hour), Price: 0.1},
+		{DateTime: start.Add(2 * time.Hour), Price: 0.2},
+	}
+	info := DailyPriceInfo{
+		DayRating:        Good,
+		DayAverage:       0.11,
+		ThirtyDayAverage: 0.12,
+		Prices:           prices,
+		CheapPeriods:     [][]Price{prices[:1]},
+		ExpensivePeriods: [][]Price{prices[2:]},
+		PercentileRanks:  []PercentileRank{{Window: "30d", Days: 30, DayAverage: 40, Hours: []float64{10, 50, 90}}},
+		Anomalies:        []Anomaly{{DateTime: start.Add(2 * time.Hour), Type: Spike, Price: 0.2, Baseline: 0.1, Score: 3.5}},
+		Shape:            "SOLAR_DUCK_CURVE",
+	}
+
+	tables := DailyInfoTables(info, "2024-05-15")
+	if len(tables) != 3 {
+		t.Fatalf("Expected 3 tables, but got %d", len(tables))
+	}
+
+	var buf strings.Builder
+	if err := WriteCSV(&buf, tables, "."); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	sections := strings.SplitAfter(buf.String(), "\n\n")
+	if len(sections) != 3 {
+		t.Fatalf("Expected 3 sections, but got %d", len(sections))
+	}
+	for i := range sections[:2] {
+		sections[i] = strings.TrimSuffix(sections[i], "\n")
+	}
+	for _, row := range []string{"date,2024-05-15", "dayRating,GOOD", "shape,SOLAR_DUCK_CURVE", "percentileRank.30d.dayAverage,40"} {
+		if !strings.Contains(sections[0], row+"\n") {
+			t.Errorf("Expected the day section to contain %s, but got\n%s", row, sections[0])
+		}
+	}
+	expectedHours := "dateTime,price,period,percentileRank.30d\n" +
+		"2024-05-15T00:00:00Z,0.05,cheap,10\n" +
+		"2024-05-15T01:00:00Z,0.1,,50\n" +
+		"2024-05-15T02:00:00Z,0.2,expensive,90\n"
+	if sections[1] != expectedHours {
+		t.Errorf("Expected the hours\n%s\nbut got\n%s", expectedHours, sections[1])
+	}
+	expectedAnomalies := "dateTime,type,price,baseline,score\n2024-05-15T02:00:00Z,SPIKE,0.2,0.1,3.5\n"
+	if sections[2] != expectedAnomalies {
+		t.Errorf("Expected the anomalies\n%s\nbut got\n%s", expectedAnomalies, sections[2])
+	}
+}
+
+func TestHeatmapTables(t *testing.T) {
+	heatmap := CalculateHeatmap([]Price{{DateTime: time.Date(2023, 11, 27, 0, 0, 0, 0, date.Location), Price: 0.12345}}, HeatmapFilter{})
+
+	tables := HeatmapTables(heatmap)
+	if len(tables) != 3 {
+		t.Fatalf("Expected 3 tables, but got %d", len(tables))
+	}
+
+	var buf strings.Builder
+	if err := WriteCSV(&buf, tables, "."); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	sections := strings.Split(strings.TrimSpace(buf.String()), "\n\n")
+	if len(sections) != 3 {
+		t.Fatalf("Expected 3 sections, but got %d", len(sections))
+	}
+	for i, name := range []string{"mean", "median", "count"} {
+		lines := strings.Split(sections[i], "\n")
+		if len(lines) != 7+1 {
+			t.Fatalf("Expected %d lines in the %s section, but got %d", 7+1, name, len(lines))
+		}
+		if !strings.HasPrefix(lines[0], name+",0,1,2,") || !strings.HasSuffix(lines[0], ",23") {
+			t.Errorf("Unexpected %s header %s", name, lines[0])
+		}
+		if cells := strings.Split(lines[1], ","); len(cells) != 25 || cells[0] != "Monday" {
+			t.Errorf("Unexpected first %s row %s", name, lines[1])
+		}
+	}
+	if row := strings.Split(sections[0], "\n")[1]; !strings.HasPrefix(row, "Monday,0.12345,0,") {
+		t.Errorf("Unexpected first mean row %s", row)
+	}
+	if row := strings.Split(sections[2], "\n")[1]; !strings.HasPrefix(row, "Monday,1,0,") {
+		t.Errorf("Unexpected first count row %s", row)
+	}
+}
@@ -0,0 +1,37 @@
+package price
+
+import "time"
+
+type Season string
+
+const (
+	Winter Season = "winter"
+	Spring Season = "spring"
+	Summer Season = "summer"
+	Autumn Season = "autumn"
+)
+
+type DayType string
+
+const (
+	WorkingDay DayType = "working"
+	Holiday    DayType = "holiday"
+)
+
+// HeatmapFilter restricts the days included in a heatmap. Zero values don't filter.
+// Holidays are weekends and national holidays.
+type HeatmapFilter struct {
+	Month   time.Month
+	Season  Season
+	DayType DayType
+}
+
+// Heatmap is the typical price shape as a matrix of weekday (Monday first) by local hour.
+type Heatmap struct {
+	Start    string         `json:"start"`
+	End      string         `json:"end"`
+	Weekdays []string       `json:"weekdays"`
+	Mean     [7][24]float64 `json:"mean"`
+	Median   [7][24]float64 `json:"median"`
+	Count    [7][24]int     `json:"count"`
+}
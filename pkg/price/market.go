@@ -0,0 +1,71 @@
+package price
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownSeries   = errors.New("unknown price series, market or session")
+	ErrSessionRequired = fmt.Errorf("a session from 1 to %d is required for the intraday market", IntradaySessions)
+)
+
+// Market is the Iberian electricity market a price comes from.
+type Market string
+
+const (
+	// DayAhead is the daily auction held the day before, which the PVPC and OMIE series come from
+	DayAhead Market = "day-ahead"
+	// Intraday is the intraday auction sessions held after the day-ahead market
+	Intraday Market = "intraday"
+	// Continuous is the continuous intraday market (MIC), traded until an hour before delivery
+	Continuous Market = "continuous"
+)
+
+// IntradaySessions is the number of intraday auction sessions held each day since June 2024.
+const IntradaySessions = 3
+
+// ParseMarket returns the market with the given name.
+func ParseMarket(name string) (Market, bool) {
+	switch Market(name) {
+	case DayAhead, Intraday, Continuous:
+		return Market(name), true
+	}
+	return "", false
+}
+
+// SeriesFor returns the series of the market for the zone of the day-ahead series.
+// PVPC and omie-es are Spanish and omie-pt is Portuguese. Other series have no intraday prices.
+func (m Market) SeriesFor(series Series) (Series, bool) {
+	if m == DayAhead {
+		return series, true
+	}
+
+	portugal := false
+	switch series {
+	case Pvpc, OmieSpain:
+	case OmiePortugal:
+		portugal = true
+	default:
+		return "", false
+	}
+
+	switch m {
+	case Intraday:
+		if portugal {
+			return IntradayPortugal, true
+		}
+		return IntradaySpain, true
+	case Continuous:
+		if portugal {
+			return ContinuousPortugal, true
+		}
+		return ContinuousSpain, true
+	}
+	return "", false
+}
+
+// WithSession returns the key of a single session of the series, used to look up its service.
+func (s Series) WithSession(session int) Series {
+	return Series(fmt.Sprintf("%s-%d", s, session))
+}
@@ -0,0 +1,44 @@
+package price
+
+import "testing"
+
+func TestMarketSeriesFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		market   Market
+		series   Series
+		expected Series
+		ok       bool
+	}{
+		{"Day-ahead keeps the series", DayAhead, Surplus, Surplus, true},
+		{"Intraday defaults to Spain", Intraday, Pvpc, IntradaySpain, true},
+		{"Intraday Spain", Intraday, OmieSpain, IntradaySpain, true},
+		{"Intraday Portugal", Intraday, OmiePortugal, IntradayPortugal, true},
+		{"Continuous Spain", Continuous, Pvpc, ContinuousSpain, true},
+		{"Continuous Portugal", Continuous, OmiePortugal, ContinuousPortugal, true},
+		{"No intraday surplus prices", Intraday, Surplus, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, ok := tt.market.SeriesFor(tt.series)
+			if ok != tt.ok || series != tt.expected {
+				t.Errorf("Expected %s (%t) but got %s (%t)", tt.expected, tt.ok, series, ok)
+			}
+		})
+	}
+}
+
+func TestParseMarket(t *testing.T) {
+	for _, m := range []Market{DayAhead, Intraday, Continuous} {
+		if parsed, ok := ParseMarket(string(m)); !ok || parsed != m {
+			t.Errorf("Expected %s to parse", m)
+		}
+	}
+	if _, ok := ParseMarket("futures"); ok {
+		t.Error("Expected an unknown market not to parse")
+	}
+	if IntradaySpain.WithSession(2) != "intraday-es-2" {
+		t.Errorf("Expected the session key intraday-es-2 but got %s", IntradaySpain.WithSession(2))
+	}
+}
@@ -0,0 +1,338 @@
+package price
+
+import (
+	"electricity-prices/pkg/date"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidOutput = errors.New("invalid output options")
+
+// Unit is the unit prices are returned in.
+type Unit string
+
+const (
+	EurPerKwh  Unit = "eur-kwh"
+	CentPerKwh Unit = "cent-kwh"
+	EurPerMwh  Unit = "eur-mwh"
+)
+
+// factor is what a price in €/kWh is multiplied by to convert it to the unit.
+func (u Unit) factor() float64 {
+	switch u {
+	case CentPerKwh:
+		return 100
+	case EurPerMwh:
+		return 1000
+	}
+	return 1
+}
+
+// Taxes are the electricity tax and VAT on the energy price, as fractions.
+type Taxes struct {
+	ElectricityTax float64
+	Vat            float64
+}
+
+// TaxSchedule gets the taxes in effect on a day.
+type TaxSchedule interface {
+	TaxesAt(t time.Time) (Taxes, error)
+}
+
+// Output is how prices are returned: their unit, whether the electricity tax and VAT are included and the time zone of their times.
+// The electricity tax is applied as a percentage, as its minimum per kWh only applies to a whole bill.
+// A nil Taxes returns the prices without taxes and a nil Location keeps the times in UTC.
+type Output struct {
+	Unit     Unit
+	Taxes    TaxSchedule
+	Location *time.Location
+}
+
+// ParseOutput parses the unit, whether to include taxes and the time zone. Empty values are left as the defaults.
+// Taxes can only be included when there is a schedule to take them from.
+func ParseOutput(unit string, taxes string, timezone string, schedule TaxSchedule) (Output, error) {
+	output := Output{Unit: EurPerKwh}
+	switch Unit(unit) {
+	case "":
+	case EurPerKwh, CentPerKwh, EurPerMwh:
+		output.Unit = Unit(unit)
+	default:
+		return Output{}, fmt.Errorf("%w: unknown unit %q, use eur-kwh, cent-kwh or eur-mwh", ErrInvalidOutput, unit)
+	}
+
+	switch taxes {
+	case "", "false":
+	case "true":
+		if schedule == nil {
+			return Output{}, fmt.Errorf("%w: taxes are not configured", ErrInvalidOutput)
+		}
+		output.Taxes = schedule
+	default:
+		return Output{}, fmt.Errorf("%w: taxes must be true or false", ErrInvalidOutput)
+	}
+
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return Output{}, fmt.Errorf("%w: unknown time zone %q", ErrInvalidOutput, timezone)
+		}
+		output.Location = loc
+	}
+
+	return output, nil
+}
+
+// Factor is what a price in €/kWh on the day is multiplied by to return it.
+func (o Output) Factor(day time.Time) (float64, error) {
+	factor := o.Unit.factor()
+	if o.Taxes != nil {
+		taxes, err := o.Taxes.TaxesAt(day)
+		if err != nil {
+			return 0, err
+		}
+		factor *= (1 + taxes.ElectricityTax) * (1 + taxes.Vat)
+	}
+	return factor, nil
+}
+
+// DayFactor is the Factor for a day given in the format yyyy-MM-dd.
+func (o Output) DayFactor(day string) (float64, error) {
+	if o.Taxes == nil {
+		return o.Unit.factor(), nil
+	}
+	d, err := date.ParseDate(day)
+	if err != nil {
+		return 0, err
+	}
+	return o.Factor(d)
+}
+
+// Time returns the time in the output time zone.
+func (o Output) Time(t time.Time) time.Time {
+	if o.Location == nil {
+		return t
+	}
+	return t.In(o.Location)
+}
+
+// Prices returns a copy of the prices in the output unit and time zone.
+func (o Output) Prices(prices []Price) ([]Price, error) {
+	result := make([]Price, len(prices))
+	for i, p := range prices {
+		factor, err := o.Factor(p.DateTime)
+		if err != nil {
+			return nil, err
+		}
+		p.DateTime = o.Time(p.DateTime)
+		p.Price *= factor
+		if p.Components != nil {
+			components := p.Components.scale(factor)
+			p.Components = &components
+		}
+		if p.Anomalies != nil {
+			p.Anomalies, err = o.Anomalies(p.Anomalies)
+			if err != nil {
+				return nil, err
+			}
+		}
+		result[i] = p
+	}
+	return result, nil
+}
+
+// Periods returns a copy of the periods in the output unit and time zone.
+func (o Output) Periods(periods [][]Price) ([][]Price, error) {
+	result := make([][]Price, len(periods))
+	for i, period := range periods {
+		p, err := o.Prices(period)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = p
+	}
+	return result, nil
+}
+
+// Anomalies returns a copy of the anomalies in the output unit and time zone.
+func (o Output) Anomalies(anomalies []Anomaly) ([]Anomaly, error) {
+	result := make([]Anomaly, len(anomalies))
+	for i, a := range anomalies {
+		factor, err := o.Factor(a.DateTime)
+		if err != nil {
+			return nil, err
+		}
+		a.DateTime = o.Time(a.DateTime)
+		a.Price *= factor
+		a.Baseline *= factor
+		result[i] = a
+	}
+	return result, nil
+}
+
+// DailyAverages returns a copy of the averages in the output unit.
+func (o Output) DailyAverages(averages []DailyAverage) ([]DailyAverage, error) {
+	result := make([]DailyAverage, len(averages))
+	for i, a := range averages {
+		factor, err := o.DayFactor(a.Date)
+		if err != nil {
+			return nil, err
+		}
+		a.Average *= factor
+		result[i] = a
+	}
+	return result, nil
+}
+
+// DailyInfo returns a copy of the day's info in the output unit and time zone.
+// The averages and thresholds use the taxes in effect on the day. The percentile ranks are not prices so are left as they are.
+func (o Output) DailyInfo(info DailyPriceInfo, day time.Time) (DailyPriceInfo, error) {
+	factor, err := o.Factor(day)
+	if err != nil {
+		return DailyPriceInfo{}, err
+	}
+
+	if info.Prices, err = o.Prices(info.Prices); err != nil {
+		return DailyPriceInfo{}, err
+	}
+	if info.CheapPeriods, err = o.Periods(info.CheapPeriods); err != nil {
+		return DailyPriceInfo{}, err
+	}
+	if info.ExpensivePeriods, err = o.Periods(info.ExpensivePeriods); err != nil {
+		return DailyPriceInfo{}, err
+	}
+	if info.Anomalies, err = o.Anomalies(info.Anomalies); err != nil {
+		return DailyPriceInfo{}, err
+	}
+
+	info.DayAverage *= factor
+	info.ThirtyDayAverage *= factor
+	if info.Components != nil {
+		components := info.Components.scale(factor)
+		info.Components = &components
+	}
+
+	// Only the absolute thresholds are prices
+	if info.Rating.Method == "" || info.Rating.Method == AbsoluteBand || info.Rating.Method == SameWeekday {
+		info.Rating.Threshold *= factor
+	}
+	info.Rating.Baseline *= factor
+	info.Rating.GoodBelow *= factor
+	info.Rating.BadAbove *= factor
+
+	if info.Explanation != nil {
+		explanation := info.Explanation.scale(factor)
+		for i := range explanation.Hours {
+			explanation.Hours[i].DateTime = o.Time(explanation.Hours[i].DateTime)
+		}
+		info.Explanation = &explanation
+	}
+
+	return info, nil
+}
+
+// UpcomingPeriods returns a copy of the upcoming periods in the output unit and time zone.
+func (o Output) UpcomingPeriods(upcoming UpcomingPeriods) (UpcomingPeriods, error) {
+	var err error
+	if upcoming.Prices, err = o.Prices(upcoming.Prices); err != nil {
+		return UpcomingPeriods{}, err
+	}
+	if upcoming.CheapPeriods, err = o.Periods(upcoming.CheapPeriods); err != nil {
+		return UpcomingPeriods{}, err
+	}
+	if upcoming.ExpensivePeriods, err = o.Periods(upcoming.ExpensivePeriods); err != nil {
+		return UpcomingPeriods{}, err
+	}
+	upcoming.Start = o.Time(upcoming.Start)
+	upcoming.End = o.Time(upcoming.End)
+	return upcoming, nil
+}
+
+// Stats returns a copy of the stats in the output unit and time zone, using the taxes in effect on the last day.
+func (o Output) Stats(stats Stats) (Stats, error) {
+	factor, err := o.DayFactor(stats.End)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats.Mean *= factor
+	stats.Median *= factor
+	stats.StdDev *= factor
+	stats.Percentiles = Percentiles{
+		P5:  stats.Percentiles.P5 * factor,
+		P10: stats.Percentiles.P10 * factor,
+		P25: stats.Percentiles.P25 * factor,
+		P75: stats.Percentiles.P75 * factor,
+		P90: stats.Percentiles.P90 * factor,
+		P95: stats.Percentiles.P95 * factor,
+	}
+	stats.Min = PriceAtTime{DateTime: o.Time(stats.Min.DateTime), Price: stats.Min.Price * factor}
+	stats.Max = PriceAtTime{DateTime: o.Time(stats.Max.DateTime), Price: stats.Max.Price * factor}
+	return stats, nil
+}
+
+// Heatmap returns a copy of the heatmap in the output unit, using the taxes in effect on the last day.
+// The hours are always local to Spain.
+func (o Output) Heatmap(heatmap Heatmap) (Heatmap, error) {
+	factor, err := o.DayFactor(heatmap.End)
+	if err != nil {
+		return Heatmap{}, err
+	}
+
+	for d := range heatmap.Mean {
+		for h := range heatmap.Mean[d] {
+			heatmap.Mean[d][h] *= factor
+			heatmap.Median[d][h] *= factor
+		}
+	}
+	return heatmap, nil
+}
+
+// scale
+// Multiply each component by the factor.
+func (c Components) scale(factor float64) Components {
+	return Components{
+		Energy:           c.Energy * factor,
+		Adjustment:       c.Adjustment * factor,
+		CapacityPayments: c.CapacityPayments * factor,
+		Interruptibility: c.Interruptibility * factor,
+		OperatorFees:     c.OperatorFees * factor,
+		TollsAndCharges:  c.TollsAndCharges * factor,
+		TradingCosts:     c.TradingCosts * factor,
+		Other:            c.Other * factor,
+	}
+}
+
+// scale
+// Multiply each price and threshold of the explanation by the factor.
+func (e Explanation) scale(factor float64) Explanation {
+	hours := make([]HourExplanation, len(e.Hours))
+	for i, h := range e.Hours {
+		h.Price *= factor
+		h.CheapThreshold *= factor
+		h.ExpensiveThreshold *= factor
+		hours[i] = h
+	}
+	var centroids []float64
+	for _, c := range e.Centroids {
+		centroids = append(centroids, c*factor)
+	}
+	return Explanation{
+		Strategy:           e.Strategy,
+		ThirtyDayAverage:   e.ThirtyDayAverage * factor,
+		DayAverage:         e.DayAverage * factor,
+		CombinedAverage:    e.CombinedAverage * factor,
+		MinPrice:           e.MinPrice * factor,
+		MaxPrice:           e.MaxPrice * factor,
+		MinVariance:        e.MinVariance * factor,
+		MaxVariance:        e.MaxVariance * factor,
+		CheapVariance:      e.CheapVariance * factor,
+		ExpensiveVariance:  e.ExpensiveVariance * factor,
+		CheapThreshold:     e.CheapThreshold * factor,
+		ExpensiveThreshold: e.ExpensiveThreshold * factor,
+		ExpensiveCutOff:    e.ExpensiveCutOff * factor,
+		Centroids:          centroids,
+		MinLength:          e.MinLength,
+		Hours:              hours,
+	}
+}
@@ -0,0 +1,209 @@
+package price
+
+import (
+	"electricity-prices/pkg/date"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fixedTaxes is a tax schedule with the same taxes every day.
+type fixedTaxes Taxes
+
+func (f fixedTaxes) TaxesAt(t time.Time) (Taxes, error) {
+	return Taxes(f), nil
+}
+
+// failingTaxes is a tax schedule without any taxes.
+type failingTaxes struct{}
+
+func (f failingTaxes) TaxesAt(t time.Time) (Taxes, error) {
+	return Taxes{}, errors.New("no rates in effect")
+}
+
+func TestParseOutput(t *testing.T) {
+	schedule := fixedTaxes{ElectricityTax: 0.05, Vat: 0.21}
+
+	tests := []struct {
+		name         string
+		unit         string
+		taxes        string
+		timezone     string
+		schedule     TaxSchedule
+		expectedUnit Unit
+		expectTaxes  bool
+		expectZone   string
+		expectErr    bool
+	}{
+		{name: "Defaults", expectedUnit: EurPerKwh},
+		{name: "Cents with taxes", unit: "cent-kwh", taxes: "true", schedule: schedule, expectedUnit: CentPerKwh, expectTaxes: true},
+		{name: "Without taxes", unit: "eur-mwh", taxes: "false", schedule: schedule, expectedUnit: EurPerMwh},
+		{name: "Time zone", timezone: "Europe/Madrid", expectedUnit: EurPerKwh, expectZone: "Europe/Madrid"},
+		{name: "Unknown unit", unit: "pence", expectErr: true},
+		{name: "Invalid taxes", taxes: "yes", schedule: schedule, expectErr: true},
+		{name: "Taxes not configured", taxes: "true", expectErr: true},
+		{name: "Unknown time zone", timezone: "Mars/Olympus", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := ParseOutput(tt.unit, tt.taxes, tt.timezone, tt.schedule)
+			if tt.expectErr {
+				if !errors.Is(err, ErrInvalidOutput) {
+					t.Errorf("Expected an invalid output error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if output.Unit != tt.expectedUnit {
+				t.Errorf("Expected unit %s, but got %s", tt.expectedUnit, output.Unit)
+			}
+			if (output.Taxes != nil) != tt.expectTaxes {
+				t.Errorf("Expected taxes to be %t", tt.expectTaxes)
+			}
+			if tt.expectZone != "" && (output.Location == nil || output.Location.String() != tt.expectZone) {
+				t.Errorf("Expected time zone %s, but got %v", tt.expectZone, output.Location)
+			}
+			if tt.expectZone == "" && output.Location != nil {
+				t.Errorf("Expected no time zone, but got %s", output.Location)
+			}
+		})
+	}
+}
+
+func TestOutputPrices(t *testing.T) {
+	hour := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	prices := []Price{{
+		DateTime:   hour,
+		Price:      0.1,
+		Components: &Components{Energy: 0.06, TollsAndCharges: 0.04},
+		Anomalies:  []Anomaly{{DateTime: hour, Type: Spike, Price: 0.1, Baseline: 0.05, Score: 3.5}},
+	}}
+
+	tests := []struct {
+		name     string
+		output   Output
+		expected float64
+	}{
+		{"Default", Output{Unit: EurPerKwh}, 0.1},
+		{"Cents", Output{Unit: CentPerKwh}, 10},
+		{"Megawatt hours", Output{Unit: EurPerMwh}, 100},
+		{"Cents with taxes", Output{Unit: CentPerKwh, Taxes: fixedTaxes{ElectricityTax: 0.05, Vat: 0.2}}, 12.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.output.Prices(prices)
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			factor := tt.expected / 0.1
+			if !floatEquals(result[0].Price, tt.expected) {
+				t.Errorf("Expected %f, but got %f", tt.expected, result[0].Price)
+			}
+			if !floatEquals(result[0].Components.Energy, 0.06*factor) || !floatEquals(result[0].Components.TollsAndCharges, 0.04*factor) {
+				t.Errorf("Expected the components to add up to the price, but got %+v", result[0].Components)
+			}
+			a := result[0].Anomalies[0]
+			if !floatEquals(a.Price, tt.expected) || !floatEquals(a.Baseline, 0.05*factor) || a.Score != 3.5 {
+				t.Errorf("Expected the anomaly prices to be converted but not its score, but got %+v", a)
+			}
+		})
+	}
+
+	// The prices passed in are left unchanged
+	if prices[0].Price != 0.1 || prices[0].Components.Energy != 0.06 || prices[0].Anomalies[0].Price != 0.1 {
+		t.Errorf("Expected the original prices to be unchanged, but got %+v", prices[0])
+	}
+}
+
+func TestOutputTimeZone(t *testing.T) {
+	hour := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	output := Output{Unit: EurPerKwh, Location: date.Location}
+
+	prices, err := output.Prices([]Price{{DateTime: hour, Price: 0.1}})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if got := prices[0].DateTime.Format(time.RFC3339); got != "2024-05-15T12:00:00+02:00" {
+		t.Errorf("Expected the time in Madrid, but got %s", got)
+	}
+	if !prices[0].DateTime.Equal(hour) {
+		t.Errorf("Expected the same instant, but got %s", prices[0].DateTime)
+	}
+}
+
+func TestOutputDailyInfo(t *testing.T) {
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	info := DailyPriceInfo{
+		DayAverage:       0.1,
+		ThirtyDayAverage: 0.12,
+		Prices:           []Price{{DateTime: day, Price: 0.1}},
+		CheapPeriods:     [][]Price{{{DateTime: day, Price: 0.1}}},
+		PercentileRanks:  []PercentileRank{{Window: "30d", DayAverage: 15, Hours: []float64{40}}},
+		Explanation:      &Explanation{CheapThreshold: 0.08, Hours: []HourExplanation{{DateTime: day, Price: 0.1}}},
+	}
+
+	tests := []struct {
+		name              string
+		rating            RatingDetails
+		expectedThreshold float64
+	}{
+		{"Absolute threshold is a price", RatingDetails{Method: AbsoluteBand, Threshold: 0.02, GoodBelow: 0.1}, 2},
+		{"Percentage threshold is not", RatingDetails{Method: PercentageBand, Threshold: 0.2, GoodBelow: 0.1}, 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info.Rating = tt.rating
+			result, err := Output{Unit: CentPerKwh}.DailyInfo(info, day)
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if !floatEquals(result.DayAverage, 10) || !floatEquals(result.ThirtyDayAverage, 12) {
+				t.Errorf("Expected the averages in cents, but got %f and %f", result.DayAverage, result.ThirtyDayAverage)
+			}
+			if !floatEquals(result.Prices[0].Price, 10) || !floatEquals(result.CheapPeriods[0][0].Price, 10) {
+				t.Errorf("Expected the prices in cents")
+			}
+			if result.PercentileRanks[0].DayAverage != 15 || result.PercentileRanks[0].Hours[0] != 40 {
+				t.Errorf("Expected the ranks to be unchanged, but got %+v", result.PercentileRanks[0])
+			}
+			if !floatEquals(result.Rating.Threshold, tt.expectedThreshold) || !floatEquals(result.Rating.GoodBelow, 10) {
+				t.Errorf("Expected a threshold of %f, but got %+v", tt.expectedThreshold, result.Rating)
+			}
+			if !floatEquals(result.Explanation.CheapThreshold, 8) || !floatEquals(result.Explanation.Hours[0].Price, 10) {
+				t.Errorf("Expected the explanation in cents, but got %+v", result.Explanation)
+			}
+		})
+	}
+
+	if info.Prices[0].Price != 0.1 || info.Explanation.CheapThreshold != 0.08 || info.PercentileRanks[0].DayAverage != 15 {
+		t.Error("Expected the original info to be unchanged")
+	}
+}
+
+func TestOutputStats(t *testing.T) {
+	stats := Stats{
+		Start:       "2024-05-01",
+		End:         "2024-05-15",
+		Mean:        0.1,
+		StdDev:      0.02,
+		Percentiles: Percentiles{P5: 0.05, P95: 0.15},
+		Min:         PriceAtTime{DateTime: time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC), Price: 0.01},
+	}
+
+	result, err := Output{Unit: EurPerMwh, Taxes: fixedTaxes{Vat: 0.1}}.Stats(stats)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if !floatEquals(result.Mean, 110) || !floatEquals(result.StdDev, 22) || !floatEquals(result.Percentiles.P95, 165) || !floatEquals(result.Min.Price, 11) {
+		t.Errorf("Expected the stats in €/MWh with VAT, but got %+v", result)
+	}
+
+	if _, err := (Output{Unit: EurPerKwh, Taxes: failingTaxes{}}).Stats(stats); err == nil {
+		t.Error("Expected an error when the taxes are not known")
+	}
+}
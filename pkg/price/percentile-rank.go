@@ -0,0 +1,53 @@
+package price
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RankWindow is a look-back window that prices are ranked against.
+// It is either the given number of days before the day or, if SameMonth is set,
+// the same calendar month in previous years.
+type RankWindow struct {
+	Name      string
+	Days      int
+	SameMonth bool
+}
+
+// sameMonthYears is how many previous years are compared against for the same month window.
+const sameMonthYears = 10
+
+var DefaultRankWindows = []RankWindow{
+	{Name: "30d", Days: 30},
+	{Name: "90d", Days: 90},
+	{Name: "365d", Days: 365},
+	{Name: "sameMonth", SameMonth: true},
+}
+
+// PercentileRank is the percentage of days, and of hours, in a window that were cheaper.
+// A rank of 15 means the day is cheaper than 85% of the days in the window.
+type PercentileRank struct {
+	Window     string    `json:"window"`
+	Days       int       `json:"days"`
+	DayAverage float64   `json:"dayAverage"`
+	Hours      []float64 `json:"hours"`
+}
+
+// ParseRankWindows parses a comma separated list of windows, given as a number of days or sameMonth.
+func ParseRankWindows(s string) ([]RankWindow, error) {
+	var windows []RankWindow
+	for _, w := range strings.Split(s, ",") {
+		w = strings.TrimSpace(w)
+		if w == "sameMonth" {
+			windows = append(windows, RankWindow{Name: w, SameMonth: true})
+			continue
+		}
+		days, err := strconv.Atoi(strings.TrimSuffix(w, "d"))
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("invalid rank window %q", w)
+		}
+		windows = append(windows, RankWindow{Name: fmt.Sprintf("%dd", days), Days: days})
+	}
+	return windows, nil
+}
@@ -0,0 +1,344 @@
+package price
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var ErrInvalidPeriodStrategy = errors.New("invalid period strategy")
+
+const (
+	defaultCheapQuantile     = 25.0
+	defaultExpensiveQuantile = 75.0
+	defaultMinPeriodLength   = 2
+	kMeansIterations         = 100
+)
+
+// PeriodStrategy detects the cheap and expensive periods of a day's prices.
+// The thirty-day average is available to strategies that rate the day against its history.
+// Explain returns the values the periods were detected with and how each hour compared against them.
+type PeriodStrategy interface {
+	Name() string
+	CheapPeriods(prices []Price, thirtyDayAvg float64) [][]Price
+	ExpensivePeriods(prices []Price, thirtyDayAvg float64) [][]Price
+	Explain(prices []Price, thirtyDayAvg float64) Explanation
+}
+
+// VarianceStrategy is the default heuristic, selecting the prices within a variance of the day's minimum or maximum.
+// A day has no expensive periods when its most expensive price is at least CutOff below the thirty-day average.
+// A zero CutOff defaults to the threshold of the default absolute rating band.
+type VarianceStrategy struct {
+	CutOff float64
+}
+
+func (VarianceStrategy) Name() string {
+	return "variance"
+}
+
+func (VarianceStrategy) CheapPeriods(prices []Price, thirtyDayAvg float64) [][]Price {
+	return CalculateCheapPeriods(prices, thirtyDayAvg)
+}
+
+func (s VarianceStrategy) ExpensivePeriods(prices []Price, thirtyDayAvg float64) [][]Price {
+	return calculateExpensivePeriods(prices, thirtyDayAvg, s.cutOff())
+}
+
+func (s VarianceStrategy) Explain(prices []Price, thirtyDayAvg float64) Explanation {
+	return explainVariance(prices, thirtyDayAvg, s.cutOff())
+}
+
+func (s VarianceStrategy) cutOff() float64 {
+	if s.CutOff == 0 {
+		return ratingVariance
+	}
+	return s.CutOff
+}
+
+// QuantileStrategy selects the prices at or below the cheap percentile and at or above the expensive percentile of the day.
+// Zero values default to the 25th and 75th percentiles.
+type QuantileStrategy struct {
+	Cheap     float64
+	Expensive float64
+}
+
+func (QuantileStrategy) Name() string {
+	return "quantile"
+}
+
+func (s QuantileStrategy) CheapPeriods(prices []Price, _ float64) [][]Price {
+	if len(prices) == 0 {
+		return [][]Price{}
+	}
+	threshold, _ := s.thresholds(prices)
+	return selectPeriods(prices, func(p Price) bool { return p.Price <= threshold })
+}
+
+func (s QuantileStrategy) ExpensivePeriods(prices []Price, _ float64) [][]Price {
+	if len(prices) == 0 {
+		return [][]Price{}
+	}
+	_, threshold := s.thresholds(prices)
+	return selectPeriods(prices, func(p Price) bool { return p.Price >= threshold })
+}
+
+func (s QuantileStrategy) Explain(prices []Price, thirtyDayAvg float64) Explanation {
+	explanation := newExplanation(s.Name(), prices, thirtyDayAvg)
+	if len(prices) == 0 {
+		return explanation
+	}
+	explanation.CheapThreshold, explanation.ExpensiveThreshold = s.thresholds(prices)
+	explanation.Hours = explainHours(prices, s.CheapPeriods(prices, thirtyDayAvg), s.ExpensivePeriods(prices, thirtyDayAvg),
+		explanation.CheapThreshold, explanation.ExpensiveThreshold)
+	return explanation
+}
+
+// thresholds
+// Get the prices at the cheap and expensive percentiles of the day.
+func (s QuantileStrategy) thresholds(prices []Price) (float64, float64) {
+	cheap, expensive := s.Cheap, s.Expensive
+	if cheap == 0 {
+		cheap = defaultCheapQuantile
+	}
+	if expensive == 0 {
+		expensive = defaultExpensiveQuantile
+	}
+	values := sortedValues(prices)
+	return Percentile(values, cheap), Percentile(values, expensive)
+}
+
+// KMeansStrategy clusters the day's prices into cheap, normal and expensive bands.
+// A day with a single price level has no bands, so no periods are returned.
+type KMeansStrategy struct{}
+
+func (KMeansStrategy) Name() string {
+	return "kmeans"
+}
+
+func (KMeansStrategy) CheapPeriods(prices []Price, _ float64) [][]Price {
+	centroids, ok := calculateKMeansBands(prices)
+	if !ok {
+		return [][]Price{}
+	}
+	return selectPeriods(prices, func(p Price) bool { return nearestCentroid(centroids, p.Price) == 0 })
+}
+
+func (KMeansStrategy) ExpensivePeriods(prices []Price, _ float64) [][]Price {
+	centroids, ok := calculateKMeansBands(prices)
+	if !ok {
+		return [][]Price{}
+	}
+	return selectPeriods(prices, func(p Price) bool { return nearestCentroid(centroids, p.Price) == len(centroids)-1 })
+}
+
+// Explain uses the midpoints between the centroids as the thresholds. A price at the cheap midpoint is cheap, as ties go
+// to the lower band, and a price must be above the expensive midpoint to be expensive.
+func (s KMeansStrategy) Explain(prices []Price, thirtyDayAvg float64) Explanation {
+	explanation := newExplanation(s.Name(), prices, thirtyDayAvg)
+	centroids, ok := calculateKMeansBands(prices)
+	if ok {
+		explanation.Centroids = centroids
+		explanation.CheapThreshold = (centroids[0] + centroids[1]) / 2
+		explanation.ExpensiveThreshold = (centroids[1] + centroids[2]) / 2
+	}
+	explanation.Hours = explainHours(prices, s.CheapPeriods(prices, thirtyDayAvg), s.ExpensivePeriods(prices, thirtyDayAvg),
+		explanation.CheapThreshold, explanation.ExpensiveThreshold)
+	return explanation
+}
+
+// SmoothedStrategy smooths the periods of another strategy so they last at least MinLength hours.
+// Gaps shorter than MinLength between two periods are filled in and any period still shorter is dropped.
+// A nil Strategy smooths the variance heuristic and a zero MinLength defaults to two hours.
+type SmoothedStrategy struct {
+	Strategy  PeriodStrategy
+	MinLength int
+}
+
+func (SmoothedStrategy) Name() string {
+	return "smoothed"
+}
+
+func (s SmoothedStrategy) CheapPeriods(prices []Price, thirtyDayAvg float64) [][]Price {
+	return s.smooth(prices, s.strategy().CheapPeriods(prices, thirtyDayAvg))
+}
+
+func (s SmoothedStrategy) ExpensivePeriods(prices []Price, thirtyDayAvg float64) [][]Price {
+	return s.smooth(prices, s.strategy().ExpensivePeriods(prices, thirtyDayAvg))
+}
+
+func (s SmoothedStrategy) Explain(prices []Price, thirtyDayAvg float64) Explanation {
+	explanation := s.strategy().Explain(prices, thirtyDayAvg)
+	explanation.Strategy = s.Name()
+	explanation.MinLength = s.minLength()
+	explanation.Hours = explainHours(prices, s.CheapPeriods(prices, thirtyDayAvg), s.ExpensivePeriods(prices, thirtyDayAvg),
+		explanation.CheapThreshold, explanation.ExpensiveThreshold)
+	return explanation
+}
+
+func (s SmoothedStrategy) minLength() int {
+	if s.MinLength == 0 {
+		return defaultMinPeriodLength
+	}
+	return s.MinLength
+}
+
+func (s SmoothedStrategy) strategy() PeriodStrategy {
+	if s.Strategy == nil {
+		return VarianceStrategy{}
+	}
+	return s.Strategy
+}
+
+// smooth
+// Fill in the short gaps between the periods and drop the periods that are still too short.
+func (s SmoothedStrategy) smooth(prices []Price, periods [][]Price) [][]Price {
+	minLength := s.minLength()
+
+	sorted := make([]Price, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].DateTime.Before(sorted[j].DateTime) })
+
+	selected := make([]bool, len(sorted))
+	for _, period := range periods {
+		for _, p := range period {
+			for i := range sorted {
+				if sorted[i].DateTime.Equal(p.DateTime) {
+					selected[i] = true
+				}
+			}
+		}
+	}
+
+	// Fill in the gaps between two selected runs
+	last := -1
+	for i := range sorted {
+		if !selected[i] {
+			continue
+		}
+		if last >= 0 && i-last > 1 && i-last-1 < minLength {
+			for j := last + 1; j < i; j++ {
+				selected[j] = true
+			}
+		}
+		last = i
+	}
+
+	var kept []Price
+	for _, period := range groupPrices(filterSelected(sorted, selected)) {
+		if len(period) >= minLength {
+			kept = append(kept, period...)
+		}
+	}
+	if len(kept) == 0 {
+		return [][]Price{}
+	}
+	return groupPrices(kept)
+}
+
+// ParsePeriodStrategy returns the strategy with the given name. An empty name returns nil so the default is used.
+func ParsePeriodStrategy(name string) (PeriodStrategy, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case VarianceStrategy{}.Name():
+		return VarianceStrategy{}, nil
+	case QuantileStrategy{}.Name():
+		return QuantileStrategy{}, nil
+	case KMeansStrategy{}.Name():
+		return KMeansStrategy{}, nil
+	case SmoothedStrategy{}.Name():
+		return SmoothedStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown strategy %q", ErrInvalidPeriodStrategy, name)
+	}
+}
+
+// selectPeriods
+// Group the consecutive prices that match into periods.
+func selectPeriods(prices []Price, matches func(Price) bool) [][]Price {
+	var selected []Price
+	for _, p := range prices {
+		if matches(p) {
+			selected = append(selected, p)
+		}
+	}
+	if len(selected) == 0 {
+		return [][]Price{}
+	}
+	return groupPrices(selected)
+}
+
+// filterSelected
+// Get the prices whose flag is set.
+func filterSelected(prices []Price, selected []bool) []Price {
+	var result []Price
+	for i, p := range prices {
+		if selected[i] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// sortedValues
+// Get the price values in ascending order.
+func sortedValues(prices []Price) []float64 {
+	values := make([]float64, len(prices))
+	for i, p := range prices {
+		values[i] = p.Price
+	}
+	sort.Float64s(values)
+	return values
+}
+
+// calculateKMeansBands
+// Cluster the prices into three bands, returning the ascending centroids.
+// The centroids start at the minimum, median and maximum so the result is deterministic.
+func calculateKMeansBands(prices []Price) ([]float64, bool) {
+	values := sortedValues(prices)
+	if len(values) == 0 || values[0] == values[len(values)-1] {
+		return nil, false
+	}
+
+	centroids := []float64{values[0], Percentile(values, 50), values[len(values)-1]}
+	for iteration := 0; iteration < kMeansIterations; iteration++ {
+		sums := make([]float64, len(centroids))
+		counts := make([]int, len(centroids))
+		for _, v := range values {
+			c := nearestCentroid(centroids, v)
+			sums[c] += v
+			counts[c]++
+		}
+
+		changed := false
+		for c := range centroids {
+			if counts[c] == 0 {
+				continue
+			}
+			mean := sums[c] / float64(counts[c])
+			if math.Abs(mean-centroids[c]) > 1e-12 {
+				centroids[c] = mean
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	sort.Float64s(centroids)
+	return centroids, true
+}
+
+// nearestCentroid
+// Get the index of the centroid closest to the value, preferring the lower one on a tie.
+func nearestCentroid(centroids []float64, value float64) int {
+	nearest := 0
+	for c := range centroids {
+		if math.Abs(value-centroids[c]) < math.Abs(value-centroids[nearest]) {
+			nearest = c
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,257 @@
+package price
+
+import (
+	"electricity-prices/pkg/date"
+	"errors"
+	"testing"
+	"time"
+)
+
+func periodPrices(values ...float64) []Price {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, date.Location)
+	prices := make([]Price, len(values))
+	for i, v := range values {
+		prices[i] = Price{DateTime: start.Add(time.Duration(i) * time.Hour), Price: v}
+	}
+	return prices
+}
+
+func periodHours(periods [][]Price) [][]int {
+	result := make([][]int, 0)
+	for _, period := range periods {
+		var hours []int
+		for _, p := range period {
+			hours = append(hours, p.DateTime.In(date.Location).Hour())
+		}
+		result = append(result, hours)
+	}
+	return result
+}
+
+func equalHours(a, b [][]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestPeriodStrategies(t *testing.T) {
+	prices := periodPrices(0.05, 0.06, 0.10, 0.11, 0.10, 0.20, 0.21, 0.05)
+
+	testCases := []struct {
+		name              string
+		strategy          PeriodStrategy
+		prices            []Price
+		expectedCheap     [][]int
+		expectedExpensive [][]int
+	}{
+		{
+			name:              "Quantile",
+			strategy:          QuantileStrategy{},
+			prices:            prices,
+			expectedCheap:     [][]int{{0}, {7}},
+			expectedExpensive: [][]int{{5, 6}},
+		},
+		{
+			name:              "Quantile with custom percentiles",
+			strategy:          QuantileStrategy{Cheap: 10, Expensive: 90},
+			prices:            prices,
+			expectedCheap:     [][]int{{0}, {7}},
+			expectedExpensive: [][]int{{6}},
+		},
+		{
+			name:              "K-means",
+			strategy:          KMeansStrategy{},
+			prices:            prices,
+			expectedCheap:     [][]int{{0, 1}, {7}},
+			expectedExpensive: [][]int{{5, 6}},
+		},
+		{
+			name:              "K-means with a flat day",
+			strategy:          KMeansStrategy{},
+			prices:            periodPrices(0.1, 0.1, 0.1),
+			expectedCheap:     [][]int{},
+			expectedExpensive: [][]int{},
+		},
+		{
+			name:              "Smoothed drops short periods",
+			strategy:          SmoothedStrategy{Strategy: QuantileStrategy{Cheap: 40}},
+			prices:            prices,
+			expectedCheap:     [][]int{{0, 1}},
+			expectedExpensive: [][]int{{5, 6}},
+		},
+		{
+			name:              "Smoothed fills short gaps",
+			strategy:          SmoothedStrategy{Strategy: QuantileStrategy{Cheap: 20}, MinLength: 2},
+			prices:            periodPrices(0.05, 0.20, 0.05, 0.20, 0.20, 0.20, 0.20, 0.20),
+			expectedCheap:     [][]int{{0, 1, 2}},
+			expectedExpensive: [][]int{{1, 2, 3, 4, 5, 6, 7}},
+		},
+		{
+			name:              "No prices",
+			strategy:          QuantileStrategy{},
+			prices:            []Price{},
+			expectedCheap:     [][]int{},
+			expectedExpensive: [][]int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cheap := periodHours(tc.strategy.CheapPeriods(tc.prices, 0.1))
+			if !equalHours(cheap, tc.expectedCheap) {
+				t.Errorf("Expected cheap periods %v, but got %v", tc.expectedCheap, cheap)
+			}
+			expensive := periodHours(tc.strategy.ExpensivePeriods(tc.prices, 0.1))
+			if !equalHours(expensive, tc.expectedExpensive) {
+				t.Errorf("Expected expensive periods %v, but got %v", tc.expectedExpensive, expensive)
+			}
+		})
+	}
+}
+
+func TestVarianceStrategyMatchesHeuristic(t *testing.T) {
+	prices := periodPrices(0.05, 0.06, 0.10, 0.11, 0.10, 0.20, 0.21, 0.05)
+
+	expectedCheap := periodHours(CalculateCheapPeriods(prices, 0.1))
+	if cheap := periodHours(VarianceStrategy{}.CheapPeriods(prices, 0.1)); !equalHours(cheap, expectedCheap) {
+		t.Errorf("Expected cheap periods %v, but got %v", expectedCheap, cheap)
+	}
+	expectedExpensive := periodHours(CalculateExpensivePeriods(prices, 0.1))
+	if expensive := periodHours(VarianceStrategy{}.ExpensivePeriods(prices, 0.1)); !equalHours(expensive, expectedExpensive) {
+		t.Errorf("Expected expensive periods %v, but got %v", expectedExpensive, expensive)
+	}
+}
+
+func TestParsePeriodStrategy(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		expected  string
+		expectErr bool
+	}{
+		{name: "Empty", input: ""},
+		{name: "Variance", input: "variance", expected: "variance"},
+		{name: "Quantile", input: "quantile", expected: "quantile"},
+		{name: "K-means", input: "kmeans", expected: "kmeans"},
+		{name: "Smoothed", input: "smoothed", expected: "smoothed"},
+		{name: "Unknown", input: "median", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			strategy, err := ParsePeriodStrategy(tc.input)
+			if tc.expectErr {
+				if !errors.Is(err, ErrInvalidPeriodStrategy) {
+					t.Errorf("Expected an invalid period strategy error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if tc.expected == "" {
+				if strategy != nil {
+					t.Errorf("Expected no strategy, but got %s", strategy.Name())
+				}
+				return
+			}
+			if strategy.Name() != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, strategy.Name())
+			}
+		})
+	}
+}
+
+func TestPeriodStrategyExplanations(t *testing.T) {
+	prices := periodPrices(0.05, 0.06, 0.10, 0.11, 0.10, 0.20, 0.21, 0.05)
+
+	testCases := []struct {
+		name     string
+		strategy PeriodStrategy
+	}{
+		{"Variance", VarianceStrategy{}},
+		{"Variance with a cut-off", VarianceStrategy{CutOff: 0.2}},
+		{"Quantile", QuantileStrategy{Cheap: 10, Expensive: 90}},
+		{"K-means", KMeansStrategy{}},
+		{"Smoothed", SmoothedStrategy{Strategy: QuantileStrategy{Cheap: 40}}},
+		{"Smoothed variance", SmoothedStrategy{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			explanation := tc.strategy.Explain(prices, 0.1)
+			if explanation.Strategy != tc.strategy.Name() {
+				t.Errorf("Expected the %s strategy, but got %s", tc.strategy.Name(), explanation.Strategy)
+			}
+			if len(explanation.Hours) != len(prices) {
+				t.Fatalf("Expected %d hours, but got %d", len(prices), len(explanation.Hours))
+			}
+
+			// The hours flagged must be the hours in the periods
+			var cheap, expensive []int
+			for i, h := range explanation.Hours {
+				if h.Cheap {
+					cheap = append(cheap, i)
+				}
+				if h.Expensive {
+					expensive = append(expensive, i)
+				}
+			}
+			if expected := flatHours(tc.strategy.CheapPeriods(prices, 0.1)); !equalHours([][]int{cheap}, [][]int{expected}) {
+				t.Errorf("Expected the cheap hours %v, but got %v", expected, cheap)
+			}
+			if expected := flatHours(tc.strategy.ExpensivePeriods(prices, 0.1)); !equalHours([][]int{expensive}, [][]int{expected}) {
+				t.Errorf("Expected the expensive hours %v, but got %v", expected, expensive)
+			}
+		})
+	}
+}
+
+// flatHours
+// Get the hours in the periods as a single list.
+func flatHours(periods [][]Price) []int {
+	var hours []int
+	for _, period := range periodHours(periods) {
+		hours = append(hours, period...)
+	}
+	return hours
+}
+
+func TestPeriodStrategyExplanationThresholds(t *testing.T) {
+	prices := periodPrices(0.05, 0.06, 0.10, 0.11, 0.10, 0.20, 0.21, 0.05)
+
+	quantile := QuantileStrategy{}.Explain(prices, 0.1)
+	if !floatEquals(quantile.CheapThreshold, Percentile(sortedValues(prices), 25)) || !floatEquals(quantile.ExpensiveThreshold, Percentile(sortedValues(prices), 75)) {
+		t.Errorf("Expected the quartiles as thresholds, but got %f and %f", quantile.CheapThreshold, quantile.ExpensiveThreshold)
+	}
+	if quantile.CheapVariance != 0 || quantile.ExpensiveCutOff != 0 {
+		t.Errorf("Expected no variance values for the quantile strategy, but got %+v", quantile)
+	}
+
+	kmeans := KMeansStrategy{}.Explain(prices, 0.1)
+	if len(kmeans.Centroids) != 3 || !floatEquals(kmeans.CheapThreshold, (kmeans.Centroids[0]+kmeans.Centroids[1])/2) {
+		t.Errorf("Expected the midpoints of the centroids as thresholds, but got %+v", kmeans)
+	}
+
+	// A cut-off above the spread of the day leaves no expensive hours
+	variance := VarianceStrategy{CutOff: 0.2}.Explain(periodPrices(0.05, 0.06), 0.3)
+	if !floatEquals(variance.ExpensiveCutOff, 0.1) || variance.Hours[1].Expensive {
+		t.Errorf("Expected a cut-off of 0.1 and no expensive hours, but got %+v", variance)
+	}
+
+	smoothed := SmoothedStrategy{MinLength: 3}.Explain(prices, 0.1)
+	if smoothed.MinLength != 3 || smoothed.CheapVariance == 0 {
+		t.Errorf("Expected the variance values with the minimum length, but got %+v", smoothed)
+	}
+}
@@ -4,26 +4,62 @@ import (
 	"context"
 	"electricity-prices/pkg/date"
 	"electricity-prices/pkg/db"
+	"errors"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"log"
 	"time"
 )
 
+var ErrUnsupportedAggregation = errors.New("aggregation not supported by the server")
+
 type Collection interface {
 	db.Collection[Price]
+	UpsertMany(ctx context.Context, documents []Price) error
 	GetThirtyDayAverage(ctx context.Context, t time.Time) (float64, error)
 	GetLatestPrice(ctx context.Context) (Price, bool, error)
+	GetStats(ctx context.Context, start time.Time, end time.Time) (Stats, error)
 }
 
+// ColReceiver stores a single price series in the collection.
+// If no series is set it defaults to PVPC.
+// If a Session is set only the prices of that intraday session are read.
 type ColReceiver struct {
-	Col *mongo.Collection
+	Col     *mongo.Collection
+	Series  Series
+	Session int
+}
+
+func (r ColReceiver) series() Series {
+	if r.Series == "" {
+		return Pvpc
+	}
+	return r.Series
+}
+
+// seriesFilter
+// Match the documents for this series. PVPC prices stored before series were introduced have no series field.
+func (r ColReceiver) seriesFilter() bson.M {
+	if r.series() == Pvpc {
+		return bson.M{"series": bson.M{"$in": bson.A{nil, Pvpc}}}
+	}
+	if r.Session > 0 {
+		return bson.M{"series": r.series(), "session": r.Session}
+	}
+	return bson.M{"series": r.series()}
+}
+
+// withSeries
+// Restrict the filter to this series.
+func (r ColReceiver) withSeries(filter interface{}) bson.M {
+	return bson.M{"$and": bson.A{filter, r.seriesFilter()}}
 }
 
 func (r ColReceiver) FindOne(ctx context.Context, filter interface{}) (Price, error) {
 	var p Price
-	err := r.Col.FindOne(ctx, filter).Decode(&p)
+	err := r.Col.FindOne(ctx, r.withSeries(filter)).Decode(&p)
 
 	if err != nil {
 		return Price{}, err
@@ -33,7 +69,7 @@ func (r ColReceiver) FindOne(ctx context.Context, filter interface{}) (Price, er
 }
 
 func (r ColReceiver) Find(ctx context.Context, filter interface{}) ([]Price, error) {
-	cur, err := r.Col.Find(ctx, filter)
+	cur, err := r.Col.Find(ctx, r.withSeries(filter))
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +123,7 @@ func (r ColReceiver) InsertMany(ctx context.Context, documents []Price) error {
 		}
 		var documentsInterface []interface{}
 		for _, doc := range documents {
+			doc.Series = r.series()
 			documentsInterface = append(documentsInterface, doc)
 		}
 
@@ -113,6 +150,30 @@ func (r ColReceiver) InsertMany(ctx context.Context, documents []Price) error {
 	return nil
 }
 
+// UpsertMany stores the prices, replacing any existing price for the same hour and session.
+// The intraday markets are published through the day, so their latest day is stored again as it is filled in.
+func (r ColReceiver) UpsertMany(ctx context.Context, documents []Price) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(documents))
+	for i, doc := range documents {
+		doc.Series = r.series()
+		var session interface{}
+		if doc.Session > 0 {
+			session = doc.Session
+		}
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"series": doc.Series, "dateTime": doc.DateTime, "session": session}).
+			SetReplacement(doc).
+			SetUpsert(true)
+	}
+
+	_, err := r.Col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
 func (r ColReceiver) Aggregate(ctx context.Context, pipeline interface{}) (*mongo.Cursor, error) {
 	cursor, err := r.Col.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -127,12 +188,12 @@ func (r ColReceiver) GetThirtyDayAverage(ctx context.Context, t time.Time) (floa
 
 	// Define the aggregation pipeline
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{
+		{{Key: "$match", Value: r.withSeries(bson.M{
 			"dateTime": bson.M{
 				"$gte": start,
 				"$lte": end,
 			},
-		}}},
+		})}},
 		{{Key: "$group", Value: bson.M{
 			"_id": nil,
 			"averagePrice": bson.M{
@@ -175,6 +236,7 @@ func (r ColReceiver) GetThirtyDayAverage(ctx context.Context, t time.Time) (floa
 func (r ColReceiver) GetLatestPrice(ctx context.Context) (Price, bool, error) {
 	// Define the aggregation pipeline
 	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: r.seriesFilter()}},
 		{{Key: "$sort", Value: bson.M{
 			"dateTime": -1,
 		}}},
@@ -203,3 +265,89 @@ func (r ColReceiver) GetLatestPrice(ctx context.Context) (Price, bool, error) {
 
 	return Price{}, true, nil
 }
+
+// GetStats
+// Calculate the descriptive statistics of the prices between start and end using the aggregation framework.
+// The prices are pushed in order so the median and percentiles are calculated exactly, as they are in memory.
+// The $top accumulator needs MongoDB 5.2 or later, older servers return ErrUnsupportedAggregation.
+func (r ColReceiver) GetStats(ctx context.Context, start time.Time, end time.Time) (Stats, error) {
+	// Define the aggregation pipeline
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: r.withSeries(bson.M{
+			"dateTime": bson.M{
+				"$gte": start,
+				"$lte": end,
+			},
+		})}},
+		{{Key: "$sort", Value: bson.D{{Key: "price", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    nil,
+			"count":  bson.M{"$sum": 1},
+			"mean":   bson.M{"$avg": "$price"},
+			"stdDev": bson.M{"$stdDevPop": "$price"},
+			"values": bson.M{"$push": "$price"},
+			"min": bson.M{"$top": bson.M{
+				"sortBy": bson.D{{Key: "price", Value: 1}, {Key: "dateTime", Value: 1}},
+				"output": bson.M{"dateTime": "$dateTime", "price": "$price"},
+			}},
+			"max": bson.M{"$top": bson.M{
+				"sortBy": bson.D{{Key: "price", Value: -1}, {Key: "dateTime", Value: 1}},
+				"output": bson.M{"dateTime": "$dateTime", "price": "$price"},
+			}},
+			"nonPositiveHours": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$lte": bson.A{"$price", 0}}, 1, 0}}},
+		}}},
+	}
+
+	cursor, err := r.Aggregate(ctx, pipeline)
+	if isUnsupportedOperator(err) {
+		return Stats{}, fmt.Errorf("%w: %v", ErrUnsupportedAggregation, err)
+	}
+	if err != nil {
+		return Stats{}, err
+	}
+
+	defer func(cursor *mongo.Cursor, ctx context.Context) {
+		err := cursor.Close(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(cursor, ctx)
+
+	var result struct {
+		Count            int         `bson:"count"`
+		Mean             float64     `bson:"mean"`
+		StdDev           float64     `bson:"stdDev"`
+		Values           []float64   `bson:"values"`
+		Min              PriceAtTime `bson:"min"`
+		Max              PriceAtTime `bson:"max"`
+		NonPositiveHours int         `bson:"nonPositiveHours"`
+	}
+	if !cursor.Next(ctx) {
+		return Stats{}, cursor.Err()
+	}
+	if err = cursor.Decode(&result); err != nil {
+		return Stats{}, err
+	}
+
+	return Stats{
+		Count:            result.Count,
+		Mean:             result.Mean,
+		Median:           Percentile(result.Values, 50),
+		StdDev:           result.StdDev,
+		Percentiles:      calculatePercentiles(result.Values),
+		Min:              PriceAtTime{DateTime: result.Min.DateTime.UTC(), Price: result.Min.Price},
+		Max:              PriceAtTime{DateTime: result.Max.DateTime.UTC(), Price: result.Max.Price},
+		NonPositiveHours: result.NonPositiveHours,
+	}, nil
+}
+
+// isUnsupportedOperator
+// Check if the server rejected the pipeline because it doesn't know one of its operators.
+func isUnsupportedOperator(err error) bool {
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) {
+		return false
+	}
+	// 15952 is an unknown group operator and 168 an unknown expression operator
+	return cmdErr.Code == 15952 || cmdErr.Code == 168
+}
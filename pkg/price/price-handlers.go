@@ -1,16 +1,108 @@
 package price
 
 import (
+	"bytes"
 	"electricity-prices/pkg/api"
 	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/i18n"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Handler serves the price endpoints. Taxes is optional and is needed to return prices with taxes.
 type Handler struct {
-	PriceService Service
+	PriceService   Service
+	SeriesServices map[Series]Service
+	Taxes          TaxSchedule
+}
+
+// getService
+// Get the service for the series, market and intraday session requested, defaulting to the PVPC day-ahead prices.
+// An intraday hour has a price for each session that covered it, so a session is required unless allSessions is set.
+func (h *Handler) getService(c *gin.Context, allSessions bool) (Service, error) {
+	market, ok := ParseMarket(c.DefaultQuery("market", string(DayAhead)))
+	if !ok {
+		return nil, ErrUnknownSeries
+	}
+	series, ok := market.SeriesFor(Series(c.DefaultQuery("series", string(Pvpc))))
+	if !ok {
+		return nil, ErrUnknownSeries
+	}
+
+	// Only the intraday auctions have sessions
+	if sessionStr := c.Query("session"); sessionStr != "" {
+		session, err := strconv.Atoi(sessionStr)
+		if market != Intraday || err != nil || session < 1 || session > IntradaySessions {
+			return nil, ErrUnknownSeries
+		}
+		series = series.WithSession(session)
+	} else if market == Intraday && !allSessions {
+		return nil, ErrSessionRequired
+	}
+
+	if series == Pvpc {
+		return h.PriceService, nil
+	}
+	service, ok := h.SeriesServices[series]
+	if !ok {
+		return nil, ErrUnknownSeries
+	}
+	return service, nil
+}
+
+// getOutput
+// Parse the unit, taxes and time zone requested, defaulting to €/kWh without taxes in UTC.
+// The electricity tax and VAT are Spanish consumer taxes so can only be added to the PVPC day-ahead prices.
+func (h *Handler) getOutput(c *gin.Context) (Output, error) {
+	output, err := ParseOutput(c.Query("unit"), c.Query("taxes"), c.Query("timezone"), h.Taxes)
+	if err != nil {
+		return Output{}, err
+	}
+	pvpc := c.DefaultQuery("series", string(Pvpc)) == string(Pvpc) && c.DefaultQuery("market", string(DayAhead)) == string(DayAhead)
+	if output.Taxes != nil && !pvpc {
+		return Output{}, fmt.Errorf("%w: taxes can only be added to the pvpc day-ahead prices", ErrInvalidOutput)
+	}
+	return output, nil
+}
+
+// getFormat
+// Parse the response format from the format query param or the Accept header, defaulting to JSON.
+func getFormat(c *gin.Context) (Format, error) {
+	return ParseFormat(c.Query("format"), c.GetHeader("Accept"))
+}
+
+// respond
+// Write the body as JSON or its tables as CSV or XLSX. The spreadsheet formats are downloaded as the file name
+// with the format's extension and CSV uses the decimal separator of the locale query param or Accept-Language header.
+func respond(c *gin.Context, format Format, filename string, body any, tables func() []Table) {
+	if format == JSON {
+		c.IndentedJSON(http.StatusOK, body)
+		return
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if format == CSV {
+		err = WriteCSV(&buf, tables(), DecimalSeparator(ParseLocale(c.Query("locale"), c.GetHeader("Accept-Language"))))
+	} else {
+		err = WriteXLSX(&buf, tables())
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// includeComponents
+// Returns true if the price component breakdown was requested.
+func includeComponents(c *gin.Context) bool {
+	return c.Query("components") == "true"
 }
 
 // GetPrices @Summary Get price info
@@ -18,7 +110,18 @@ type Handler struct {
 // @Tags Price
 // @ID get-prices
 // @Produce  json
+// @Produce  text/csv
+// @Produce  application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param date query string false "Date in format yyyy-MM-dd"
+// @Param series query string false "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured"
+// @Param market query string false "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series"
+// @Param session query int false "Intraday auction session, 1 to 3. Defaults to every session"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT, only for the pvpc day-ahead prices"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Param components query bool false "Include the breakdown of each price into its components"
+// @Param format query string false "Response format, json (default), csv or xlsx. Defaults to the Accept header"
+// @Param locale query string false "Locale of the CSV decimal separator, such as es. Defaults to the Accept-Language header"
 // @Success 200 {object} []price.Price
 // @Failure 400 {object} api.ErrorResponse
 // @Failure 500 {object} api.ErrorResponse
@@ -35,17 +138,47 @@ func (h *Handler) GetPrices(c *gin.Context) {
 		return
 	}
 
+	// Get the service for the requested series
+	service, err := h.getService(c, true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := h.getOutput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the format to return the response in
+	format, err := getFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
 	// Get the context from the request
 	ctx := c.Request.Context()
 
 	// Get the prices from the database
-	prices, err := h.PriceService.GetDailyPrices(ctx, d)
+	prices, err := service.GetDailyPrices(ctx, d)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if !includeComponents(c) {
+		prices = WithoutComponents(prices)
+	}
+
+	prices, err = output.Prices(prices)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, prices)
+	respond(c, format, "prices-"+dateStr, prices, func() []Table { return PricesTables(prices) })
 }
 
 // GetThirtyDayAverages @Summary Get daily averages
@@ -53,7 +186,17 @@ func (h *Handler) GetPrices(c *gin.Context) {
 // @Tags Price
 // @ID get-daily-averages
 // @Produce  json
+// @Produce  text/csv
+// @Produce  application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param date query string false "Date in format yyyy-MM-dd"
+// @Param series query string false "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured"
+// @Param market query string false "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series"
+// @Param session query int false "Intraday auction session, 1 to 3. Required for the intraday market"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT, only for the pvpc day-ahead prices"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Param format query string false "Response format, json (default), csv or xlsx. Defaults to the Accept header"
+// @Param locale query string false "Locale of the CSV decimal separator, such as es. Defaults to the Accept-Language header"
 // @Success 200 {object} []price.DailyAverage
 // @Failure 400 {object} api.ErrorResponse
 // @Failure 500 {object} api.ErrorResponse
@@ -70,24 +213,67 @@ func (h *Handler) GetThirtyDayAverages(c *gin.Context) {
 		return
 	}
 
+	// Get the service for the requested series
+	service, err := h.getService(c, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := h.getOutput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the format to return the response in
+	format, err := getFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
 	// Get the context from the request
 	ctx := c.Request.Context()
 
-	averages, err := h.PriceService.GetDailyAverages(ctx, d, 30)
+	averages, err := service.GetDailyAverages(ctx, d, 30)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, averages)
+	averages, err = output.DailyAverages(averages)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	respond(c, format, "averages-"+dateStr, averages, func() []Table { return DailyAveragesTables(averages) })
 }
 
 // GetDailyInfo @Summary Get daily info
-// @Description Returns daily info for the date provided.
+// @Description Returns daily info for the date provided, including percentile ranks of the day and its hours against previous periods. A rank of 15 means cheaper than 85% of the period.
 // @Tags Price
 // @ID get-daily-info
 // @Produce  json
+// @Produce  text/csv
+// @Produce  application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param date query string false "Date in format yyyy-MM-dd"
+// @Param series query string false "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured"
+// @Param market query string false "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series"
+// @Param session query int false "Intraday auction session, 1 to 3. Required for the intraday market"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT, only for the pvpc day-ahead prices"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Param components query bool false "Include the breakdown of the prices into their components"
+// @Param ratingMethod query string false "Day rating method, absolute (default), percentage, percentile or weekday"
+// @Param ratingThreshold query number false "Rating threshold, in €/kWh for absolute and weekday, a fraction of the baseline for percentage or the good percentile for percentile"
+// @Param ratingBaselineDays query int false "Number of days in the rating baseline, defaults to 30"
+// @Param periodStrategy query string false "Cheap and expensive period detection, variance (default), quantile, kmeans or smoothed"
+// @Param explain query bool false "Include the intermediate values of the variance heuristic and the thresholds each hour was compared against"
+// @Param format query string false "Response format, json (default), csv or xlsx. Defaults to the Accept header"
+// @Param locale query string false "Locale of the CSV decimal separator, such as es. Defaults to the Accept-Language header"
 // @Success 200 {object} price.DailyPriceInfo
 // @Failure 400 {object} api.ErrorResponse
 // @Failure 500 {object} api.ErrorResponse
@@ -104,10 +290,45 @@ func (h *Handler) GetDailyInfo(c *gin.Context) {
 		return
 	}
 
+	// Get the service for the requested series
+	service, err := h.getService(c, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := h.getOutput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the format to return the response in
+	format, err := getFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the rating options
+	rating, err := ParseRatingConfig(c.Query("ratingMethod"), c.Query("ratingThreshold"), c.Query("ratingBaselineDays"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the period detection strategy
+	periods, err := ParsePeriodStrategy(c.Query("periodStrategy"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
 	// Get the context from the request
 	ctx := c.Request.Context()
 
-	dailyInfo, err := h.PriceService.GetDailyInfo(ctx, d)
+	dailyInfo, err := service.GetDailyInfo(ctx, d, Options{Rating: rating, Periods: periods, Explain: c.Query("explain") == "true"})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
 		return
@@ -116,6 +337,426 @@ func (h *Handler) GetDailyInfo(c *gin.Context) {
 		c.JSON(http.StatusNotFound, api.ErrorResponse{Message: "No data found for the given date."})
 		return
 	}
+	if !includeComponents(c) {
+		dailyInfo.Prices = WithoutComponents(dailyInfo.Prices)
+		for i := range dailyInfo.CheapPeriods {
+			dailyInfo.CheapPeriods[i] = WithoutComponents(dailyInfo.CheapPeriods[i])
+		}
+		for i := range dailyInfo.ExpensivePeriods {
+			dailyInfo.ExpensivePeriods[i] = WithoutComponents(dailyInfo.ExpensivePeriods[i])
+		}
+		dailyInfo.Components = nil
+	}
+
+	dailyInfo, err = output.DailyInfo(dailyInfo, d)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	respond(c, format, "dailyinfo-"+dateStr, dailyInfo, func() []Table { return DailyInfoTables(dailyInfo, dateStr) })
+}
+
+// GetUpcomingPeriods @Summary Get upcoming periods
+// @Description Returns the cheap and expensive periods from the current hour until the last known price. Once tomorrow's prices are published the periods continue past midnight.
+// @Tags Price
+// @ID get-upcoming-periods
+// @Produce  json
+// @Param series query string false "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured"
+// @Param market query string false "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series"
+// @Param session query int false "Intraday auction session, 1 to 3. Required for the intraday market"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT, only for the pvpc day-ahead prices"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Param components query bool false "Include the breakdown of the prices into their components"
+// @Param periodStrategy query string false "Cheap and expensive period detection, variance (default), quantile, kmeans or smoothed"
+// @Success 200 {object} price.UpcomingPeriods
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /price/periods [get]
+func (h *Handler) GetUpcomingPeriods(c *gin.Context) {
+
+	// Get the service for the requested series
+	service, err := h.getService(c, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := h.getOutput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the period detection strategy
+	periods, err := ParsePeriodStrategy(c.Query("periodStrategy"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	upcoming, err := service.GetUpcomingPeriods(ctx, time.Now(), Options{Periods: periods})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if len(upcoming.Prices) == 0 {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Message: "No data found from the current hour."})
+		return
+	}
+	if !includeComponents(c) {
+		upcoming.Prices = WithoutComponents(upcoming.Prices)
+		for i := range upcoming.CheapPeriods {
+			upcoming.CheapPeriods[i] = WithoutComponents(upcoming.CheapPeriods[i])
+		}
+		for i := range upcoming.ExpensivePeriods {
+			upcoming.ExpensivePeriods[i] = WithoutComponents(upcoming.ExpensivePeriods[i])
+		}
+	}
+
+	upcoming, err = output.UpcomingPeriods(upcoming)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, upcoming)
+}
+
+// GetStats @Summary Get price statistics
+// @Description Returns descriptive statistics of the hourly prices between the start and end dates (inclusive). If no dates are provided it defaults to the last 30 days.
+// @Tags Price
+// @ID get-price-stats
+// @Produce  json
+// @Param start query string false "Start date in format yyyy-MM-dd"
+// @Param end query string false "End date in format yyyy-MM-dd"
+// @Param series query string false "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured"
+// @Param market query string false "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series"
+// @Param session query int false "Intraday auction session, 1 to 3. Required for the intraday market"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT, only for the pvpc day-ahead prices"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Success 200 {object} price.Stats
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /price/stats [get]
+func (h *Handler) GetStats(c *gin.Context) {
+
+	// Get the date strings from the request
+	now := time.Now()
+	endStr := c.DefaultQuery("end", now.Format("2006-01-02"))
+	startStr := c.DefaultQuery("start", now.AddDate(0, 0, -29).Format("2006-01-02"))
+
+	// Parse the date strings
+	start, err := date.ParseDate(startStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse start date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	end, err := date.ParseDate(endStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse end date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "End date must not be before the start date."})
+		return
+	}
+
+	// Get the service for the requested series
+	service, err := h.getService(c, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := h.getOutput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	stats, err := service.GetStats(ctx, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if stats.Count == 0 {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Message: "No data found for the given dates."})
+		return
+	}
+
+	stats, err = output.Stats(stats)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, stats)
+}
+
+// GetAnomalies @Summary Get price anomalies
+// @Description Returns the anomalies flagged when the prices were synced between the start and end dates (inclusive). If no dates are provided it defaults to the last 30 days.
+// @Description SPIKE and DIP hours are far from the rolling 30-day distribution, NON_POSITIVE hours have a zero or negative price and DAY_JUMP marks the first hour of a day whose average moved sharply from the day before.
+// @Tags Price
+// @ID get-price-anomalies
+// @Produce  json
+// @Param start query string false "Start date in format yyyy-MM-dd"
+// @Param end query string false "End date in format yyyy-MM-dd"
+// @Param type query string false "Only return anomalies of this type, SPIKE, DIP, NON_POSITIVE or DAY_JUMP"
+// @Param series query string false "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured"
+// @Param market query string false "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series"
+// @Param session query int false "Intraday auction session, 1 to 3. Required for the intraday market"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT, only for the pvpc day-ahead prices"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Success 200 {object} []price.Anomaly
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /price/anomalies [get]
+func (h *Handler) GetAnomalies(c *gin.Context) {
+
+	// Get the date strings from the request
+	now := time.Now()
+	endStr := c.DefaultQuery("end", now.Format("2006-01-02"))
+	startStr := c.DefaultQuery("start", now.AddDate(0, 0, -29).Format("2006-01-02"))
+
+	// Parse the date strings
+	start, err := date.ParseDate(startStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse start date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	end, err := date.ParseDate(endStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse end date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "End date must not be before the start date."})
+		return
+	}
+
+	// Parse the anomaly type
+	anomalyType := AnomalyType(c.Query("type"))
+	switch anomalyType {
+	case "", Spike, Dip, NonPositive, DayJump:
+	default:
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Unknown anomaly type. Use SPIKE, DIP, NON_POSITIVE or DAY_JUMP."})
+		return
+	}
+
+	// Get the service for the requested series
+	service, err := h.getService(c, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := h.getOutput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	anomalies, err := service.GetAnomalies(ctx, date.StartOfDay(start), date.StartOfDay(end).AddDate(0, 0, 1).Add(-time.Second))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	filtered := make([]Anomaly, 0, len(anomalies))
+	for _, a := range anomalies {
+		if anomalyType == "" || a.Type == anomalyType {
+			filtered = append(filtered, a)
+		}
+	}
+
+	filtered, err = output.Anomalies(filtered)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, filtered)
+}
+
+// GetHeatmap @Summary Get price heatmap
+// @Description Returns the mean and median price for each weekday (Monday first) and local hour between the start and end dates (inclusive). If no dates are provided it defaults to the last 365 days.
+// @Description The days included can be filtered by month, season or day type. Holidays are weekends and national holidays.
+// @Tags Price
+// @ID get-price-heatmap
+// @Produce  json
+// @Produce  text/csv
+// @Produce  application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param start query string false "Start date in format yyyy-MM-dd"
+// @Param end query string false "End date in format yyyy-MM-dd"
+// @Param month query int false "Month to include, 1 to 12"
+// @Param season query string false "Season to include, winter, spring, summer or autumn"
+// @Param dayType query string false "Day type to include, working or holiday"
+// @Param format query string false "Response format, json (default), csv or xlsx. Defaults to the Accept header"
+// @Param locale query string false "Locale of the CSV decimal separator, such as es. Defaults to the Accept-Language header"
+// @Param series query string false "Price series, pvpc (default), surplus, omie-es, omie-pt or entsoe-{zone} when configured"
+// @Param market query string false "Market, day-ahead (default), intraday or continuous. The intraday markets use the zone of the series"
+// @Param session query int false "Intraday auction session, 1 to 3. Required for the intraday market"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT, only for the pvpc day-ahead prices"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Success 200 {object} price.Heatmap
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /price/heatmap [get]
+func (h *Handler) GetHeatmap(c *gin.Context) {
+
+	// Get the date strings from the request
+	now := time.Now()
+	endStr := c.DefaultQuery("end", now.Format("2006-01-02"))
+	startStr := c.DefaultQuery("start", now.AddDate(0, 0, -364).Format("2006-01-02"))
+
+	// Parse the date strings
+	start, err := date.ParseDate(startStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse start date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	end, err := date.ParseDate(endStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse end date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "End date must not be before the start date."})
+		return
+	}
+
+	// Parse the filters
+	var filter HeatmapFilter
+	if monthStr := c.Query("month"); monthStr != "" {
+		month, err := strconv.Atoi(monthStr)
+		if err != nil || month < 1 || month > 12 {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Month must be a number from 1 to 12."})
+			return
+		}
+		filter.Month = time.Month(month)
+	}
+	filter.Season = Season(c.Query("season"))
+	if filter.Season != "" && filter.Season != Winter && filter.Season != Spring && filter.Season != Summer && filter.Season != Autumn {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Season must be winter, spring, summer or autumn."})
+		return
+	}
+	filter.DayType = DayType(c.Query("dayType"))
+	if filter.DayType != "" && filter.DayType != WorkingDay && filter.DayType != Holiday {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Day type must be working or holiday."})
+		return
+	}
+	format, err := getFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the service for the requested series
+	service, err := h.getService(c, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := h.getOutput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	heatmap, err := service.GetHeatmap(ctx, start, end, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	heatmap, err = output.Heatmap(heatmap)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	respond(c, format, "heatmap-"+startStr+"-"+endStr, heatmap, func() []Table { return HeatmapTables(heatmap) })
+}
+
+// GetCalendar @Summary Get calendar of cheap and expensive periods
+// @Description Returns an iCalendar feed with an event for each cheap and expensive period of today and tomorrow, to subscribe to from a calendar app. Each event's summary includes the average price of the period in cents/kWh.
+// @Description The periods are detected over today's and tomorrow's prices together with the variance heuristic, so they continue past midnight once tomorrow's prices are published.
+// @Tags Price
+// @ID get-price-calendar
+// @Produce  text/calendar
+// @Param lang query string false "Language in format es or en"
+// @Param zone query string false "Bidding zone, es (default) for the PVPC, pt for the OMIE price of Portugal or an ENTSO-E zone such as fr when configured"
+// @Param type query string false "Period type, cheap or expensive. Defaults to both"
+// @Success 200 {string} string
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /price/calendar.ics [get]
+func (h *Handler) GetCalendar(c *gin.Context) {
+
+	// Parse language from request
+	lang := i18n.ParseLanguage(c.DefaultQuery("lang", "es"))
+
+	// Get the service for the requested zone
+	zone := c.DefaultQuery("zone", "es")
+	series := ZoneSeries(zone)
+	service := h.PriceService
+	if series != Pvpc {
+		var ok bool
+		if service, ok = h.SeriesServices[series]; !ok {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Unknown zone."})
+			return
+		}
+	}
+
+	// Parse the period types to include
+	types, ok := ParsePeriodTypes(c.Query("type"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Type must be cheap or expensive."})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	// Get the prices for today and tomorrow
+	now := time.Now()
+	today := date.StartOfDay(now)
+	prices, err := service.GetPrices(ctx, today, today.AddDate(0, 0, 2).Add(-time.Second))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	thirtyDayAvg, err := service.GetThirtyDayAverage(ctx, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
 
-	c.IndentedJSON(http.StatusOK, dailyInfo)
+	events := CalculateCalendarEvents(prices, thirtyDayAvg, types)
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(WriteCalendar(events, zone, lang, now)))
 }
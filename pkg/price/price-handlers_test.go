@@ -0,0 +1,166 @@
+package price
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serve
+// Call the handler with a GET request to the URL and return the response.
+func serve(handler gin.HandlerFunc, url string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+	handler(c)
+	return w
+}
+
+func TestGetDailyInfoUnitKeepsPercentileRanks(t *testing.T) {
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+	service := &MockPriceService{
+		MockGetDailyInfoResult: &[]DailyPriceInfo{{
+			DayAverage:      0.1,
+			Prices:          []Price{{DateTime: day, Price: 0.1}},
+			PercentileRanks: []PercentileRank{{Window: "30d", Days: 30, DayAverage: 15, Hours: []float64{40}}},
+		}},
+		MockGetDailyInfoError: &[]error{},
+	}
+	handler := Handler{PriceService: service}
+
+	w := serve(handler.GetDailyInfo, "/api/v1/price/dailyinfo?date=2024-05-15&unit=cent-kwh")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, but got %d: %s", w.Code, w.Body.String())
+	}
+
+	var info DailyPriceInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+		t.Fatalf("Failed to decode the response: %s", err)
+	}
+	if !floatEquals(info.DayAverage, 10) || !floatEquals(info.Prices[0].Price, 10) {
+		t.Errorf("Expected the prices in cents, but got %f and %f", info.DayAverage, info.Prices[0].Price)
+	}
+	if rank := info.PercentileRanks[0]; rank.DayAverage != 15 || rank.Hours[0] != 40 {
+		t.Errorf("Expected the percentile ranks to be unchanged, but got %+v", rank)
+	}
+}
+
+func TestGetPricesTaxesOnlyForPvpc(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{"PVPC", "taxes=true", http.StatusOK},
+		{"PVPC without taxes", "series=omie-es&taxes=false", http.StatusOK},
+		{"OMIE", "series=omie-es&taxes=true", http.StatusBadRequest},
+		{"Surplus", "series=surplus&taxes=true", http.StatusBadRequest},
+		{"Intraday", "market=intraday&taxes=true", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &MockPriceService{
+				MockGetDailyPricesResult: &[][]Price{{{DateTime: time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC), Price: 0.1}}},
+				MockGetDailyPricesError:  &[]error{},
+			}
+			handler := Handler{
+				PriceService: service,
+				SeriesServices: map[Series]Service{
+					OmieSpain:     service,
+					Surplus:       service,
+					IntradaySpain: service,
+				},
+				Taxes: fixedTaxes{ElectricityTax: 0.05, Vat: 0.21},
+			}
+
+			w := serve(handler.GetPrices, "/api/v1/price?date=2024-05-15&"+tt.query)
+			if w.Code != tt.expected {
+				t.Errorf("Expected status %d, but got %d: %s", tt.expected, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestIntradaySessionRequiredForDailyEndpoints(t *testing.T) {
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		handler  func(h *Handler) gin.HandlerFunc
+		url      string
+		expected int
+	}{
+		{"Prices without a session", func(h *Handler) gin.HandlerFunc { return h.GetPrices }, "/api/v1/price?date=2024-05-15&market=intraday", http.StatusOK},
+		{"Daily info without a session", func(h *Handler) gin.HandlerFunc { return h.GetDailyInfo }, "/api/v1/price/dailyinfo?date=2024-05-15&market=intraday", http.StatusBadRequest},
+		{"Daily info with a session", func(h *Handler) gin.HandlerFunc { return h.GetDailyInfo }, "/api/v1/price/dailyinfo?date=2024-05-15&market=intraday&session=2", http.StatusOK},
+		{"Stats without a session", func(h *Handler) gin.HandlerFunc { return h.GetStats }, "/api/v1/price/stats?start=2024-05-01&end=2024-05-15&market=intraday", http.StatusBadRequest},
+		{"Heatmap without a session", func(h *Handler) gin.HandlerFunc { return h.GetHeatmap }, "/api/v1/price/heatmap?start=2024-05-01&end=2024-05-15&market=intraday", http.StatusBadRequest},
+		{"Continuous without a session", func(h *Handler) gin.HandlerFunc { return h.GetDailyInfo }, "/api/v1/price/dailyinfo?date=2024-05-15&market=continuous", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &MockPriceService{
+				MockGetDailyPricesResult: &[][]Price{{{DateTime: day, Price: 0.1}}},
+				MockGetDailyPricesError:  &[]error{},
+				MockGetDailyInfoResult:   &[]DailyPriceInfo{{DayAverage: 0.1, Prices: []Price{{DateTime: day, Price: 0.1}}}},
+				MockGetDailyInfoError:    &[]error{},
+			}
+			handler := &Handler{
+				PriceService: service,
+				SeriesServices: map[Series]Service{
+					IntradaySpain:                service,
+					IntradaySpain.WithSession(2): service,
+					ContinuousSpain:              service,
+				},
+			}
+
+			w := serve(tt.handler(handler), tt.url)
+			if w.Code != tt.expected {
+				t.Fatalf("Expected status %d, but got %d: %s", tt.expected, w.Code, w.Body.String())
+			}
+			if tt.expected == http.StatusBadRequest && !strings.Contains(w.Body.String(), ErrSessionRequired.Error()) {
+				t.Errorf("Expected the session to be required, but got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetHeatmapFormats(t *testing.T) {
+	tests := []struct {
+		name                string
+		query               string
+		expectedStatus      int
+		expectedContentType string
+	}{
+		{"JSON", "", http.StatusOK, JSON.ContentType()},
+		{"CSV", "format=csv", http.StatusOK, CSV.ContentType()},
+		{"Excel", "format=xlsx", http.StatusOK, XLSX.ContentType()},
+		{"Unknown", "format=pdf", http.StatusBadRequest, JSON.ContentType()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &MockPriceService{
+				MockGetHeatmapResult: &[]Heatmap{CalculateHeatmap([]Price{}, HeatmapFilter{})},
+				MockGetHeatmapError:  &[]error{},
+			}
+			handler := Handler{PriceService: service}
+
+			w := serve(handler.GetHeatmap, "/api/v1/price/heatmap?start=2024-01-01&end=2024-01-31&"+tt.query)
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, but got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != tt.expectedContentType {
+				t.Errorf("Expected content type %s, but got %s", tt.expectedContentType, contentType)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package price
 import (
 	"context"
 	"electricity-prices/pkg/date"
+	"errors"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
+	"log"
 	"time"
 )
 
@@ -12,19 +14,38 @@ type Service interface {
 	GetPrice(ctx context.Context, t time.Time) (Price, error)
 	GetPrices(ctx context.Context, start time.Time, end time.Time) ([]Price, error)
 	SavePrices(ctx context.Context, prices []Price) error
+	ReplacePrices(ctx context.Context, prices []Price) error
 	GetDailyPrices(ctx context.Context, t time.Time) ([]Price, error)
 	GetDailyAverages(ctx context.Context, t time.Time, numberOfDays int) ([]DailyAverage, error)
-	GetDailyInfo(ctx context.Context, t time.Time) (DailyPriceInfo, error)
+	GetDailyInfo(ctx context.Context, t time.Time, opts Options) (DailyPriceInfo, error)
 	GetDayRating(ctx context.Context, t time.Time) (DayRating, error)
 	GetDayAverage(ctx context.Context, t time.Time) (float64, error)
-	GetCheapPeriods(ctx context.Context, t time.Time) ([][]Price, error)
-	GetExpensivePeriods(ctx context.Context, t time.Time) ([][]Price, error)
+	GetCheapPeriods(ctx context.Context, t time.Time, opts Options) ([][]Price, error)
+	GetExpensivePeriods(ctx context.Context, t time.Time, opts Options) ([][]Price, error)
+	GetUpcomingPeriods(ctx context.Context, t time.Time, opts Options) (UpcomingPeriods, error)
 	GetThirtyDayAverage(ctx context.Context, t time.Time) (float64, error)
 	GetLatestPrice(ctx context.Context) (Price, bool, error)
+	GetStats(ctx context.Context, start time.Time, end time.Time) (Stats, error)
+	GetHeatmap(ctx context.Context, start time.Time, end time.Time, filter HeatmapFilter) (Heatmap, error)
+	GetAnomalies(ctx context.Context, start time.Time, end time.Time) ([]Anomaly, error)
 }
 
+// Receiver implements the Service using the prices in the Collection.
+// RankWindows are the look-back windows used for percentile ranks and default to DefaultRankWindows.
+// Rating is the deployment's rating method, which defaults to an absolute band around the thirty-day average.
+// Periods is the deployment's period detection strategy, which defaults to the VarianceStrategy.
+// Shapes is optional and labels the daily info and averages with the shape of each day's prices.
 type Receiver struct {
-	Collection Collection
+	Collection  Collection
+	RankWindows []RankWindow
+	Rating      RatingConfig
+	Periods     PeriodStrategy
+	Shapes      ShapeSource
+}
+
+// ShapeSource gets the stored shape labels of the days between start and end (inclusive), keyed by date in the format yyyy-MM-dd.
+type ShapeSource interface {
+	GetShapes(ctx context.Context, start time.Time, end time.Time) (map[string]string, error)
 }
 
 func (r *Receiver) GetPrice(ctx context.Context, t time.Time) (Price, error) {
@@ -60,6 +81,11 @@ func (r *Receiver) SavePrices(ctx context.Context, prices []Price) error {
 	return nil
 }
 
+// ReplacePrices stores the prices, replacing any stored for the same hour and session.
+func (r *Receiver) ReplacePrices(ctx context.Context, prices []Price) error {
+	return r.Collection.UpsertMany(ctx, prices)
+}
+
 func (r *Receiver) GetDailyPrices(ctx context.Context, t time.Time) ([]Price, error) {
 	start, end := date.ParseStartAndEndTimes(t, 1)
 
@@ -88,11 +114,17 @@ func (r *Receiver) GetDailyAverages(ctx context.Context, t time.Time, numberOfDa
 
 	averages := CalculateDailyAverages(prices)
 
+	// Label the days with the shape of their prices
+	shapes := r.getShapes(ctx, xDaysAgo, today)
+	for i := range averages {
+		averages[i].Shape = shapes[averages[i].Date]
+	}
+
 	return averages, nil
 
 }
 
-func (r *Receiver) GetDailyInfo(ctx context.Context, t time.Time) (DailyPriceInfo, error) {
+func (r *Receiver) GetDailyInfo(ctx context.Context, t time.Time, opts Options) (DailyPriceInfo, error) {
 	// Get the prices for the given day
 	prices, err := r.GetDailyPrices(ctx, t)
 	if err != nil {
@@ -107,13 +139,30 @@ func (r *Receiver) GetDailyInfo(ctx context.Context, t time.Time) (DailyPriceInf
 
 	// Get day rating
 	dayAvg := CalculateAverage(prices)
-	dayRating := CalculateDayRating(dayAvg, avgPrice)
+	dayRating, ratingDetails, err := r.rateDay(ctx, t, dayAvg, avgPrice, r.Rating.Override(opts.Rating))
+	if err != nil {
+		return DailyPriceInfo{}, err
+	}
 
 	// Get cheap periods
-	cheapPeriods := CalculateCheapPeriods(prices, avgPrice)
+	strategy := r.periodStrategy(opts)
+	cheapPeriods := strategy.CheapPeriods(prices, avgPrice)
 
 	// Get expensive periods
-	expensivePeriods := CalculateExpensivePeriods(prices, avgPrice)
+	expensivePeriods := strategy.ExpensivePeriods(prices, avgPrice)
+
+	// Get percentile ranks
+	ranks, err := r.getPercentileRanks(ctx, t, prices)
+	if err != nil {
+		return DailyPriceInfo{}, err
+	}
+
+	// Get the explanation of the rating and periods
+	var explanation *Explanation
+	if opts.Explain {
+		e := strategy.Explain(prices, avgPrice)
+		explanation = &e
+	}
 
 	return DailyPriceInfo{
 		Prices:           prices,
@@ -122,6 +171,13 @@ func (r *Receiver) GetDailyInfo(ctx context.Context, t time.Time) (DailyPriceInf
 		DayAverage:       dayAvg,
 		CheapPeriods:     cheapPeriods,
 		ExpensivePeriods: expensivePeriods,
+		Components:       CalculateAverageComponents(prices),
+		PercentileRanks:  ranks,
+		Rating:           ratingDetails,
+		PeriodStrategy:   strategy.Name(),
+		Explanation:      explanation,
+		Anomalies:        CollectAnomalies(prices),
+		Shape:            r.getShapes(ctx, t, t)[date.ParseToLocalDay(t)],
 	}, nil
 }
 
@@ -143,7 +199,10 @@ func (r *Receiver) GetDayRating(ctx context.Context, t time.Time) (DayRating, er
 
 	// Get day rating
 	dayAvg := CalculateAverage(prices)
-	dayRating := CalculateDayRating(dayAvg, avgPrice)
+	dayRating, _, err := r.rateDay(ctx, t, dayAvg, avgPrice, r.Rating)
+	if err != nil {
+		return Nil, err
+	}
 
 	return dayRating, nil
 }
@@ -164,7 +223,7 @@ func (r *Receiver) GetDayAverage(ctx context.Context, t time.Time) (float64, err
 	return dayAvg, nil
 }
 
-func (r *Receiver) GetCheapPeriods(ctx context.Context, t time.Time) ([][]Price, error) {
+func (r *Receiver) GetCheapPeriods(ctx context.Context, t time.Time, opts Options) ([][]Price, error) {
 	// Get the prices for the given day
 	prices, err := r.GetDailyPrices(ctx, t)
 	if err != nil {
@@ -181,12 +240,12 @@ func (r *Receiver) GetCheapPeriods(ctx context.Context, t time.Time) ([][]Price,
 	}
 
 	// Get cheap periods
-	cheapPeriods := CalculateCheapPeriods(prices, avgPrice)
+	cheapPeriods := r.periodStrategy(opts).CheapPeriods(prices, avgPrice)
 
 	return cheapPeriods, nil
 }
 
-func (r *Receiver) GetExpensivePeriods(ctx context.Context, t time.Time) ([][]Price, error) {
+func (r *Receiver) GetExpensivePeriods(ctx context.Context, t time.Time, opts Options) ([][]Price, error) {
 	// Get the prices for the given day
 	prices, err := r.GetDailyPrices(ctx, t)
 	if err != nil {
@@ -203,11 +262,51 @@ func (r *Receiver) GetExpensivePeriods(ctx context.Context, t time.Time) ([][]Pr
 	}
 
 	// Get expensive periods
-	expensivePeriods := CalculateExpensivePeriods(prices, avgPrice)
+	expensivePeriods := r.periodStrategy(opts).ExpensivePeriods(prices, avgPrice)
 
 	return expensivePeriods, nil
 }
 
+// GetUpcomingPeriods returns the cheap and expensive periods from the hour of t until the last known price.
+// The periods are detected over today's and tomorrow's prices together, so they continue past midnight once tomorrow's prices are published.
+// Periods that have already ended are dropped, while a period in progress keeps its start.
+func (r *Receiver) GetUpcomingPeriods(ctx context.Context, t time.Time, opts Options) (UpcomingPeriods, error) {
+	hour := t.Truncate(time.Hour)
+	today := date.StartOfDay(t)
+
+	// Get the prices for today and tomorrow
+	prices, err := r.GetPrices(ctx, today, today.AddDate(0, 0, 2).Add(-time.Second))
+	if err != nil {
+		return UpcomingPeriods{}, err
+	}
+
+	// Get thirty-day average
+	avgPrice, err := r.GetThirtyDayAverage(ctx, t)
+	if err != nil {
+		return UpcomingPeriods{}, err
+	}
+
+	strategy := r.periodStrategy(opts)
+	upcoming := UpcomingPeriods{
+		Start:            hour,
+		PeriodStrategy:   strategy.Name(),
+		Prices:           make([]Price, 0),
+		CheapPeriods:     WithoutPastPeriods(strategy.CheapPeriods(prices, avgPrice), hour),
+		ExpensivePeriods: WithoutPastPeriods(strategy.ExpensivePeriods(prices, avgPrice), hour),
+	}
+	for _, p := range prices {
+		if p.DateTime.Before(hour) {
+			continue
+		}
+		upcoming.Prices = append(upcoming.Prices, p)
+		if end := p.DateTime.Add(time.Hour); end.After(upcoming.End) {
+			upcoming.End = end
+		}
+	}
+
+	return upcoming, nil
+}
+
 func (r *Receiver) GetThirtyDayAverage(ctx context.Context, t time.Time) (float64, error) {
 	return r.Collection.GetThirtyDayAverage(ctx, t)
 }
@@ -218,3 +317,221 @@ func (r *Receiver) GetThirtyDayAverage(ctx context.Context, t time.Time) (float6
 func (r *Receiver) GetLatestPrice(ctx context.Context) (Price, bool, error) {
 	return r.Collection.GetLatestPrice(ctx)
 }
+
+// GetStats returns the descriptive statistics of the prices for the days between start and end (inclusive).
+// The statistics are aggregated by the database where it supports it, otherwise they are calculated in memory.
+func (r *Receiver) GetStats(ctx context.Context, start time.Time, end time.Time) (Stats, error) {
+	from := date.StartOfDay(start)
+	to := date.StartOfDay(end).AddDate(0, 0, 1).Add(-time.Second)
+
+	stats, err := r.Collection.GetStats(ctx, from, to)
+	if errors.Is(err, ErrUnsupportedAggregation) {
+		log.Printf("Failed to aggregate price stats, calculating them in memory: %s", err)
+
+		prices, err := r.GetPrices(ctx, from, to)
+		if err != nil {
+			return Stats{}, err
+		}
+		stats = CalculateStats(prices)
+	} else if err != nil {
+		return Stats{}, err
+	}
+
+	stats.Start = date.ParseToLocalDay(from)
+	stats.End = date.ParseToLocalDay(to)
+	return stats, nil
+}
+
+// GetHeatmap returns the weekday by hour matrix of prices for the days between start and end (inclusive) that match the filter.
+func (r *Receiver) GetHeatmap(ctx context.Context, start time.Time, end time.Time, filter HeatmapFilter) (Heatmap, error) {
+	from := date.StartOfDay(start)
+	to := date.StartOfDay(end).AddDate(0, 0, 1).Add(-time.Second)
+
+	prices, err := r.GetPrices(ctx, from, to)
+	if err != nil {
+		return Heatmap{}, err
+	}
+
+	heatmap := CalculateHeatmap(prices, filter)
+	heatmap.Start = date.ParseToLocalDay(from)
+	heatmap.End = date.ParseToLocalDay(to)
+	return heatmap, nil
+}
+
+// periodStrategy
+// Get the strategy requested in the options, falling back to the deployment's and then the variance heuristic.
+// The variance heuristic's expensive cut-off is set from the active rating config, see withRatingCutOff.
+func (r *Receiver) periodStrategy(opts Options) PeriodStrategy {
+	strategy := opts.Periods
+	if strategy == nil {
+		strategy = r.Periods
+	}
+	if strategy == nil {
+		strategy = VarianceStrategy{}
+	}
+	return withRatingCutOff(strategy, r.Rating.Override(opts.Rating).WithDefaults())
+}
+
+// withRatingCutOff
+// Set the expensive cut-off of a variance heuristic, or of the variance heuristic being smoothed, to the threshold of
+// an absolute rating band, so a day whose prices are all in the good band has no expensive periods.
+// Other rating methods have no threshold in €/kWh so keep the default cut-off.
+func withRatingCutOff(strategy PeriodStrategy, rating RatingConfig) PeriodStrategy {
+	if rating.Method != AbsoluteBand {
+		return strategy
+	}
+	switch s := strategy.(type) {
+	case VarianceStrategy:
+		s.CutOff = rating.Threshold
+		return s
+	case SmoothedStrategy:
+		if s.Strategy == nil {
+			s.Strategy = VarianceStrategy{}
+		}
+		s.Strategy = withRatingCutOff(s.Strategy, rating)
+		return s
+	}
+	return strategy
+}
+
+// GetAnomalies returns the anomalies flagged on the prices between start and end (inclusive).
+func (r *Receiver) GetAnomalies(ctx context.Context, start time.Time, end time.Time) ([]Anomaly, error) {
+	filter := bson.M{
+		"dateTime": bson.M{
+			"$gte": start,
+			"$lte": end,
+		},
+		"anomalies.0": bson.M{"$exists": true},
+	}
+
+	prices, err := r.Collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return CollectAnomalies(prices), nil
+}
+
+// getPercentileRanks
+// Rank the day's prices against the history in each of the look-back windows.
+func (r *Receiver) getPercentileRanks(ctx context.Context, t time.Time, prices []Price) ([]PercentileRank, error) {
+	ranks := make([]PercentileRank, 0)
+	if len(prices) == 0 {
+		return ranks, nil
+	}
+
+	windows := r.RankWindows
+	if windows == nil {
+		windows = DefaultRankWindows
+	}
+
+	// Get the history for the longest window once and share it between the windows
+	day := date.StartOfDay(t)
+	var maxDays int
+	for _, w := range windows {
+		if w.Days > maxDays {
+			maxDays = w.Days
+		}
+	}
+	var history []Price
+	if maxDays > 0 {
+		var err error
+		history, err = r.GetPrices(ctx, day.AddDate(0, 0, -maxDays), day.Add(-time.Second))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, w := range windows {
+		var windowHistory []Price
+		if w.SameMonth {
+			var err error
+			windowHistory, err = r.getSameMonthPrices(ctx, day)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			from := day.AddDate(0, 0, -w.Days)
+			for _, p := range history {
+				if !p.DateTime.Before(from) {
+					windowHistory = append(windowHistory, p)
+				}
+			}
+		}
+		ranks = append(ranks, CalculatePercentileRanks(w, prices, windowHistory))
+	}
+
+	return ranks, nil
+}
+
+// getShapes
+// Get the shape labels of the days between start and end, or none when there is no ShapeSource.
+// The labels only add to the response, so a failure to get them is logged rather than returned.
+func (r *Receiver) getShapes(ctx context.Context, start time.Time, end time.Time) map[string]string {
+	if r.Shapes == nil {
+		return nil
+	}
+	shapes, err := r.Shapes.GetShapes(ctx, start, end)
+	if err != nil {
+		log.Println("Failed to get day shapes: ", err)
+		return nil
+	}
+	return shapes
+}
+
+// getSameMonthPrices
+// Get the prices for the same calendar month in previous years.
+func (r *Receiver) getSameMonthPrices(ctx context.Context, day time.Time) ([]Price, error) {
+	ranges := bson.A{}
+	for years := 1; years <= sameMonthYears; years++ {
+		start := time.Date(day.Year()-years, day.Month(), 1, 0, 0, 0, 0, date.Location)
+		ranges = append(ranges, bson.M{
+			"dateTime": bson.M{
+				"$gte": start,
+				"$lte": start.AddDate(0, 1, 0).Add(-time.Second),
+			},
+		})
+	}
+
+	return r.Collection.Find(ctx, bson.M{"$or": ranges})
+}
+
+// rateDay
+// Rate the day using the config, getting the baseline it needs.
+// The default thirty-day baseline is aggregated by the database, other baselines are calculated from the prices.
+func (r *Receiver) rateDay(ctx context.Context, t time.Time, dayAvg float64, thirtyDayAvg float64, config RatingConfig) (DayRating, RatingDetails, error) {
+	config = config.WithDefaults()
+	if config.BaselineDays == defaultBaselineDays && (config.Method == AbsoluteBand || config.Method == PercentageBand) {
+		rating, details := CalculateRating(config, dayAvg, thirtyDayAvg, nil)
+		return rating, details, nil
+	}
+
+	start, end := date.ParseStartAndEndTimes(t, config.BaselineDays)
+	history, err := r.GetPrices(ctx, start, end)
+	if err != nil {
+		return Nil, RatingDetails{}, err
+	}
+
+	baseline := CalculateAverage(history)
+	var dailyAverages []float64
+	switch config.Method {
+	case PercentileBand:
+		for _, a := range CalculateDailyAverages(history) {
+			dailyAverages = append(dailyAverages, a.Average)
+		}
+	case SameWeekday:
+		// Compare against the same weekday in previous weeks
+		var sameWeekday []Price
+		day := date.ParseToLocalDay(t)
+		for _, p := range history {
+			local := p.DateTime.In(date.Location)
+			if local.Weekday() == t.In(date.Location).Weekday() && date.ParseToLocalDay(local) != day {
+				sameWeekday = append(sameWeekday, p)
+			}
+		}
+		baseline = CalculateAverage(sameWeekday)
+	}
+
+	rating, details := CalculateRating(config, dayAvg, baseline, dailyAverages)
+	return rating, details, nil
+}
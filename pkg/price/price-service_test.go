@@ -358,7 +358,7 @@ func TestGetCheapPeriods(t *testing.T) {
 			mockCollection := &MockCollection{MockFindResult: tt.mockPrices, MockFindErr: tt.mockPricesErr, MockThirtyDayAvg: tt.mockAvg, MockThirtyDayErr: tt.mockAvgErr}
 			service := &Receiver{Collection: mockCollection}
 
-			result, err := service.GetCheapPeriods(ctx, now)
+			result, err := service.GetCheapPeriods(ctx, now, Options{})
 
 			if tt.expectingError {
 				assert.Error(t, err)
@@ -413,7 +413,7 @@ func TestGetExpensivePeriods(t *testing.T) {
 			mockCollection := &MockCollection{MockFindResult: tt.mockPrices, MockFindErr: tt.mockPricesErr, MockThirtyDayAvg: tt.mockAvg, MockThirtyDayErr: tt.mockAvgErr}
 			service := &Receiver{Collection: mockCollection}
 
-			result, err := service.GetExpensivePeriods(ctx, now)
+			result, err := service.GetExpensivePeriods(ctx, now, Options{})
 
 			if tt.expectingError {
 				assert.Error(t, err)
@@ -569,7 +569,7 @@ func TestGetDailyInfo(r *testing.T) {
 			mockCollection := &MockCollection{MockFindResult: tt.mockPrices, MockFindErr: tt.mockPricesErr, MockThirtyDayAvg: tt.mockAvg, MockThirtyDayErr: tt.mockAvgErr}
 			service := &Receiver{Collection: mockCollection}
 
-			result, err := service.GetDailyInfo(ctx, now)
+			result, err := service.GetDailyInfo(ctx, now, Options{})
 
 			if tt.expectingError {
 				assert.Error(t, err)
@@ -586,3 +586,434 @@ func TestGetDailyInfo(r *testing.T) {
 		})
 	}
 }
+
+func TestGetStats(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location)
+
+	tests := []struct {
+		name           string
+		mockStats      *[]Stats
+		mockStatsErr   *[]error
+		mockFind       *[][]Price
+		mockFindErr    *[]error
+		expectedCount  int
+		expectedMean   float64
+		expectingError bool
+	}{
+		{
+			name:          "aggregated by the database",
+			mockStats:     &[]Stats{{Count: 24, Mean: 0.1}},
+			mockStatsErr:  &[]error{},
+			mockFind:      &[][]Price{},
+			mockFindErr:   &[]error{},
+			expectedCount: 24,
+			expectedMean:  0.1,
+		},
+		{
+			name:          "calculated in memory when aggregation is not supported",
+			mockStats:     &[]Stats{},
+			mockStatsErr:  &[]error{ErrUnsupportedAggregation},
+			mockFind:      &[][]Price{{{DateTime: start, Price: 0.1}, {DateTime: start.Add(time.Hour), Price: 0.3}}},
+			mockFindErr:   &[]error{},
+			expectedCount: 2,
+			expectedMean:  0.2,
+		},
+		{
+			name:           "aggregation failure",
+			mockStats:      &[]Stats{},
+			mockStatsErr:   &[]error{context.Canceled},
+			mockFind:       &[][]Price{{{DateTime: start, Price: 0.1}}},
+			mockFindErr:    &[]error{},
+			expectingError: true,
+		},
+		{
+			name:           "failure",
+			mockStats:      &[]Stats{},
+			mockStatsErr:   &[]error{ErrUnsupportedAggregation},
+			mockFind:       &[][]Price{},
+			mockFindErr:    &[]error{errors.New("not found")},
+			expectingError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockStats:      tt.mockStats,
+				MockStatsErr:   tt.mockStatsErr,
+				MockFindResult: tt.mockFind,
+				MockFindErr:    tt.mockFindErr,
+			}
+			service := &Receiver{Collection: mockCollection}
+
+			result, err := service.GetStats(ctx, start, start.AddDate(0, 0, 1))
+
+			if tt.expectingError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedCount, result.Count)
+				assert.InDelta(t, tt.expectedMean, result.Mean, 1e-9)
+				assert.Equal(t, "2023-11-29", result.Start)
+				assert.Equal(t, "2023-11-30", result.End)
+			}
+		})
+	}
+}
+
+func TestGetDailyInfoPercentileRanks(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location)
+
+	prices := []Price{{DateTime: day, Price: 2.0}}
+	history := []Price{
+		{DateTime: day.AddDate(0, 0, -1), Price: 1.0},
+		{DateTime: day.AddDate(0, 0, -20), Price: 3.0},
+		{DateTime: day.AddDate(0, 0, -60), Price: 4.0},
+	}
+	sameMonth := []Price{{DateTime: day.AddDate(-1, 0, 0), Price: 1.0}}
+
+	mockCollection := &MockCollection{
+		MockFindResult:   &[][]Price{prices, history, sameMonth},
+		MockFindErr:      &[]error{},
+		MockThirtyDayAvg: &[]float64{2.0},
+		MockThirtyDayErr: &[]error{},
+	}
+	service := &Receiver{Collection: mockCollection, RankWindows: []RankWindow{
+		{Name: "30d", Days: 30},
+		{Name: "90d", Days: 90},
+		{Name: "sameMonth", SameMonth: true},
+	}}
+
+	result, err := service.GetDailyInfo(ctx, day, Options{})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.PercentileRanks, 3)
+	assert.Equal(t, "30d", result.PercentileRanks[0].Window)
+	assert.Equal(t, 2, result.PercentileRanks[0].Days)
+	assert.InDelta(t, 50.0, result.PercentileRanks[0].DayAverage, 1e-9)
+	assert.Equal(t, 3, result.PercentileRanks[1].Days)
+	assert.InDelta(t, 100.0/3, result.PercentileRanks[1].DayAverage, 1e-9)
+	assert.Equal(t, "sameMonth", result.PercentileRanks[2].Window)
+	assert.InDelta(t, 100.0, result.PercentileRanks[2].DayAverage, 1e-9)
+	assert.Equal(t, []float64{100.0}, result.PercentileRanks[2].Hours)
+}
+
+func TestGetDailyInfoPeriodStrategy(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location)
+
+	var prices []Price
+	for i, p := range []float64{0.05, 0.06, 0.10, 0.11, 0.10, 0.20, 0.21, 0.05} {
+		prices = append(prices, Price{DateTime: day.Add(time.Duration(i) * time.Hour), Price: p})
+	}
+
+	tests := []struct {
+		name              string
+		deployment        PeriodStrategy
+		opts              Options
+		expectedName      string
+		expectedCheap     int
+		expectedExpensive int
+	}{
+		{"Default", nil, Options{}, "variance", 2, 1},
+		{"Deployment strategy", QuantileStrategy{}, Options{}, "quantile", 2, 1},
+		{"Requested strategy overrides the deployment", QuantileStrategy{}, Options{Periods: SmoothedStrategy{Strategy: KMeansStrategy{}}}, "smoothed", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockFindResult:   &[][]Price{prices},
+				MockFindErr:      &[]error{},
+				MockThirtyDayAvg: &[]float64{0.1},
+				MockThirtyDayErr: &[]error{},
+			}
+			service := &Receiver{Collection: mockCollection, RankWindows: []RankWindow{}, Periods: tt.deployment}
+
+			result, err := service.GetDailyInfo(ctx, day, tt.opts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedName, result.PeriodStrategy)
+			assert.Len(t, result.CheapPeriods, tt.expectedCheap)
+			assert.Len(t, result.ExpensivePeriods, tt.expectedExpensive)
+		})
+	}
+}
+
+func TestGetDailyInfoExplain(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location)
+	prices := []Price{{DateTime: day, Price: 0.1}, {DateTime: day.Add(time.Hour), Price: 0.2}}
+
+	for _, explain := range []bool{false, true} {
+		mockCollection := &MockCollection{
+			MockFindResult:   &[][]Price{prices},
+			MockFindErr:      &[]error{},
+			MockThirtyDayAvg: &[]float64{0.15},
+			MockThirtyDayErr: &[]error{},
+		}
+		service := &Receiver{Collection: mockCollection, RankWindows: []RankWindow{}}
+
+		result, err := service.GetDailyInfo(ctx, day, Options{Explain: explain})
+
+		assert.NoError(t, err)
+		if !explain {
+			assert.Nil(t, result.Explanation)
+			continue
+		}
+		assert.NotNil(t, result.Explanation)
+		assert.Equal(t, 0.15, result.Explanation.ThirtyDayAverage)
+		assert.Len(t, result.Explanation.Hours, 2)
+		assert.True(t, result.Explanation.Hours[0].Cheap)
+		assert.True(t, result.Explanation.Hours[1].Expensive)
+	}
+}
+
+func TestGetDailyInfoExplainStrategy(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location)
+
+	var prices []Price
+	for i, p := range []float64{0.05, 0.06, 0.10, 0.11, 0.10, 0.20, 0.21, 0.05} {
+		prices = append(prices, Price{DateTime: day.Add(time.Duration(i) * time.Hour), Price: p})
+	}
+
+	tests := []struct {
+		name              string
+		opts              Options
+		expectedStrategy  string
+		expectedCutOff    float64
+		expectedExpensive bool
+	}{
+		{"Quantile", Options{Periods: QuantileStrategy{}}, "quantile", 0, true},
+		{"Variance with the default rating", Options{}, "variance", 0.2, true},
+		{"Variance with an absolute rating threshold", Options{Rating: RatingConfig{Method: AbsoluteBand, Threshold: 0.01}}, "variance", 0.21, false},
+		{"Variance with a percentage rating", Options{Rating: RatingConfig{Method: PercentageBand, Threshold: 0.5}}, "variance", 0.2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockFindResult:   &[][]Price{prices, prices},
+				MockFindErr:      &[]error{},
+				MockThirtyDayAvg: &[]float64{0.22},
+				MockThirtyDayErr: &[]error{},
+			}
+			service := &Receiver{Collection: mockCollection, RankWindows: []RankWindow{}}
+			tt.opts.Explain = true
+
+			result, err := service.GetDailyInfo(ctx, day, tt.opts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStrategy, result.Explanation.Strategy)
+			assert.InDelta(t, tt.expectedCutOff, result.Explanation.ExpensiveCutOff, 1e-9)
+			assert.Equal(t, tt.expectedExpensive, len(result.ExpensivePeriods) > 0)
+
+			// The flags must match the periods returned with them
+			expensive := 0
+			for _, h := range result.Explanation.Hours {
+				if h.Expensive {
+					expensive++
+				}
+			}
+			inPeriods := 0
+			for _, period := range result.ExpensivePeriods {
+				inPeriods += len(period)
+			}
+			assert.Equal(t, inPeriods, expensive)
+		})
+	}
+}
+
+func TestGetDailyShapes(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location)
+	prices := []Price{{DateTime: day, Price: 0.1}, {DateTime: day.Add(time.Hour), Price: 0.2}}
+	shapes := map[string]string{"2023-11-29": "EVENING_PEAK"}
+
+	tests := []struct {
+		name          string
+		shapes        *MockShapeSource
+		expectedShape string
+	}{
+		{"Labelled", &MockShapeSource{MockGetShapesResult: &[]map[string]string{shapes, shapes}, MockGetShapesError: &[]error{}}, "EVENING_PEAK"},
+		{"Not labelled", &MockShapeSource{MockGetShapesResult: &[]map[string]string{{}, {}}, MockGetShapesError: &[]error{}}, ""},
+		{"Labels failed", &MockShapeSource{MockGetShapesResult: &[]map[string]string{}, MockGetShapesError: &[]error{errors.New("error"), errors.New("error")}}, ""},
+		{"No shape source", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockFindResult:   &[][]Price{prices, prices},
+				MockFindErr:      &[]error{},
+				MockThirtyDayAvg: &[]float64{0.15},
+				MockThirtyDayErr: &[]error{},
+			}
+			service := &Receiver{Collection: mockCollection, RankWindows: []RankWindow{}}
+			if tt.shapes != nil {
+				service.Shapes = tt.shapes
+			}
+
+			averages, err := service.GetDailyAverages(ctx, day, 0)
+			assert.NoError(t, err)
+			assert.Len(t, averages, 1)
+			assert.Equal(t, tt.expectedShape, averages[0].Shape)
+
+			info, err := service.GetDailyInfo(ctx, day, Options{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedShape, info.Shape)
+		})
+	}
+}
+
+func TestGetAnomalies(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location)
+	spike := Anomaly{DateTime: day.Add(time.Hour), Type: Spike, Price: 0.5}
+	negative := Anomaly{DateTime: day.Add(3 * time.Hour), Type: NonPositive, Price: -0.01}
+
+	tests := []struct {
+		name     string
+		prices   []Price
+		err      error
+		expected []Anomaly
+	}{
+		{"Anomalies", []Price{{DateTime: day.Add(time.Hour), Anomalies: []Anomaly{spike}}, {DateTime: day.Add(3 * time.Hour), Anomalies: []Anomaly{negative}}}, nil, []Anomaly{spike, negative}},
+		{"None", []Price{}, nil, []Anomaly{}},
+		{"Error", nil, errors.New("error"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockFindResult: &[][]Price{tt.prices},
+				MockFindErr:    &[]error{tt.err},
+			}
+			service := &Receiver{Collection: mockCollection}
+
+			result, err := service.GetAnomalies(ctx, day, day.AddDate(0, 0, 1))
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestGetUpcomingPeriods(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location)
+	now := day.Add(23*time.Hour + 20*time.Minute)
+
+	var prices []Price
+	for i := 0; i < 48; i++ {
+		p := 0.2
+		switch i {
+		case 3, 22, 23, 24, 25:
+			p = 0.05
+		case 42:
+			p = 0.3
+		}
+		prices = append(prices, Price{DateTime: day.Add(time.Duration(i) * time.Hour), Price: p})
+	}
+
+	mockCollection := &MockCollection{
+		MockFindResult:   &[][]Price{prices},
+		MockFindErr:      &[]error{},
+		MockThirtyDayAvg: &[]float64{0.1},
+		MockThirtyDayErr: &[]error{},
+	}
+	service := &Receiver{Collection: mockCollection}
+
+	result, err := service.GetUpcomingPeriods(ctx, now, Options{Periods: KMeansStrategy{}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "kmeans", result.PeriodStrategy)
+	assert.Equal(t, day.Add(23*time.Hour), result.Start)
+	assert.Equal(t, day.AddDate(0, 0, 2), result.End)
+	assert.Len(t, result.Prices, 25)
+	// The period from 22:00 to 02:00 is kept whole and the past period at 03:00 is dropped
+	assert.Len(t, result.CheapPeriods, 1)
+	assert.Len(t, result.CheapPeriods[0], 4)
+	assert.Equal(t, day.Add(22*time.Hour), result.CheapPeriods[0][0].DateTime)
+	assert.Len(t, result.ExpensivePeriods, 1)
+	assert.Equal(t, day.Add(42*time.Hour), result.ExpensivePeriods[0][0].DateTime)
+}
+
+func TestGetDailyInfoRating(t *testing.T) {
+	ctx := context.Background()
+	// Wednesday
+	day := time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location)
+
+	prices := []Price{{DateTime: day, Price: 0.2}}
+	history := []Price{
+		{DateTime: day.AddDate(0, 0, -7), Price: 0.3},
+		{DateTime: day.AddDate(0, 0, -6), Price: 0.1},
+		{DateTime: day.AddDate(0, 0, -5), Price: 0.1},
+		{DateTime: day, Price: 0.2},
+	}
+
+	tests := []struct {
+		name              string
+		opts              Options
+		mockFind          *[][]Price
+		expectedRating    DayRating
+		expectedMethod    RatingMethod
+		expectedBaseline  float64
+		expectedGoodBelow float64
+	}{
+		{
+			name:              "default absolute band around the thirty-day average",
+			opts:              Options{},
+			mockFind:          &[][]Price{prices},
+			expectedRating:    Normal,
+			expectedMethod:    AbsoluteBand,
+			expectedBaseline:  0.2,
+			expectedGoodBelow: 0.18,
+		},
+		{
+			name:              "same weekday",
+			opts:              Options{Rating: RatingConfig{Method: SameWeekday}},
+			mockFind:          &[][]Price{prices, history},
+			expectedRating:    Good,
+			expectedMethod:    SameWeekday,
+			expectedBaseline:  0.3,
+			expectedGoodBelow: 0.28,
+		},
+		{
+			name:              "percentile",
+			opts:              Options{Rating: RatingConfig{Method: PercentileBand, Threshold: 10}},
+			mockFind:          &[][]Price{prices, history},
+			expectedRating:    Normal,
+			expectedMethod:    PercentileBand,
+			expectedBaseline:  0.15,
+			expectedGoodBelow: 0.1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockFindResult:   tt.mockFind,
+				MockFindErr:      &[]error{},
+				MockThirtyDayAvg: &[]float64{0.2},
+				MockThirtyDayErr: &[]error{},
+			}
+			service := &Receiver{Collection: mockCollection, RankWindows: []RankWindow{}}
+
+			result, err := service.GetDailyInfo(ctx, day, tt.opts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRating, result.DayRating)
+			assert.Equal(t, tt.expectedMethod, result.Rating.Method)
+			assert.InDelta(t, tt.expectedBaseline, result.Rating.Baseline, 1e-9)
+			assert.InDelta(t, tt.expectedGoodBelow, result.Rating.GoodBelow, 1e-9)
+		})
+	}
+}
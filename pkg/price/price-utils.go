@@ -3,6 +3,8 @@ package price
 import (
 	"electricity-prices/pkg/date"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
 )
@@ -43,15 +45,269 @@ func CalculateAverage(prices []Price) float64 {
 	return total / float64(len(prices))
 }
 
+// CalculateAverageComponents
+// Calculate the average of each price component over the prices that have a breakdown.
+// Returns nil if none of the prices have one.
+func CalculateAverageComponents(prices []Price) *Components {
+	var avg Components
+	var count float64
+	for _, p := range prices {
+		if p.Components == nil {
+			continue
+		}
+		c := p.Components
+		avg.Energy += c.Energy
+		avg.Adjustment += c.Adjustment
+		avg.CapacityPayments += c.CapacityPayments
+		avg.Interruptibility += c.Interruptibility
+		avg.OperatorFees += c.OperatorFees
+		avg.TollsAndCharges += c.TollsAndCharges
+		avg.TradingCosts += c.TradingCosts
+		avg.Other += c.Other
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+	avg.Energy /= count
+	avg.Adjustment /= count
+	avg.CapacityPayments /= count
+	avg.Interruptibility /= count
+	avg.OperatorFees /= count
+	avg.TollsAndCharges /= count
+	avg.TradingCosts /= count
+	avg.Other /= count
+	return &avg
+}
+
+// WithoutComponents
+// Returns a copy of the prices without their component breakdown.
+func WithoutComponents(prices []Price) []Price {
+	if prices == nil {
+		return nil
+	}
+	stripped := make([]Price, len(prices))
+	for i, p := range prices {
+		p.Components = nil
+		stripped[i] = p
+	}
+	return stripped
+}
+
+// CalculateStats
+// Calculate the descriptive statistics of the prices in memory.
+// Percentiles are interpolated linearly between the closest ranks.
+func CalculateStats(prices []Price) Stats {
+	stats := Stats{Count: len(prices)}
+	if len(prices) == 0 {
+		return stats
+	}
+
+	stats.Mean = CalculateAverage(prices)
+	minP, maxP := getMinPrice(prices), getMaxPrice(prices)
+
+	values := make([]float64, len(prices))
+	var sumSquares float64
+	for i, p := range prices {
+		values[i] = p.Price
+		sumSquares += (p.Price - stats.Mean) * (p.Price - stats.Mean)
+		if p.Price <= 0 {
+			stats.NonPositiveHours++
+		}
+		if p.Price == minP && (stats.Min.DateTime.IsZero() || p.DateTime.Before(stats.Min.DateTime)) {
+			stats.Min = PriceAtTime{DateTime: p.DateTime, Price: p.Price}
+		}
+		if p.Price == maxP && (stats.Max.DateTime.IsZero() || p.DateTime.Before(stats.Max.DateTime)) {
+			stats.Max = PriceAtTime{DateTime: p.DateTime, Price: p.Price}
+		}
+	}
+	stats.StdDev = math.Sqrt(sumSquares / float64(len(prices)))
+
+	sort.Float64s(values)
+	stats.Median = Percentile(values, 50)
+	stats.Percentiles = calculatePercentiles(values)
+
+	return stats
+}
+
+// calculatePercentiles
+// Get the percentiles reported in the stats from sorted values.
+func calculatePercentiles(sorted []float64) Percentiles {
+	return Percentiles{
+		P5:  Percentile(sorted, 5),
+		P10: Percentile(sorted, 10),
+		P25: Percentile(sorted, 25),
+		P75: Percentile(sorted, 75),
+		P90: Percentile(sorted, 90),
+		P95: Percentile(sorted, 95),
+	}
+}
+
+// Percentile
+// Get the p-th percentile of sorted values, interpolating between the closest ranks.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0.0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// CalculateHeatmap
+// Aggregate the prices that match the filter into a weekday by local hour matrix of mean and median prices.
+func CalculateHeatmap(prices []Price, filter HeatmapFilter) Heatmap {
+	var cells [7][24][]float64
+	for _, p := range prices {
+		t := p.DateTime.In(date.Location)
+		if !filter.Matches(t) {
+			continue
+		}
+		// Shift the weekday so Monday is first
+		weekday := (int(t.Weekday()) + 6) % 7
+		cells[weekday][t.Hour()] = append(cells[weekday][t.Hour()], p.Price)
+	}
+
+	heatmap := Heatmap{Weekdays: make([]string, 7)}
+	for weekday := range cells {
+		heatmap.Weekdays[weekday] = time.Weekday((weekday + 1) % 7).String()
+		for hour, values := range cells[weekday] {
+			if len(values) == 0 {
+				continue
+			}
+			var total float64
+			for _, v := range values {
+				total += v
+			}
+			sort.Float64s(values)
+			heatmap.Mean[weekday][hour] = total / float64(len(values))
+			heatmap.Median[weekday][hour] = Percentile(values, 50)
+			heatmap.Count[weekday][hour] = len(values)
+		}
+	}
+	return heatmap
+}
+
+// Matches
+// Returns true if the local day of t passes the filter.
+func (f HeatmapFilter) Matches(t time.Time) bool {
+	t = t.In(date.Location)
+	if f.Month != 0 && t.Month() != f.Month {
+		return false
+	}
+	if f.Season != "" && SeasonOf(t) != f.Season {
+		return false
+	}
+	switch f.DayType {
+	case WorkingDay:
+		return date.IsWorkingDay(t)
+	case Holiday:
+		return !date.IsWorkingDay(t)
+	}
+	return true
+}
+
+// SeasonOf gets the meteorological season of the local day.
+func SeasonOf(day time.Time) Season {
+	switch day.In(date.Location).Month() {
+	case time.December, time.January, time.February:
+		return Winter
+	case time.March, time.April, time.May:
+		return Spring
+	case time.June, time.July, time.August:
+		return Summer
+	}
+	return Autumn
+}
+
+// CalculatePercentileRank
+// Calculate the percentage of values that are lower than the value, counting equal values as half.
+func CalculatePercentileRank(value float64, values []float64) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+	var below float64
+	for _, v := range values {
+		if v < value {
+			below++
+		} else if v == value {
+			below += 0.5
+		}
+	}
+	return below / float64(len(values)) * 100
+}
+
+// CalculatePercentileRanks
+// Rank the day average against the daily averages of the history and each hour against all the hourly prices of the history.
+func CalculatePercentileRanks(window RankWindow, prices []Price, history []Price) PercentileRank {
+	averages := CalculateDailyAverages(history)
+	dailyValues := make([]float64, len(averages))
+	for i, a := range averages {
+		dailyValues[i] = a.Average
+	}
+	hourlyValues := make([]float64, len(history))
+	for i, p := range history {
+		hourlyValues[i] = p.Price
+	}
+
+	rank := PercentileRank{
+		Window: window.Name,
+		Days:   len(averages),
+		Hours:  make([]float64, len(prices)),
+	}
+	if len(averages) == 0 {
+		return rank
+	}
+	rank.DayAverage = CalculatePercentileRank(CalculateAverage(prices), dailyValues)
+	for i, p := range prices {
+		rank.Hours[i] = CalculatePercentileRank(p.Price, hourlyValues)
+	}
+	return rank
+}
+
 // CalculateDayRating
 // Calculate the rating for a day based on the daily average and the thirty-day average.
 func CalculateDayRating(dayAvg float64, thirtyDayAvg float64) DayRating {
+	return rateAgainst(dayAvg, thirtyDayAvg-ratingVariance, thirtyDayAvg+ratingVariance)
+}
 
-	variance := dayAvg - thirtyDayAvg
+// CalculateRating
+// Rate the day average using the method in the config.
+// The baseline is the average to compare against, or the same weekday average for the weekday method.
+// The daily averages of the baseline period are only used by the percentile method.
+func CalculateRating(config RatingConfig, dayAvg float64, baseline float64, dailyAverages []float64) (DayRating, RatingDetails) {
+	config = config.WithDefaults()
+	details := RatingDetails{
+		Method:       config.Method,
+		Threshold:    config.Threshold,
+		BaselineDays: config.BaselineDays,
+		Baseline:     baseline,
+	}
 
-	if variance < -ratingVariance {
+	switch config.Method {
+	case PercentageBand:
+		band := math.Abs(baseline) * config.Threshold
+		details.GoodBelow, details.BadAbove = baseline-band, baseline+band
+	case PercentileBand:
+		sorted := append([]float64(nil), dailyAverages...)
+		sort.Float64s(sorted)
+		details.Baseline = Percentile(sorted, 50)
+		details.GoodBelow = Percentile(sorted, config.Threshold)
+		details.BadAbove = Percentile(sorted, 100-config.Threshold)
+	default:
+		details.GoodBelow, details.BadAbove = baseline-config.Threshold, baseline+config.Threshold
+	}
+
+	return rateAgainst(dayAvg, details.GoodBelow, details.BadAbove), details
+}
+
+// rateAgainst
+// Rate the day average as good below the lower threshold and bad above the upper threshold.
+func rateAgainst(dayAvg float64, goodBelow float64, badAbove float64) DayRating {
+	if dayAvg < goodBelow {
 		return Good
-	} else if variance > ratingVariance {
+	} else if dayAvg > badAbove {
 		return Bad
 	}
 	return Normal
@@ -81,6 +337,12 @@ func CalculateCheapPeriods(prices []Price, thirtyDayAvg float64) [][]Price {
 // CalculateExpensivePeriods
 // Get the expensive periods from a slice of prices.
 func CalculateExpensivePeriods(prices []Price, thirtyDayAvg float64) [][]Price {
+	return calculateExpensivePeriods(prices, thirtyDayAvg, ratingVariance)
+}
+
+// calculateExpensivePeriods
+// Get the expensive periods, with none when the most expensive price is at least the cut-off below the thirty-day average.
+func calculateExpensivePeriods(prices []Price, thirtyDayAvg float64, cutOff float64) [][]Price {
 	if len(prices) == 0 {
 		return [][]Price{}
 	}
@@ -88,7 +350,7 @@ func CalculateExpensivePeriods(prices []Price, thirtyDayAvg float64) [][]Price {
 	maxP := getMaxPrice(prices)
 
 	// If the most expensive price would be considered cheap, return empty list
-	if maxP <= thirtyDayAvg-ratingVariance {
+	if maxP <= thirtyDayAvg-cutOff {
 		return [][]Price{}
 	}
 
@@ -224,6 +486,115 @@ func calculateExpensiveVariance(prices []Price, thirtyDayAvg float64) float64 {
 	return variance
 }
 
+// ExplainPeriods
+// Get the intermediate values the default variance heuristic compares each hour against.
+func ExplainPeriods(prices []Price, thirtyDayAvg float64) Explanation {
+	return explainVariance(prices, thirtyDayAvg, ratingVariance)
+}
+
+// explainVariance
+// Get the intermediate values the variance heuristic with the expensive cut-off compares each hour against.
+func explainVariance(prices []Price, thirtyDayAvg float64, cutOff float64) Explanation {
+	explanation := newExplanation(VarianceStrategy{}.Name(), prices, thirtyDayAvg)
+	if len(prices) == 0 {
+		return explanation
+	}
+
+	minP, maxP := explanation.MinPrice, explanation.MaxPrice
+	explanation.CombinedAverage = calculateCombinedAverage(explanation.DayAverage, thirtyDayAvg)
+	explanation.MinVariance = calculateMinVariance(minP, maxP)
+	explanation.MaxVariance = calculateMaxVariance(minP, maxP)
+	explanation.CheapVariance = calculateCheapVariance(prices, thirtyDayAvg)
+	explanation.ExpensiveVariance = calculateExpensiveVariance(prices, thirtyDayAvg)
+	explanation.CheapThreshold = minP + explanation.CheapVariance
+	explanation.ExpensiveThreshold = maxP - explanation.ExpensiveVariance
+	explanation.ExpensiveCutOff = thirtyDayAvg - cutOff
+
+	for _, p := range prices {
+		explanation.Hours = append(explanation.Hours, HourExplanation{
+			DateTime:           p.DateTime,
+			Price:              p.Price,
+			CheapThreshold:     explanation.CheapThreshold,
+			ExpensiveThreshold: explanation.ExpensiveThreshold,
+			Cheap:              p.Price <= explanation.CheapThreshold,
+			Expensive:          maxP > explanation.ExpensiveCutOff && maxP-p.Price <= explanation.ExpensiveVariance,
+		})
+	}
+
+	return explanation
+}
+
+// DetectAnomalies
+// Flag the unusual prices of a day against the history before it, returning the prices with their anomalies set.
+// The history should cover the detector's window. Spikes and dips need at least a day of history and day jumps need the previous day.
+func DetectAnomalies(prices []Price, history []Price, detector AnomalyDetector) []Price {
+	detector = detector.WithDefaults()
+	if len(prices) == 0 {
+		return prices
+	}
+
+	// Get the rolling distribution over the window before the first price
+	first := prices[0].DateTime
+	for _, p := range prices {
+		if p.DateTime.Before(first) {
+			first = p.DateTime
+		}
+	}
+	day := date.StartOfDay(first)
+	windowStart := day.AddDate(0, 0, -detector.WindowDays)
+	var window, previousDay []Price
+	for _, p := range history {
+		if p.DateTime.Before(windowStart) || !p.DateTime.Before(day) {
+			continue
+		}
+		window = append(window, p)
+		if !p.DateTime.Before(day.AddDate(0, 0, -1)) {
+			previousDay = append(previousDay, p)
+		}
+	}
+	stats := CalculateStats(window)
+
+	result := make([]Price, len(prices))
+	copy(result, prices)
+	for i, p := range result {
+		var anomalies []Anomaly
+		if p.Price <= 0 {
+			anomalies = append(anomalies, Anomaly{DateTime: p.DateTime, Type: NonPositive, Price: p.Price})
+		}
+		if len(window) >= 24 && stats.StdDev > 0 {
+			z := (p.Price - stats.Mean) / stats.StdDev
+			if z >= detector.ZScore {
+				anomalies = append(anomalies, Anomaly{DateTime: p.DateTime, Type: Spike, Price: p.Price, Baseline: stats.Mean, Score: z})
+			} else if z <= -detector.ZScore {
+				anomalies = append(anomalies, Anomaly{DateTime: p.DateTime, Type: Dip, Price: p.Price, Baseline: stats.Mean, Score: z})
+			}
+		}
+		if p.DateTime.Equal(first) && len(previousDay) > 0 {
+			previousAvg := CalculateAverage(previousDay)
+			dayAvg := CalculateAverage(prices)
+			if previousAvg != 0 {
+				change := (dayAvg - previousAvg) / math.Abs(previousAvg)
+				if math.Abs(change) >= detector.DayJump {
+					anomalies = append(anomalies, Anomaly{DateTime: p.DateTime, Type: DayJump, Price: dayAvg, Baseline: previousAvg, Score: change})
+				}
+			}
+		}
+		result[i].Anomalies = anomalies
+	}
+
+	return result
+}
+
+// CollectAnomalies
+// Get the anomalies flagged on the prices, in the order of the prices.
+func CollectAnomalies(prices []Price) []Anomaly {
+	anomalies := make([]Anomaly, 0)
+	for _, p := range prices {
+		anomalies = append(anomalies, p.Anomalies...)
+	}
+	return anomalies
+}
+
 // groupPrices
 // Group consecutive prices into periods.
 func groupPrices(cheapPrices []Price) [][]Price {
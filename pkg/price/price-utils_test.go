@@ -1,6 +1,7 @@
 package price
 
 import (
+	"electricity-prices/pkg/date"
 	"electricity-prices/pkg/testutils"
 	"math"
 	"testing"
@@ -37,6 +38,135 @@ func TestCalculateAverage(t *testing.T) {
 	}
 }
 
+func TestCalculateAverageComponents(t *testing.T) {
+	testCases := []struct {
+		name     string
+		prices   []Price
+		expected *Components
+	}{
+		{"Empty slice", []Price{}, nil},
+		{"No breakdown", []Price{{Price: 1.0}}, nil},
+		{"One breakdown", []Price{{Price: 1.0, Components: &Components{Energy: 0.8, TollsAndCharges: 0.2}}}, &Components{Energy: 0.8, TollsAndCharges: 0.2}},
+		{"Ignores prices without a breakdown", []Price{
+			{Price: 1.0, Components: &Components{Energy: 0.8, TollsAndCharges: 0.2}},
+			{Price: 2.0, Components: &Components{Energy: 1.6, TollsAndCharges: 0.4}},
+			{Price: 3.0},
+		}, &Components{Energy: 1.2, TollsAndCharges: 0.3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			avg := CalculateAverageComponents(tc.prices)
+			if tc.expected == nil {
+				if avg != nil {
+					t.Errorf("Expected nil, but got %+v", avg)
+				}
+				return
+			}
+			if avg == nil {
+				t.Fatal("Expected components, but got nil")
+			}
+			if !floatEquals(avg.Energy, tc.expected.Energy) || !floatEquals(avg.TollsAndCharges, tc.expected.TollsAndCharges) {
+				t.Errorf("Expected %+v, but got %+v", tc.expected, avg)
+			}
+		})
+	}
+}
+
+func TestWithoutComponents(t *testing.T) {
+	prices := []Price{{Price: 1.0, Components: &Components{Energy: 1.0}}, {Price: 2.0}}
+
+	stripped := WithoutComponents(prices)
+
+	if len(stripped) != 2 {
+		t.Fatalf("Expected 2 prices but got %d", len(stripped))
+	}
+	for _, p := range stripped {
+		if p.Components != nil {
+			t.Errorf("Expected no components but got %+v", p.Components)
+		}
+	}
+	if prices[0].Components == nil {
+		t.Errorf("Expected the original prices to be unchanged")
+	}
+	if WithoutComponents(nil) != nil {
+		t.Errorf("Expected nil for nil prices")
+	}
+}
+
+func TestCalculateStats(t *testing.T) {
+	start := time.Date(2023, 11, 29, 0, 0, 0, 0, time.UTC)
+	prices := []Price{
+		{DateTime: start, Price: 3.0},
+		{DateTime: start.Add(time.Hour), Price: 1.0},
+		{DateTime: start.Add(2 * time.Hour), Price: 5.0},
+		{DateTime: start.Add(3 * time.Hour), Price: 2.0},
+		{DateTime: start.Add(4 * time.Hour), Price: 4.0},
+		{DateTime: start.Add(5 * time.Hour), Price: 1.0},
+		{DateTime: start.Add(6 * time.Hour), Price: 0.0},
+		{DateTime: start.Add(7 * time.Hour), Price: -1.0},
+	}
+
+	stats := CalculateStats(prices)
+
+	if stats.Count != 8 {
+		t.Errorf("Expected count 8, but got %d", stats.Count)
+	}
+	if !floatEquals(stats.Mean, 1.875) {
+		t.Errorf("Expected mean 1.875, but got %f", stats.Mean)
+	}
+	if !floatEquals(stats.Median, 1.5) {
+		t.Errorf("Expected median 1.5, but got %f", stats.Median)
+	}
+	if !floatEquals(stats.StdDev, 1.8998) {
+		t.Errorf("Expected standard deviation 1.8998, but got %f", stats.StdDev)
+	}
+	if !floatEquals(stats.Percentiles.P25, 0.75) || !floatEquals(stats.Percentiles.P75, 3.25) {
+		t.Errorf("Expected p25 0.75 and p75 3.25, but got %f and %f", stats.Percentiles.P25, stats.Percentiles.P75)
+	}
+	if !floatEquals(stats.Percentiles.P5, -0.65) || !floatEquals(stats.Percentiles.P95, 4.65) {
+		t.Errorf("Expected p5 -0.65 and p95 4.65, but got %f and %f", stats.Percentiles.P5, stats.Percentiles.P95)
+	}
+	if stats.Min.Price != -1.0 || !stats.Min.DateTime.Equal(start.Add(7*time.Hour)) {
+		t.Errorf("Expected min -1.0 at %s, but got %f at %s", start.Add(7*time.Hour), stats.Min.Price, stats.Min.DateTime)
+	}
+	if stats.Max.Price != 5.0 || !stats.Max.DateTime.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("Expected max 5.0 at %s, but got %f at %s", start.Add(2*time.Hour), stats.Max.Price, stats.Max.DateTime)
+	}
+	if stats.NonPositiveHours != 2 {
+		t.Errorf("Expected 2 non-positive hours, but got %d", stats.NonPositiveHours)
+	}
+
+	if empty := CalculateStats([]Price{}); empty.Count != 0 || empty.Mean != 0 {
+		t.Errorf("Expected empty stats, but got %+v", empty)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []float64
+		p        float64
+		expected float64
+	}{
+		{"Empty slice", []float64{}, 50, 0.0},
+		{"One value", []float64{2.0}, 90, 2.0},
+		{"Exact rank", []float64{1.0, 2.0, 3.0}, 50, 2.0},
+		{"Interpolated", []float64{1.0, 2.0, 3.0, 4.0}, 50, 2.5},
+		{"Lowest", []float64{1.0, 2.0, 3.0, 4.0}, 0, 1.0},
+		{"Highest", []float64{1.0, 2.0, 3.0, 4.0}, 100, 4.0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := Percentile(tc.values, tc.p)
+			if !floatEquals(actual, tc.expected) {
+				t.Errorf("Expected %f, but got %f", tc.expected, actual)
+			}
+		})
+	}
+}
+
 func TestSortDailyAverages(t *testing.T) {
 	averages := []DailyAverage{
 		{Date: "2020-01-01"},
@@ -380,6 +510,118 @@ func TestCalculateExpensivePeriods(t *testing.T) {
 	}
 }
 
+func TestExplainPeriods(t *testing.T) {
+	var prices []Price
+	err := testutils.ReadJSONFromFile("testdata/cheap-day.json", &prices)
+	if err != nil {
+		t.Errorf("Error reading file: %s", err)
+	}
+
+	testCases := []struct {
+		name         string
+		prices       []Price
+		thirtyDayAvg float64
+	}{
+		{"Three prices", []Price{{Price: 1.0}, {Price: 2.0}, {Price: 3.0}}, 1.0},
+		{"Cheap day", prices, 0.15},
+		{"Expensive periods cut off", prices, 1.0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			explanation := ExplainPeriods(tc.prices, tc.thirtyDayAvg)
+			if !floatEquals(explanation.CheapVariance, calculateCheapVariance(tc.prices, tc.thirtyDayAvg)) {
+				t.Errorf("Expected cheap variance %f, but got %f", calculateCheapVariance(tc.prices, tc.thirtyDayAvg), explanation.CheapVariance)
+			}
+			if explanation.CheapVariance < explanation.MinVariance || explanation.CheapVariance > explanation.MaxVariance {
+				t.Errorf("Expected cheap variance %f to be clamped between %f and %f", explanation.CheapVariance, explanation.MinVariance, explanation.MaxVariance)
+			}
+			if len(explanation.Hours) != len(tc.prices) {
+				t.Fatalf("Expected %d hours, but got %d", len(tc.prices), len(explanation.Hours))
+			}
+
+			// The hours flagged must be the hours in the periods
+			var cheap, expensive int
+			for _, h := range explanation.Hours {
+				if h.Cheap {
+					cheap++
+				}
+				if h.Expensive {
+					expensive++
+				}
+			}
+			var expectedCheap, expectedExpensive int
+			for _, period := range CalculateCheapPeriods(tc.prices, tc.thirtyDayAvg) {
+				expectedCheap += len(period)
+			}
+			for _, period := range CalculateExpensivePeriods(tc.prices, tc.thirtyDayAvg) {
+				expectedExpensive += len(period)
+			}
+			if cheap != expectedCheap {
+				t.Errorf("Expected %d cheap hours, but got %d", expectedCheap, cheap)
+			}
+			if expensive != expectedExpensive {
+				t.Errorf("Expected %d expensive hours, but got %d", expectedExpensive, expensive)
+			}
+		})
+	}
+
+	empty := ExplainPeriods([]Price{}, 0.1)
+	if len(empty.Hours) != 0 || empty.ThirtyDayAverage != 0.1 {
+		t.Errorf("Expected an empty explanation with the thirty-day average, but got %+v", empty)
+	}
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, date.Location)
+	var history []Price
+	for h := 0; h < 24*3; h++ {
+		history = append(history, Price{DateTime: day.AddDate(0, 0, -3).Add(time.Duration(h) * time.Hour), Price: 0.1 + 0.01*float64(h%2)})
+	}
+
+	testCases := []struct {
+		name     string
+		prices   []Price
+		history  []Price
+		detector AnomalyDetector
+		expected []AnomalyType
+	}{
+		{"Normal day", []Price{{DateTime: day, Price: 0.1}, {DateTime: day.Add(time.Hour), Price: 0.11}}, history, AnomalyDetector{}, nil},
+		{"Spike", []Price{{DateTime: day, Price: 0.1}, {DateTime: day.Add(time.Hour), Price: 0.2}}, history, AnomalyDetector{DayJump: 1}, []AnomalyType{Spike}},
+		{"Dip and negative", []Price{{DateTime: day, Price: 0.1}, {DateTime: day.Add(time.Hour), Price: -0.05}}, history, AnomalyDetector{DayJump: 1}, []AnomalyType{NonPositive, Dip}},
+		{"Day jump", []Price{{DateTime: day, Price: 0.16}, {DateTime: day.Add(time.Hour), Price: 0.16}}, history, AnomalyDetector{ZScore: 100}, []AnomalyType{DayJump}},
+		{"Zero price without history", []Price{{DateTime: day, Price: 0}}, nil, AnomalyDetector{}, []AnomalyType{NonPositive}},
+		{"History outside the window", []Price{{DateTime: day, Price: 0.5}}, history, AnomalyDetector{WindowDays: 1, DayJump: 100}, []AnomalyType{Spike}},
+		{"Empty", []Price{}, history, AnomalyDetector{}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := DetectAnomalies(tc.prices, tc.history, tc.detector)
+			if len(result) != len(tc.prices) {
+				t.Fatalf("Expected %d prices, but got %d", len(tc.prices), len(result))
+			}
+			var types []AnomalyType
+			for _, a := range CollectAnomalies(result) {
+				types = append(types, a.Type)
+			}
+			if len(types) != len(tc.expected) {
+				t.Fatalf("Expected %v, but got %v", tc.expected, types)
+			}
+			for i := range types {
+				if types[i] != tc.expected[i] {
+					t.Errorf("Expected %v, but got %v", tc.expected, types)
+				}
+			}
+			for _, p := range tc.prices {
+				if p.Anomalies != nil {
+					t.Errorf("Expected the input prices to be left unchanged")
+				}
+			}
+		})
+	}
+}
+
 func TestGetNextPeriod(t *testing.T) {
 
 	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -540,3 +782,166 @@ func TestCalculateDailyAverages(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateHeatmap(t *testing.T) {
+	// Wednesday 29th and Thursday 30th November 2023 and the following Wednesday
+	wed := time.Date(2023, 11, 29, 10, 0, 0, 0, date.Location)
+	prices := []Price{
+		{DateTime: wed, Price: 1.0},
+		{DateTime: wed.AddDate(0, 0, 7), Price: 3.0},
+		{DateTime: wed.AddDate(0, 0, 14), Price: 8.0},
+		{DateTime: wed.AddDate(0, 0, 1).Add(2 * time.Hour), Price: 2.0},
+		{DateTime: time.Date(2023, 10, 4, 10, 0, 0, 0, date.Location), Price: 100.0},
+	}
+
+	heatmap := CalculateHeatmap(prices, HeatmapFilter{Month: time.December})
+	if heatmap.Count[2][10] != 2 {
+		t.Errorf("Expected 2 prices on Wednesdays at 10 in December, but got %d", heatmap.Count[2][10])
+	}
+	if !floatEquals(heatmap.Mean[2][10], 5.5) || !floatEquals(heatmap.Median[2][10], 5.5) {
+		t.Errorf("Expected a mean and median of 5.5, but got %f and %f", heatmap.Mean[2][10], heatmap.Median[2][10])
+	}
+
+	heatmap = CalculateHeatmap(prices, HeatmapFilter{})
+	if heatmap.Count[2][10] != 4 {
+		t.Errorf("Expected 4 prices on Wednesdays at 10, but got %d", heatmap.Count[2][10])
+	}
+	if !floatEquals(heatmap.Median[2][10], 5.5) || !floatEquals(heatmap.Mean[2][10], 28.0) {
+		t.Errorf("Expected a mean of 28.0 and median of 5.5, but got %f and %f", heatmap.Mean[2][10], heatmap.Median[2][10])
+	}
+	if heatmap.Count[3][12] != 1 || !floatEquals(heatmap.Mean[3][12], 2.0) {
+		t.Errorf("Expected Thursday at 12 to be 2.0, but got %f from %d prices", heatmap.Mean[3][12], heatmap.Count[3][12])
+	}
+	if heatmap.Weekdays[0] != "Monday" || heatmap.Weekdays[6] != "Sunday" {
+		t.Errorf("Expected the weeks to run from Monday to Sunday, but got %v", heatmap.Weekdays)
+	}
+}
+
+func TestHeatmapFilterMatches(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filter   HeatmapFilter
+		t        time.Time
+		expected bool
+	}{
+		{"No filter", HeatmapFilter{}, time.Date(2023, 12, 25, 10, 0, 0, 0, date.Location), true},
+		{"Month matches", HeatmapFilter{Month: time.December}, time.Date(2023, 12, 25, 10, 0, 0, 0, date.Location), true},
+		{"Month doesn't match", HeatmapFilter{Month: time.November}, time.Date(2023, 12, 25, 10, 0, 0, 0, date.Location), false},
+		{"Month in local time", HeatmapFilter{Month: time.December}, time.Date(2023, 11, 30, 23, 30, 0, 0, time.UTC), true},
+		{"Winter", HeatmapFilter{Season: Winter}, time.Date(2023, 2, 1, 10, 0, 0, 0, date.Location), true},
+		{"Not summer", HeatmapFilter{Season: Summer}, time.Date(2023, 9, 1, 10, 0, 0, 0, date.Location), false},
+		{"Autumn", HeatmapFilter{Season: Autumn}, time.Date(2023, 9, 1, 10, 0, 0, 0, date.Location), true},
+		{"Working day", HeatmapFilter{DayType: WorkingDay}, time.Date(2023, 12, 27, 10, 0, 0, 0, date.Location), true},
+		{"National holiday is not a working day", HeatmapFilter{DayType: WorkingDay}, time.Date(2023, 12, 25, 10, 0, 0, 0, date.Location), false},
+		{"National holiday", HeatmapFilter{DayType: Holiday}, time.Date(2023, 12, 25, 10, 0, 0, 0, date.Location), true},
+		{"Weekend is a holiday", HeatmapFilter{DayType: Holiday}, time.Date(2023, 12, 30, 10, 0, 0, 0, date.Location), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.filter.Matches(tc.t); actual != tc.expected {
+				t.Errorf("Expected %t, but got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSeasonOf(t *testing.T) {
+	testCases := []struct {
+		day      time.Time
+		expected Season
+	}{
+		{time.Date(2024, 1, 15, 0, 0, 0, 0, date.Location), Winter},
+		{time.Date(2024, 3, 1, 0, 0, 0, 0, date.Location), Spring},
+		{time.Date(2024, 7, 20, 0, 0, 0, 0, date.Location), Summer},
+		{time.Date(2024, 11, 30, 0, 0, 0, 0, date.Location), Autumn},
+		{time.Date(2024, 12, 1, 0, 0, 0, 0, date.Location), Winter},
+		// Still summer in Spain at 23:30 UTC on the 31st of August
+		{time.Date(2024, 8, 31, 21, 30, 0, 0, time.UTC), Summer},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.day.String(), func(t *testing.T) {
+			if result := SeasonOf(tc.day); result != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestCalculatePercentileRank(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    float64
+		values   []float64
+		expected float64
+	}{
+		{"Empty slice", 1.0, []float64{}, 0.0},
+		{"Cheapest", 0.5, []float64{1.0, 2.0, 3.0, 4.0}, 0.0},
+		{"Most expensive", 5.0, []float64{1.0, 2.0, 3.0, 4.0}, 100.0},
+		{"Middle", 2.5, []float64{1.0, 2.0, 3.0, 4.0}, 50.0},
+		{"Equal counts as half", 2.0, []float64{1.0, 2.0, 3.0, 4.0}, 37.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := CalculatePercentileRank(tc.value, tc.values)
+			if !floatEquals(actual, tc.expected) {
+				t.Errorf("Expected %f, but got %f", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCalculatePercentileRanks(t *testing.T) {
+	day := time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location)
+	prices := []Price{
+		{DateTime: day, Price: 1.0},
+		{DateTime: day.Add(time.Hour), Price: 3.0},
+	}
+	history := []Price{
+		{DateTime: day.AddDate(0, 0, -1), Price: 1.0},
+		{DateTime: day.AddDate(0, 0, -1).Add(time.Hour), Price: 1.0},
+		{DateTime: day.AddDate(0, 0, -2), Price: 3.0},
+		{DateTime: day.AddDate(0, 0, -2).Add(time.Hour), Price: 5.0},
+	}
+
+	rank := CalculatePercentileRanks(RankWindow{Name: "30d", Days: 30}, prices, history)
+
+	if rank.Window != "30d" || rank.Days != 2 {
+		t.Errorf("Expected window 30d with 2 days, but got %s with %d days", rank.Window, rank.Days)
+	}
+	if !floatEquals(rank.DayAverage, 50.0) {
+		t.Errorf("Expected day average rank 50, but got %f", rank.DayAverage)
+	}
+	if len(rank.Hours) != 2 || !floatEquals(rank.Hours[0], 25.0) || !floatEquals(rank.Hours[1], 62.5) {
+		t.Errorf("Expected hour ranks [25 62.5], but got %v", rank.Hours)
+	}
+
+	empty := CalculatePercentileRanks(RankWindow{Name: "sameMonth", SameMonth: true}, prices, nil)
+	if empty.Days != 0 || empty.DayAverage != 0 {
+		t.Errorf("Expected an empty rank, but got %+v", empty)
+	}
+}
+
+func TestParseRankWindows(t *testing.T) {
+	windows, err := ParseRankWindows("7, 30d,sameMonth")
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	expected := []RankWindow{{Name: "7d", Days: 7}, {Name: "30d", Days: 30}, {Name: "sameMonth", SameMonth: true}}
+	if len(windows) != len(expected) {
+		t.Fatalf("Expected %d windows, but got %d", len(expected), len(windows))
+	}
+	for i := range expected {
+		if windows[i] != expected[i] {
+			t.Errorf("Expected %+v, but got %+v", expected[i], windows[i])
+		}
+	}
+
+	for _, invalid := range []string{"", "week", "0", "-30"} {
+		if _, err := ParseRankWindows(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
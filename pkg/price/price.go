@@ -4,8 +4,41 @@ import (
 	"time"
 )
 
+// Series identifies a price series stored in the prices collection.
+type Series string
+
+const (
+	Pvpc         Series = "pvpc"
+	Surplus      Series = "surplus"
+	OmieSpain    Series = "omie-es"
+	OmiePortugal Series = "omie-pt"
+
+	IntradaySpain      Series = "intraday-es"
+	IntradayPortugal   Series = "intraday-pt"
+	ContinuousSpain    Series = "continuous-es"
+	ContinuousPortugal Series = "continuous-pt"
+)
+
+// Price is the price of an hour, in €/kWh.
+// Session is the intraday auction session the price was set in, and is only set for the intraday series.
 type Price struct {
-	ID       string    `bson:"_id,omitempty" json:"-"`
-	DateTime time.Time `bson:"dateTime" json:"dateTime"`
-	Price    float64   `bson:"price" json:"price"`
+	ID         string      `bson:"_id,omitempty" json:"-"`
+	Series     Series      `bson:"series,omitempty" json:"-"`
+	Session    int         `bson:"session,omitempty" json:"session,omitempty"`
+	DateTime   time.Time   `bson:"dateTime" json:"dateTime"`
+	Price      float64     `bson:"price" json:"price"`
+	Components *Components `bson:"components,omitempty" json:"components,omitempty"`
+	Anomalies  []Anomaly   `bson:"anomalies,omitempty" json:"anomalies,omitempty"`
+}
+
+// Components is the breakdown of the PVPC price into the terms it is made up of, in €/kWh.
+type Components struct {
+	Energy           float64 `bson:"energy" json:"energy"`                     // Day-ahead and intraday market price
+	Adjustment       float64 `bson:"adjustment" json:"adjustment"`             // Adjustment services
+	CapacityPayments float64 `bson:"capacityPayments" json:"capacityPayments"` // Capacity payments
+	Interruptibility float64 `bson:"interruptibility" json:"interruptibility"` // Interruptibility service
+	OperatorFees     float64 `bson:"operatorFees" json:"operatorFees"`         // Market and system operator funding
+	TollsAndCharges  float64 `bson:"tollsAndCharges" json:"tollsAndCharges"`   // Access tolls and charges
+	TradingCosts     float64 `bson:"tradingCosts" json:"tradingCosts"`         // Retailer trading costs
+	Other            float64 `bson:"other" json:"other"`                       // Other regulated adjustments
 }
@@ -21,6 +21,8 @@ type MockPriceService struct {
 	MockGetPricesError            *[]error
 	MockSavePricesCount           *CallCounter
 	MockSavePricesError           *[]error
+	MockReplacePrices             *[][]Price
+	MockReplacePricesError        *[]error
 	MockGetDailyPricesResult      *[][]Price
 	MockGetDailyPricesError       *[]error
 	MockGetDailyAveragesResult    *[][]DailyAverage
@@ -35,8 +37,16 @@ type MockPriceService struct {
 	MockGetCheapPeriodsError      *[]error
 	MockGetExpensivePeriodsResult *[][][]Price
 	MockGetExpensivePeriodsError  *[]error
+	MockGetUpcomingPeriodsResult  *[]UpcomingPeriods
+	MockGetUpcomingPeriodsError   *[]error
 	MockGetThirtyDayAverageResult *[]float64
 	MockGetThirtyDayAverageError  *[]error
+	MockGetStatsResult            *[]Stats
+	MockGetStatsError             *[]error
+	MockGetHeatmapResult          *[]Heatmap
+	MockGetHeatmapError           *[]error
+	MockGetAnomaliesResult        *[][]Anomaly
+	MockGetAnomaliesError         *[]error
 }
 
 func (m *MockPriceService) GetLatestPrice(ctx context.Context) (Price, bool, error) {
@@ -129,6 +139,22 @@ func (m *MockPriceService) SavePrices(ctx context.Context, prices []Price) error
 	return err
 }
 
+func (m *MockPriceService) ReplacePrices(ctx context.Context, prices []Price) error {
+	// Record the prices that were saved
+	*m.MockReplacePrices = append(*m.MockReplacePrices, prices)
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockReplacePricesError) > 0 {
+		err = (*m.MockReplacePricesError)[0]
+		*m.MockReplacePricesError = (*m.MockReplacePricesError)[1:]
+	} else {
+		err = nil
+	}
+
+	return err
+}
+
 func (m *MockPriceService) GetDailyPrices(ctx context.Context, t time.Time) ([]Price, error) {
 	// Get the first element of the result array and remove it from the array, return nil if the array is empty
 	var result []Price
@@ -173,7 +199,7 @@ func (m *MockPriceService) GetDailyAverages(ctx context.Context, t time.Time, nu
 	return result, err
 }
 
-func (m *MockPriceService) GetDailyInfo(ctx context.Context, t time.Time) (DailyPriceInfo, error) {
+func (m *MockPriceService) GetDailyInfo(ctx context.Context, t time.Time, opts Options) (DailyPriceInfo, error) {
 	// Get the first element of the result array and remove it from the array, return nil if the array is empty
 	var result DailyPriceInfo
 	if len(*m.MockGetDailyInfoResult) > 0 {
@@ -239,7 +265,7 @@ func (m *MockPriceService) GetDayAverage(ctx context.Context, t time.Time) (floa
 	return result, err
 }
 
-func (m *MockPriceService) GetCheapPeriods(ctx context.Context, t time.Time) ([][]Price, error) {
+func (m *MockPriceService) GetCheapPeriods(ctx context.Context, t time.Time, opts Options) ([][]Price, error) {
 	// Get the first element of the result array and remove it from the array, return nil if the array is empty
 	var result [][]Price
 	if len(*m.MockGetCheapPeriodsResult) > 0 {
@@ -261,7 +287,7 @@ func (m *MockPriceService) GetCheapPeriods(ctx context.Context, t time.Time) ([]
 	return result, err
 }
 
-func (m *MockPriceService) GetExpensivePeriods(ctx context.Context, t time.Time) ([][]Price, error) {
+func (m *MockPriceService) GetExpensivePeriods(ctx context.Context, t time.Time, opts Options) ([][]Price, error) {
 	// Get the first element of the result array and remove it from the array, return nil if the array is empty
 	var result [][]Price
 	if len(*m.MockGetExpensivePeriodsResult) > 0 {
@@ -305,6 +331,86 @@ func (m *MockPriceService) GetThirtyDayAverage(ctx context.Context, t time.Time)
 	return result, err
 }
 
+func (m *MockPriceService) GetStats(ctx context.Context, start time.Time, end time.Time) (Stats, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result Stats
+	if len(*m.MockGetStatsResult) > 0 {
+		result = (*m.MockGetStatsResult)[0]
+		*m.MockGetStatsResult = (*m.MockGetStatsResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetStatsError) > 0 {
+		err = (*m.MockGetStatsError)[0]
+		*m.MockGetStatsError = (*m.MockGetStatsError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockPriceService) GetHeatmap(ctx context.Context, start time.Time, end time.Time, filter HeatmapFilter) (Heatmap, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result Heatmap
+	if len(*m.MockGetHeatmapResult) > 0 {
+		result = (*m.MockGetHeatmapResult)[0]
+		*m.MockGetHeatmapResult = (*m.MockGetHeatmapResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetHeatmapError) > 0 {
+		err = (*m.MockGetHeatmapError)[0]
+		*m.MockGetHeatmapError = (*m.MockGetHeatmapError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockPriceService) GetUpcomingPeriods(ctx context.Context, t time.Time, opts Options) (UpcomingPeriods, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result UpcomingPeriods
+	if len(*m.MockGetUpcomingPeriodsResult) > 0 {
+		result = (*m.MockGetUpcomingPeriodsResult)[0]
+		*m.MockGetUpcomingPeriodsResult = (*m.MockGetUpcomingPeriodsResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetUpcomingPeriodsError) > 0 {
+		err = (*m.MockGetUpcomingPeriodsError)[0]
+		*m.MockGetUpcomingPeriodsError = (*m.MockGetUpcomingPeriodsError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockPriceService) GetAnomalies(ctx context.Context, start time.Time, end time.Time) ([]Anomaly, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []Anomaly
+	if len(*m.MockGetAnomaliesResult) > 0 {
+		result = (*m.MockGetAnomaliesResult)[0]
+		*m.MockGetAnomaliesResult = (*m.MockGetAnomaliesResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetAnomaliesError) > 0 {
+		err = (*m.MockGetAnomaliesError)[0]
+		*m.MockGetAnomaliesError = (*m.MockGetAnomaliesError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
 // A mock implementation of Client
 
 type MockPriceClient struct {
@@ -343,3 +449,30 @@ func (m *MockPriceClient) GetPrices(t time.Time) ([]Price, bool, error) {
 
 	return result, synced, err
 }
+
+// A mock implementation of ShapeSource
+
+type MockShapeSource struct {
+	MockGetShapesResult *[]map[string]string
+	MockGetShapesError  *[]error
+}
+
+func (m *MockShapeSource) GetShapes(ctx context.Context, start time.Time, end time.Time) (map[string]string, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result map[string]string
+	if len(*m.MockGetShapesResult) > 0 {
+		result = (*m.MockGetShapesResult)[0]
+		*m.MockGetShapesResult = (*m.MockGetShapesResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetShapesError) > 0 {
+		err = (*m.MockGetShapesError)[0]
+		*m.MockGetShapesError = (*m.MockGetShapesError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
@@ -0,0 +1,107 @@
+package price
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var ErrInvalidRating = errors.New("invalid rating configuration")
+
+type RatingMethod string
+
+const (
+	// AbsoluteBand rates against the baseline average plus or minus a fixed amount in €/kWh
+	AbsoluteBand RatingMethod = "absolute"
+	// PercentageBand rates against the baseline average plus or minus a fraction of it
+	PercentageBand RatingMethod = "percentage"
+	// PercentileBand rates against the percentiles of the daily averages in the baseline
+	PercentileBand RatingMethod = "percentile"
+	// SameWeekday rates against the average of the same weekday in the baseline plus or minus a fixed amount in €/kWh
+	SameWeekday RatingMethod = "weekday"
+)
+
+const defaultBaselineDays = 30
+
+// RatingConfig selects how a day is rated. Zero values use the defaults for the method.
+// The threshold is in €/kWh for the absolute and weekday methods, a fraction of the baseline
+// for the percentage method and the percentile of the good band for the percentile method.
+type RatingConfig struct {
+	Method       RatingMethod
+	Threshold    float64
+	BaselineDays int
+}
+
+// RatingDetails is the method and thresholds a day was rated with.
+type RatingDetails struct {
+	Method       RatingMethod `json:"method"`
+	Threshold    float64      `json:"threshold"`
+	BaselineDays int          `json:"baselineDays"`
+	Baseline     float64      `json:"baseline"`
+	GoodBelow    float64      `json:"goodBelow"`
+	BadAbove     float64      `json:"badAbove"`
+}
+
+// ParseRatingConfig parses the rating method, threshold and baseline days. Empty values are left as zero.
+func ParseRatingConfig(method string, threshold string, baselineDays string) (RatingConfig, error) {
+	config := RatingConfig{Method: RatingMethod(method)}
+	switch config.Method {
+	case "", AbsoluteBand, PercentageBand, PercentileBand, SameWeekday:
+	default:
+		return RatingConfig{}, fmt.Errorf("%w: unknown method %q", ErrInvalidRating, method)
+	}
+	if threshold != "" {
+		t, err := strconv.ParseFloat(threshold, 64)
+		if err != nil || t <= 0 {
+			return RatingConfig{}, fmt.Errorf("%w: threshold must be a positive number", ErrInvalidRating)
+		}
+		if config.Method == PercentileBand && t >= 50 {
+			return RatingConfig{}, fmt.Errorf("%w: percentile threshold must be below 50", ErrInvalidRating)
+		}
+		config.Threshold = t
+	}
+	if baselineDays != "" {
+		d, err := strconv.Atoi(baselineDays)
+		if err != nil || d < 1 {
+			return RatingConfig{}, fmt.Errorf("%w: baseline days must be a positive whole number", ErrInvalidRating)
+		}
+		config.BaselineDays = d
+	}
+	return config, nil
+}
+
+// Override returns the config with the fields set in other replacing its own.
+// If other changes the method the threshold reverts to the default for the new method.
+func (c RatingConfig) Override(other RatingConfig) RatingConfig {
+	if other.Method != "" && other.Method != c.Method {
+		c = RatingConfig{Method: other.Method, BaselineDays: c.BaselineDays}
+	}
+	if other.Threshold != 0 {
+		c.Threshold = other.Threshold
+	}
+	if other.BaselineDays != 0 {
+		c.BaselineDays = other.BaselineDays
+	}
+	return c
+}
+
+// WithDefaults returns the config with any zero values replaced by the defaults for the method.
+func (c RatingConfig) WithDefaults() RatingConfig {
+	if c.Method == "" {
+		c.Method = AbsoluteBand
+	}
+	if c.BaselineDays == 0 {
+		c.BaselineDays = defaultBaselineDays
+	}
+	if c.Threshold == 0 {
+		switch c.Method {
+		case PercentageBand:
+			c.Threshold = 0.1
+		case PercentileBand:
+			c.Threshold = 25
+		default:
+			c.Threshold = ratingVariance
+		}
+	}
+	return c
+}
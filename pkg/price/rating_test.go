@@ -0,0 +1,127 @@
+package price
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRatingConfig(t *testing.T) {
+	testCases := []struct {
+		name         string
+		method       string
+		threshold    string
+		baselineDays string
+		expected     RatingConfig
+		expectErr    bool
+	}{
+		{name: "Empty", expected: RatingConfig{}},
+		{name: "Absolute", method: "absolute", threshold: "0.03", expected: RatingConfig{Method: AbsoluteBand, Threshold: 0.03}},
+		{name: "Percentile", method: "percentile", threshold: "20", baselineDays: "90", expected: RatingConfig{Method: PercentileBand, Threshold: 20, BaselineDays: 90}},
+		{name: "Unknown method", method: "median", expectErr: true},
+		{name: "Invalid threshold", method: "percentage", threshold: "ten", expectErr: true},
+		{name: "Negative threshold", threshold: "-0.1", expectErr: true},
+		{name: "Percentile threshold too high", method: "percentile", threshold: "50", expectErr: true},
+		{name: "Invalid baseline days", baselineDays: "0", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := ParseRatingConfig(tc.method, tc.threshold, tc.baselineDays)
+			if tc.expectErr {
+				if !errors.Is(err, ErrInvalidRating) {
+					t.Errorf("Expected an invalid rating error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if config != tc.expected {
+				t.Errorf("Expected %+v, but got %+v", tc.expected, config)
+			}
+		})
+	}
+}
+
+func TestRatingConfigOverride(t *testing.T) {
+	deployment := RatingConfig{Method: PercentageBand, Threshold: 0.2, BaselineDays: 60}
+
+	testCases := []struct {
+		name     string
+		request  RatingConfig
+		expected RatingConfig
+	}{
+		{"No override", RatingConfig{}, deployment},
+		{"Threshold only", RatingConfig{Threshold: 0.15}, RatingConfig{Method: PercentageBand, Threshold: 0.15, BaselineDays: 60}},
+		{"Same method", RatingConfig{Method: PercentageBand}, deployment},
+		{"New method resets the threshold", RatingConfig{Method: PercentileBand}, RatingConfig{Method: PercentileBand, BaselineDays: 60}},
+		{"New method and baseline", RatingConfig{Method: AbsoluteBand, Threshold: 0.01, BaselineDays: 7}, RatingConfig{Method: AbsoluteBand, Threshold: 0.01, BaselineDays: 7}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := deployment.Override(tc.request); actual != tc.expected {
+				t.Errorf("Expected %+v, but got %+v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRatingConfigWithDefaults(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   RatingConfig
+		expected RatingConfig
+	}{
+		{"Empty", RatingConfig{}, RatingConfig{Method: AbsoluteBand, Threshold: 0.02, BaselineDays: 30}},
+		{"Percentage", RatingConfig{Method: PercentageBand}, RatingConfig{Method: PercentageBand, Threshold: 0.1, BaselineDays: 30}},
+		{"Percentile", RatingConfig{Method: PercentileBand, BaselineDays: 90}, RatingConfig{Method: PercentileBand, Threshold: 25, BaselineDays: 90}},
+		{"Weekday", RatingConfig{Method: SameWeekday}, RatingConfig{Method: SameWeekday, Threshold: 0.02, BaselineDays: 30}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.config.WithDefaults(); actual != tc.expected {
+				t.Errorf("Expected %+v, but got %+v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCalculateRating(t *testing.T) {
+	dailyAverages := []float64{0.10, 0.12, 0.14, 0.16, 0.18}
+
+	testCases := []struct {
+		name              string
+		config            RatingConfig
+		dayAvg            float64
+		baseline          float64
+		expected          DayRating
+		expectedGoodBelow float64
+		expectedBadAbove  float64
+	}{
+		{"Absolute good", RatingConfig{}, 0.10, 0.15, Good, 0.13, 0.17},
+		{"Absolute normal", RatingConfig{}, 0.14, 0.15, Normal, 0.13, 0.17},
+		{"Absolute bad", RatingConfig{Threshold: 0.01}, 0.17, 0.15, Bad, 0.14, 0.16},
+		{"Percentage scales with the baseline", RatingConfig{Method: PercentageBand}, 0.046, 0.05, Normal, 0.045, 0.055},
+		{"Percentage good", RatingConfig{Method: PercentageBand}, 0.26, 0.3, Good, 0.27, 0.33},
+		{"Percentile good", RatingConfig{Method: PercentileBand}, 0.11, 0, Good, 0.12, 0.16},
+		{"Percentile bad", RatingConfig{Method: PercentileBand, Threshold: 10}, 0.175, 0, Bad, 0.108, 0.172},
+		{"Weekday", RatingConfig{Method: SameWeekday}, 0.20, 0.15, Bad, 0.13, 0.17},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rating, details := CalculateRating(tc.config, tc.dayAvg, tc.baseline, dailyAverages)
+			if rating != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, rating)
+			}
+			if !floatEquals(details.GoodBelow, tc.expectedGoodBelow) || !floatEquals(details.BadAbove, tc.expectedBadAbove) {
+				t.Errorf("Expected thresholds %f and %f, but got %f and %f", tc.expectedGoodBelow, tc.expectedBadAbove, details.GoodBelow, details.BadAbove)
+			}
+			if details.Method == "" || details.Threshold == 0 || details.BaselineDays == 0 {
+				t.Errorf("Expected the method and thresholds to be returned, but got %+v", details)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package price
+
+import "time"
+
+type PriceAtTime struct {
+	DateTime time.Time `bson:"dateTime" json:"dateTime"`
+	Price    float64   `bson:"price" json:"price"`
+}
+
+type Percentiles struct {
+	P5  float64 `json:"p5"`
+	P10 float64 `json:"p10"`
+	P25 float64 `json:"p25"`
+	P75 float64 `json:"p75"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+}
+
+// Stats describes the distribution of the hourly prices over a range.
+type Stats struct {
+	Start            string      `json:"start"`
+	End              string      `json:"end"`
+	Count            int         `json:"count"`
+	Mean             float64     `json:"mean"`
+	Median           float64     `json:"median"`
+	StdDev           float64     `json:"stdDev"`
+	Percentiles      Percentiles `json:"percentiles"`
+	Min              PriceAtTime `json:"min"`
+	Max              PriceAtTime `json:"max"`
+	NonPositiveHours int         `json:"nonPositiveHours"`
+}
@@ -0,0 +1,26 @@
+package price
+
+import "time"
+
+// UpcomingPeriods are the cheap and expensive periods over a rolling horizon from the current hour until the last known price.
+// Today's and tomorrow's prices are grouped together, so a period can continue past midnight.
+type UpcomingPeriods struct {
+	Start            time.Time `json:"start"`
+	End              time.Time `json:"end"`
+	PeriodStrategy   string    `json:"periodStrategy"`
+	Prices           []Price   `json:"prices"`
+	CheapPeriods     [][]Price `json:"cheapestPeriods"`
+	ExpensivePeriods [][]Price `json:"expensivePeriods"`
+}
+
+// WithoutPastPeriods
+// Get the periods that have not ended by the given hour.
+func WithoutPastPeriods(periods [][]Price, hour time.Time) [][]Price {
+	result := make([][]Price, 0)
+	for _, period := range periods {
+		if len(period) > 0 && !period[len(period)-1].DateTime.Before(hour) {
+			result = append(result, period)
+		}
+	}
+	return result
+}
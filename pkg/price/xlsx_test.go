@@ -0,0 +1,85 @@
+package price
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	tables := []Table{
+		{Name: "Prices", Header: []string{"dateTime", "price"}, Rows: [][]any{{time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC), 0.12345}}},
+		{Name: "A & B", Header: []string{"name", "value"}, Rows: [][]any{{"<rating>", 3}, {"cheap", true}, {"empty", nil}}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, tables); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Expected a zip archive, but got %s", err)
+	}
+	files := make(map[string]string)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %s", f.Name, err)
+		}
+		content, _ := io.ReadAll(r)
+		files[f.Name] = string(content)
+	}
+
+	expected := map[string][]string{
+		"[Content_Types].xml":        {`PartName="/xl/worksheets/sheet2.xml"`},
+		"_rels/.rels":                {`Target="xl/workbook.xml"`},
+		"xl/workbook.xml":            {`<sheet name="Prices" sheetId="1" r:id="rId1"/>`, `<sheet name="A &amp; B" sheetId="2" r:id="rId2"/>`},
+		"xl/_rels/workbook.xml.rels": {`Id="rId2"`, `Target="worksheets/sheet2.xml"`},
+		"xl/worksheets/sheet1.xml": {
+			`<c r="A1" t="inlineStr"><is><t>dateTime</t></is></c>`,
+			`<c r="A2" t="inlineStr"><is><t>2024-05-15T00:00:00Z</t></is></c><c r="B2"><v>0.12345</v></c>`,
+		},
+		"xl/worksheets/sheet2.xml": {
+			`<t>&lt;rating&gt;</t>`,
+			`<c r="B2"><v>3</v></c>`,
+			`<c r="B3" t="b"><v>1</v></c>`,
+			`<row r="4"><c r="A4" t="inlineStr"><is><t>empty</t></is></c></row>`,
+		},
+	}
+	for name, contents := range expected {
+		file, ok := files[name]
+		if !ok {
+			t.Errorf("Expected the workbook to contain %s", name)
+			continue
+		}
+		for _, content := range contents {
+			if !strings.Contains(file, content) {
+				t.Errorf("Expected %s to contain %s, but got\n%s", name, content, file)
+			}
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		index    int
+		expected string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if name := columnName(tt.index); name != tt.expected {
+			t.Errorf("Expected column %d to be %s, but got %s", tt.index, tt.expected, name)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package profile
+
+import (
+	"context"
+	"electricity-prices/pkg/db"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+)
+
+type Collection interface {
+	db.Collection[DayLabel]
+	UpsertMany(ctx context.Context, documents []DayLabel) error
+}
+
+type ColReceiver struct {
+	Col *mongo.Collection
+}
+
+func (r ColReceiver) FindOne(ctx context.Context, filter interface{}) (DayLabel, error) {
+	var l DayLabel
+	err := r.Col.FindOne(ctx, filter).Decode(&l)
+
+	if err != nil {
+		return DayLabel{}, err
+	}
+
+	return l, err
+}
+
+func (r ColReceiver) Find(ctx context.Context, filter interface{}) ([]DayLabel, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cur, err := r.Col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(cur *mongo.Cursor, ctx context.Context) {
+		err := cur.Close(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(cur, ctx)
+
+	var labels = make([]DayLabel, 0)
+
+	for cur.Next(ctx) {
+		var l DayLabel
+		err := cur.Decode(&l)
+		if err != nil {
+			log.Println("Error decoding day label:", err)
+			continue
+		}
+		labels = append(labels, l)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+func (r ColReceiver) InsertMany(ctx context.Context, documents []DayLabel) error {
+	var documentsInterface []interface{}
+	for _, doc := range documents {
+		documentsInterface = append(documentsInterface, doc)
+	}
+
+	_, err := r.Col.InsertMany(ctx, documentsInterface)
+	return err
+}
+
+// UpsertMany stores the labels, replacing any existing label for the same day.
+func (r ColReceiver) UpsertMany(ctx context.Context, documents []DayLabel) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(documents))
+	for i, doc := range documents {
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"date": doc.Date}).
+			SetReplacement(doc).
+			SetUpsert(true)
+	}
+
+	_, err := r.Col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r ColReceiver) Aggregate(ctx context.Context, pipeline interface{}) (*mongo.Cursor, error) {
+	cursor, err := r.Col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,214 @@
+package profile
+
+import (
+	"electricity-prices/pkg/api"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the profile endpoints. Taxes is optional and is needed to return prices with taxes.
+type Handler struct {
+	ProfileService Service
+	Taxes          price.TaxSchedule
+}
+
+// GetSimilarDays @Summary Get similar days
+// @Description Returns the historical days whose hourly price shape is most similar to the date, closest first. Defaults to today.
+// @Description The prices of each day are normalised to a mean of zero and a standard deviation of one before comparing, so days with different price levels but the same shape match.
+// @Tags Profile
+// @ID get-similar-days
+// @Produce  json
+// @Param date query string false "Date in format yyyy-MM-dd"
+// @Param metric query string false "Distance metric, euclidean (default) or dtw"
+// @Param sameSeason query bool false "Only compare with days in the same season"
+// @Param sameDayType query bool false "Only compare with days that are also working days, or also weekends and holidays"
+// @Param days query int false "Number of days of history to search, defaults to 365"
+// @Param limit query int false "Number of days to return, defaults to 5"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT"
+// @Success 200 {object} profile.SimilarDays
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /profile/similar [get]
+func (h *Handler) GetSimilarDays(c *gin.Context) {
+
+	// Get the date string from the request
+	dateStr := c.DefaultQuery("date", time.Now().Format("2006-01-02")) // Default to today if not provided
+
+	// Parse the date string
+	d, err := date.ParseDate(dateStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse date. Ensure it is in the format yyyy-MM-dd."})
+		return
+	}
+
+	// Parse the search options
+	metric, ok := ParseMetric(c.DefaultQuery("metric", string(Euclidean)))
+	if !ok {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Unknown metric. Use euclidean or dtw."})
+		return
+	}
+	opts := SearchOptions{
+		Metric:      metric,
+		SameSeason:  c.Query("sameSeason") == "true",
+		SameDayType: c.Query("sameDayType") == "true",
+	}
+	opts.Days, err = strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(defaultDays)))
+	if err != nil || opts.Days < 1 {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Days must be a positive number."})
+		return
+	}
+	opts.Limit, err = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || opts.Limit < 1 {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Limit must be a positive number."})
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := price.ParseOutput(c.Query("unit"), c.Query("taxes"), c.Query("timezone"), h.Taxes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	similarDays, err := h.ProfileService.GetSimilarDays(ctx, d, opts)
+	if errors.Is(err, ErrDayNotFound) {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Message: "No prices found for the date."})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	similarDays, err = similarDaysWithOutput(similarDays, output)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, similarDays)
+}
+
+// GetClusters @Summary Get day shape clusters
+// @Description Groups the days between the start and end dates (inclusive) by the shape of their prices, such as a solar duck curve or an evening peak. Defaults to the last 365 days.
+// @Description Each cluster has the number of days in it, its centroid as a normalised shape and the average price of its days at each hour.
+// @Tags Profile
+// @ID get-clusters
+// @Produce  json
+// @Param start query string false "Start date in format yyyy-MM-dd"
+// @Param end query string false "End date in format yyyy-MM-dd"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT"
+// @Success 200 {object} profile.ClusterReport
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /profile/clusters [get]
+func (h *Handler) GetClusters(c *gin.Context) {
+	start, end, ok := parseDateRange(c, defaultDays)
+	if !ok {
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := price.ParseOutput(c.Query("unit"), c.Query("taxes"), c.Query("timezone"), h.Taxes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	report, err := h.ProfileService.GetClusters(ctx, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	report, err = clustersWithOutput(report, output)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, report)
+}
+
+// GetLabels @Summary Get day shape labels
+// @Description Returns the shape cluster of each day between the start and end dates (inclusive), as labelled by the sync job. Defaults to the last 30 days.
+// @Tags Profile
+// @ID get-labels
+// @Produce  json
+// @Param start query string false "Start date in format yyyy-MM-dd"
+// @Param end query string false "End date in format yyyy-MM-dd"
+// @Param unit query string false "Price unit, eur-kwh (default), cent-kwh or eur-mwh"
+// @Param taxes query bool false "Include the electricity tax and VAT"
+// @Param timezone query string false "IANA time zone of the returned times, such as Europe/Madrid. Defaults to UTC"
+// @Success 200 {array} profile.DayLabel
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /profile/labels [get]
+func (h *Handler) GetLabels(c *gin.Context) {
+	start, end, ok := parseDateRange(c, 30)
+	if !ok {
+		return
+	}
+
+	// Parse the unit, taxes and time zone to return the prices in
+	output, err := price.ParseOutput(c.Query("unit"), c.Query("taxes"), c.Query("timezone"), h.Taxes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	labels, err := h.ProfileService.GetLabels(ctx, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	labels, err = labelsWithOutput(labels, output)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, labels)
+}
+
+// parseDateRange
+// Parse the start and end dates from the request, defaulting to the given number of days up to today.
+// A bad request response is written when the dates are invalid.
+func parseDateRange(c *gin.Context, days int) (time.Time, time.Time, bool) {
+	endStr := c.DefaultQuery("end", time.Now().Format("2006-01-02")) // Default to today if not provided
+	end, err := date.ParseDate(endStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse end date. Ensure it is in the format yyyy-MM-dd."})
+		return time.Time{}, time.Time{}, false
+	}
+	startStr := c.DefaultQuery("start", end.AddDate(0, 0, -days).Format("2006-01-02"))
+	start, err := date.ParseDate(startStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse start date. Ensure it is in the format yyyy-MM-dd."})
+		return time.Time{}, time.Time{}, false
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "End date must not be before the start date."})
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
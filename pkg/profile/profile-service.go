@@ -0,0 +1,138 @@
+package profile
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+type Service interface {
+	GetSimilarDays(ctx context.Context, day time.Time, opts SearchOptions) (SimilarDays, error)
+	GetClusters(ctx context.Context, start time.Time, end time.Time) (ClusterReport, error)
+	RecordLabels(ctx context.Context, start time.Time, end time.Time) ([]DayLabel, error)
+	GetLabels(ctx context.Context, start time.Time, end time.Time) ([]DayLabel, error)
+	GetLabel(ctx context.Context, day time.Time) (DayLabel, error)
+}
+
+// Receiver compares the price profiles of the days in the PriceService and stores the shape label of each day in the Collection.
+type Receiver struct {
+	PriceService price.Service
+	Collection   Collection
+}
+
+// GetSimilarDays finds the days before the given day whose price shape is most similar to it.
+func (r *Receiver) GetSimilarDays(ctx context.Context, day time.Time, opts SearchOptions) (SimilarDays, error) {
+	opts = opts.withDefaults()
+
+	start := date.StartOfDay(day)
+	prices, err := r.PriceService.GetPrices(ctx, start.AddDate(0, 0, -opts.Days), start.AddDate(0, 0, 1).Add(-time.Second))
+	if err != nil {
+		return SimilarDays{}, err
+	}
+
+	var target *DayProfile
+	candidates := make([]DayProfile, 0, opts.Days)
+	for _, p := range CalculateProfiles(prices) {
+		if p.Date.Equal(start) {
+			target = &p
+			continue
+		}
+		candidates = append(candidates, p)
+	}
+	if target == nil {
+		return SimilarDays{}, ErrDayNotFound
+	}
+
+	return SimilarDays{
+		Date:       date.ParseToLocalDay(start),
+		Metric:     opts.Metric,
+		Season:     price.SeasonOf(start),
+		WorkingDay: date.IsWorkingDay(start),
+		Average:    target.Average,
+		Prices:     target.Prices,
+		Matches:    FindSimilarDays(*target, candidates, opts),
+	}, nil
+}
+
+// GetClusters groups the days between start and end (inclusive) by the shape of their prices.
+func (r *Receiver) GetClusters(ctx context.Context, start time.Time, end time.Time) (ClusterReport, error) {
+	from, to := dayRange(start, end)
+	prices, err := r.PriceService.GetPrices(ctx, from, to)
+	if err != nil {
+		return ClusterReport{}, err
+	}
+
+	clusters, _ := ClusterDays(CalculateProfiles(prices))
+	return ClusterReport{
+		Start:    date.ParseToLocalDay(from),
+		End:      date.ParseToLocalDay(to),
+		Clusters: clusters,
+	}, nil
+}
+
+// RecordLabels clusters the days between start and end (inclusive) and stores the label of each day, replacing any made earlier.
+// The clusters move as days are added, so the whole range is labelled again each time.
+func (r *Receiver) RecordLabels(ctx context.Context, start time.Time, end time.Time) ([]DayLabel, error) {
+	from, to := dayRange(start, end)
+	prices, err := r.PriceService.GetPrices(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	_, labels := ClusterDays(CalculateProfiles(prices))
+	now := time.Now()
+	for i := range labels {
+		labels[i].CreatedAt = now
+	}
+
+	err = r.Collection.UpsertMany(ctx, labels)
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// GetLabels gets the stored labels of the days between start and end (inclusive).
+func (r *Receiver) GetLabels(ctx context.Context, start time.Time, end time.Time) ([]DayLabel, error) {
+	from, to := dayRange(start, end)
+	return r.Collection.Find(ctx, bson.M{
+		"date": bson.M{
+			"$gte": date.ParseToLocalDay(from),
+			"$lte": date.ParseToLocalDay(to),
+		},
+	})
+}
+
+// GetLabel gets the stored label of the day.
+func (r *Receiver) GetLabel(ctx context.Context, day time.Time) (DayLabel, error) {
+	labels, err := r.GetLabels(ctx, day, day)
+	if err != nil {
+		return DayLabel{}, err
+	}
+	if len(labels) == 0 {
+		return DayLabel{}, ErrLabelNotFound
+	}
+	return labels[0], nil
+}
+
+// GetShapes gets the stored shape of the days between start and end (inclusive), keyed by date, so the price
+// service can label its daily info and averages.
+func (r *Receiver) GetShapes(ctx context.Context, start time.Time, end time.Time) (map[string]string, error) {
+	labels, err := r.GetLabels(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	shapes := make(map[string]string, len(labels))
+	for _, l := range labels {
+		shapes[l.Date] = string(l.Shape)
+	}
+	return shapes, nil
+}
+
+// dayRange
+// Get the start of the first day and the end of the last day.
+func dayRange(start time.Time, end time.Time) (time.Time, time.Time) {
+	return date.StartOfDay(start), date.StartOfDay(end).AddDate(0, 0, 1).Add(-time.Second)
+}
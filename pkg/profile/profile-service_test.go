@@ -0,0 +1,221 @@
+package profile
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetSimilarDays(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+
+	var history []price.Price
+	history = append(history, dayPrices(day.AddDate(0, 0, -2), eveningPeak, 1, 0)...)
+	history = append(history, dayPrices(day.AddDate(0, 0, -1), duckCurve, 2, 0)...)
+
+	tests := []struct {
+		name            string
+		prices          []price.Price
+		pricesErr       error
+		expectedErr     error
+		expectedMatches []string
+	}{
+		{"Similar days", append(history, dayPrices(day, duckCurve, 1, 0)...), nil, nil, []string{"2024-05-14", "2024-05-13"}},
+		{"No history", dayPrices(day, duckCurve, 1, 0), nil, nil, []string{}},
+		{"No prices for the day", history, nil, ErrDayNotFound, nil},
+		{"Error", nil, errors.New("error"), errors.New("error"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPriceService := &price.MockPriceService{
+				MockGetPricesResult: &[][]price.Price{tt.prices},
+				MockGetPricesError:  &[]error{tt.pricesErr},
+			}
+			service := &Receiver{PriceService: mockPriceService}
+
+			result, err := service.GetSimilarDays(ctx, day, SearchOptions{})
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "2024-05-15", result.Date)
+			assert.Equal(t, Euclidean, result.Metric)
+			assert.Equal(t, price.Spring, result.Season)
+			assert.True(t, result.WorkingDay)
+			assert.Len(t, result.Prices, 24)
+			dates := make([]string, 0)
+			for _, m := range result.Matches {
+				dates = append(dates, m.Date)
+			}
+			assert.Equal(t, tt.expectedMatches, dates)
+		})
+	}
+}
+
+func TestGetClusters(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, date.Location)
+	end := start.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name           string
+		prices         []price.Price
+		pricesErr      error
+		expectedShapes []Shape
+	}{
+		{"Clusters", append(dayPrices(start, duckCurve, 1, 0), dayPrices(end, eveningPeak, 1, 0)...), nil, []Shape{DuckCurve, EveningPeak}},
+		{"No prices", []price.Price{}, nil, []Shape{}},
+		{"Error", nil, errors.New("error"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPriceService := &price.MockPriceService{
+				MockGetPricesResult: &[][]price.Price{tt.prices},
+				MockGetPricesError:  &[]error{tt.pricesErr},
+			}
+			service := &Receiver{PriceService: mockPriceService}
+
+			report, err := service.GetClusters(ctx, start, end)
+
+			if tt.pricesErr != nil {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "2024-05-01", report.Start)
+			assert.Equal(t, "2024-05-02", report.End)
+			shapes := make([]Shape, 0)
+			for _, c := range report.Clusters {
+				shapes = append(shapes, c.Shape)
+			}
+			assert.Equal(t, tt.expectedShapes, shapes)
+		})
+	}
+}
+
+func TestRecordLabels(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, date.Location)
+	end := start.AddDate(0, 0, 1)
+	prices := append(dayPrices(start, duckCurve, 1, 0), dayPrices(end, eveningPeak, 1, 0)...)
+
+	tests := []struct {
+		name      string
+		upsertErr error
+	}{
+		{"Labels", nil},
+		{"Error", errors.New("error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPriceService := &price.MockPriceService{
+				MockGetPricesResult: &[][]price.Price{prices},
+				MockGetPricesError:  &[]error{nil},
+			}
+			mockCollection := &MockCollection{
+				MockUpsertMany:    &[][]DayLabel{},
+				MockUpsertManyErr: &[]error{tt.upsertErr},
+			}
+			service := &Receiver{PriceService: mockPriceService, Collection: mockCollection}
+
+			labels, err := service.RecordLabels(ctx, start, end)
+
+			if tt.upsertErr != nil {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, *mockCollection.MockUpsertMany, 1)
+			assert.Equal(t, labels, (*mockCollection.MockUpsertMany)[0])
+			assert.Equal(t, "2024-05-01", labels[0].Date)
+			assert.Equal(t, DuckCurve, labels[0].Shape)
+			assert.Equal(t, "2024-05-02", labels[1].Date)
+			assert.Equal(t, EveningPeak, labels[1].Shape)
+			assert.False(t, labels[0].CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestGetLabel(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, date.Location)
+	label := DayLabel{Date: "2024-05-01", Shape: DuckCurve}
+
+	tests := []struct {
+		name        string
+		labels      []DayLabel
+		findErr     error
+		expected    DayLabel
+		expectedErr error
+	}{
+		{"Label", []DayLabel{label}, nil, label, nil},
+		{"Not labelled", []DayLabel{}, nil, DayLabel{}, ErrLabelNotFound},
+		{"Error", nil, errors.New("error"), DayLabel{}, errors.New("error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockFindResult: &[][]DayLabel{tt.labels},
+				MockFindErr:    &[]error{tt.findErr},
+			}
+			service := &Receiver{Collection: mockCollection}
+
+			result, err := service.GetLabel(ctx, day)
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestGetShapes(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, date.Location)
+	labels := []DayLabel{{Date: "2024-05-01", Shape: DuckCurve}, {Date: "2024-05-02", Shape: EveningPeak}}
+
+	tests := []struct {
+		name        string
+		labels      []DayLabel
+		findErr     error
+		expected    map[string]string
+		expectedErr error
+	}{
+		{"Shapes", labels, nil, map[string]string{"2024-05-01": "SOLAR_DUCK_CURVE", "2024-05-02": "EVENING_PEAK"}, nil},
+		{"Not labelled", []DayLabel{}, nil, map[string]string{}, nil},
+		{"Error", nil, errors.New("error"), nil, errors.New("error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollection := &MockCollection{
+				MockFindResult: &[][]DayLabel{tt.labels},
+				MockFindErr:    &[]error{tt.findErr},
+			}
+			service := &Receiver{Collection: mockCollection}
+
+			result, err := service.GetShapes(ctx, start, start.AddDate(0, 0, 1))
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
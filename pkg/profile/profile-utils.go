@@ -0,0 +1,440 @@
+package profile
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	hoursInProfile = 24
+	dtwWindow      = 2
+	defaultDays    = 365
+	defaultLimit   = 5
+	flatDayStdDev  = 1e-9
+	// flatRelativeStdDev is the largest standard deviation, relative to the day's average, of a flat day
+	flatRelativeStdDev = 0.1
+	clusterIterations  = 100
+)
+
+// prototypes are the typical hourly prices of each shape.
+// They are the starting centroids of the clusters, so each cluster keeps the label of the shape it started from.
+var prototypes = map[Shape][]float64{
+	DuckCurve: {
+		0.10, 0.09, 0.09, 0.09, 0.09, 0.10, 0.11, 0.12, 0.12, 0.10, 0.07, 0.04,
+		0.03, 0.03, 0.03, 0.04, 0.06, 0.09, 0.13, 0.16, 0.17, 0.16, 0.14, 0.12,
+	},
+	EveningPeak: {
+		0.10, 0.09, 0.09, 0.09, 0.09, 0.09, 0.10, 0.10, 0.10, 0.10, 0.10, 0.10,
+		0.10, 0.10, 0.10, 0.10, 0.10, 0.11, 0.13, 0.17, 0.19, 0.18, 0.14, 0.11,
+	},
+	DoublePeak: {
+		0.10, 0.09, 0.09, 0.09, 0.09, 0.10, 0.12, 0.15, 0.17, 0.16, 0.14, 0.13,
+		0.12, 0.12, 0.12, 0.12, 0.13, 0.14, 0.16, 0.18, 0.19, 0.18, 0.15, 0.12,
+	},
+}
+
+// CalculateProfiles
+// Group the prices into the profile of each local day, in date order.
+// The hours repeated or skipped when the clocks change are averaged or filled so every profile has 24 prices.
+func CalculateProfiles(prices []price.Price) []DayProfile {
+	days := make(map[string]map[int][]float64)
+	starts := make(map[string]time.Time)
+	for _, p := range prices {
+		key := date.ParseToLocalDay(p.DateTime)
+		if _, ok := days[key]; !ok {
+			days[key] = make(map[int][]float64)
+			starts[key] = date.StartOfDay(p.DateTime)
+		}
+		hour := p.DateTime.In(date.Location).Hour()
+		days[key][hour] = append(days[key][hour], p.Price)
+	}
+
+	profiles := make([]DayProfile, 0, len(days))
+	for key, hours := range days {
+		profiles = append(profiles, newProfile(starts[key], hours))
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Date.Before(profiles[j].Date) })
+	return profiles
+}
+
+// FindSimilarDays
+// Rank the candidate profiles by their distance to the target, closest first, keeping the ones that match the options.
+func FindSimilarDays(target DayProfile, candidates []DayProfile, opts SearchOptions) []Match {
+	opts = opts.withDefaults()
+
+	matches := make([]Match, 0, len(candidates))
+	for _, c := range candidates {
+		if c.Date.Equal(target.Date) {
+			continue
+		}
+		if opts.SameSeason && price.SeasonOf(c.Date) != price.SeasonOf(target.Date) {
+			continue
+		}
+		if opts.SameDayType && date.IsWorkingDay(c.Date) != date.IsWorkingDay(target.Date) {
+			continue
+		}
+		matches = append(matches, Match{
+			Date:       date.ParseToLocalDay(c.Date),
+			Distance:   Distance(target.Normalised, c.Normalised, opts.Metric),
+			Season:     price.SeasonOf(c.Date),
+			WorkingDay: date.IsWorkingDay(c.Date),
+			Average:    c.Average,
+			Prices:     c.Prices,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Distance == matches[j].Distance {
+			return matches[i].Date > matches[j].Date
+		}
+		return matches[i].Distance < matches[j].Distance
+	})
+	if len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+	return matches
+}
+
+// ClusterDays
+// Group the days by the shape of their prices and label each day with its cluster.
+// Flat days are set apart first, then the remaining days are clustered with k-means starting from the shape prototypes.
+// Only the clusters with days are returned.
+func ClusterDays(profiles []DayProfile) ([]Cluster, []DayLabel) {
+	shapes := []Shape{DuckCurve, EveningPeak, DoublePeak}
+	centroids := make([][]float64, len(shapes))
+	for i, shape := range shapes {
+		centroids[i] = normalise(prototypes[shape])
+	}
+
+	// Assign the flat days to -1 and cluster the rest
+	assignments := make([]int, len(profiles))
+	var shaped []int
+	for i, p := range profiles {
+		if isFlat(p) {
+			assignments[i] = -1
+			continue
+		}
+		shaped = append(shaped, i)
+	}
+
+	for iteration := 0; iteration < clusterIterations; iteration++ {
+		changed := iteration == 0
+		for _, i := range shaped {
+			c := nearestCentroid(centroids, profiles[i].Normalised)
+			if c != assignments[i] {
+				assignments[i] = c
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		// Move each centroid to the average shape of its days, leaving the centroids without days where they are
+		for c := range centroids {
+			var members [][]float64
+			for _, i := range shaped {
+				if assignments[i] == c {
+					members = append(members, profiles[i].Normalised)
+				}
+			}
+			if len(members) > 0 {
+				centroids[c] = averageHours(members)
+			}
+		}
+	}
+
+	var clusters []Cluster
+	labels := make([]DayLabel, len(profiles))
+	for c, shape := range append([]Shape{Flat}, shapes...) {
+		var normalised, prices [][]float64
+		for i, p := range profiles {
+			if assignments[i] == c-1 {
+				normalised = append(normalised, p.Normalised)
+				prices = append(prices, p.Prices)
+			}
+		}
+		if len(normalised) == 0 {
+			continue
+		}
+
+		cluster := Cluster{Shape: shape, Days: len(normalised), Centroid: averageHours(normalised), Prices: averageHours(prices)}
+		clusters = append(clusters, cluster)
+		for i, p := range profiles {
+			if assignments[i] == c-1 {
+				labels[i] = DayLabel{
+					Date:     date.ParseToLocalDay(p.Date),
+					Shape:    shape,
+					Distance: euclideanDistance(p.Normalised, cluster.Centroid),
+					Average:  p.Average,
+				}
+			}
+		}
+	}
+	if clusters == nil {
+		clusters = []Cluster{}
+	}
+	return clusters, labels
+}
+
+// Distance
+// Calculate the distance between two normalised profiles with the metric.
+// The distance is the root mean square difference per hour, so both metrics are on the same scale.
+func Distance(a []float64, b []float64, metric Metric) float64 {
+	if metric == DTW {
+		return dtwDistance(a, b)
+	}
+	return euclideanDistance(a, b)
+}
+
+// euclideanDistance
+// Calculate the root mean square difference of the profiles, hour by hour.
+func euclideanDistance(a []float64, b []float64) float64 {
+	n := min(len(a), len(b))
+	if n == 0 {
+		return 0.0
+	}
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		sum += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(sum / float64(n))
+}
+
+// dtwDistance
+// Calculate the dynamic time warping distance of the profiles, letting an hour match another up to two hours away.
+func dtwDistance(a []float64, b []float64) float64 {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return 0.0
+	}
+
+	cost := make([][]float64, n+1)
+	steps := make([][]int, n+1)
+	for i := range cost {
+		cost[i] = make([]float64, m+1)
+		steps[i] = make([]int, m+1)
+		for j := range cost[i] {
+			cost[i][j] = math.Inf(1)
+		}
+	}
+	cost[0][0] = 0
+
+	for i := 1; i <= n; i++ {
+		for j := max(1, i-dtwWindow); j <= min(m, i+dtwWindow); j++ {
+			d := (a[i-1] - b[j-1]) * (a[i-1] - b[j-1])
+
+			// Take the cheapest of the match, insertion and deletion
+			bestCost, bestSteps := cost[i-1][j-1], steps[i-1][j-1]
+			if cost[i-1][j] < bestCost {
+				bestCost, bestSteps = cost[i-1][j], steps[i-1][j]
+			}
+			if cost[i][j-1] < bestCost {
+				bestCost, bestSteps = cost[i][j-1], steps[i][j-1]
+			}
+			cost[i][j] = bestCost + d
+			steps[i][j] = bestSteps + 1
+		}
+	}
+
+	if math.IsInf(cost[n][m], 1) {
+		return euclideanDistance(a, b)
+	}
+	return math.Sqrt(cost[n][m] / float64(steps[n][m]))
+}
+
+// isFlat
+// Check whether the day's prices hardly change through the day.
+func isFlat(p DayProfile) bool {
+	variance := 0.0
+	for _, v := range p.Prices {
+		variance += (v - p.Average) * (v - p.Average)
+	}
+	stdDev := math.Sqrt(variance / float64(len(p.Prices)))
+	return stdDev <= flatRelativeStdDev*math.Abs(p.Average)
+}
+
+// nearestCentroid
+// Get the index of the centroid closest to the profile, preferring the earlier one on a tie.
+func nearestCentroid(centroids [][]float64, profile []float64) int {
+	nearest := 0
+	for c := range centroids {
+		if euclideanDistance(profile, centroids[c]) < euclideanDistance(profile, centroids[nearest]) {
+			nearest = c
+		}
+	}
+	return nearest
+}
+
+// averageHours
+// Average the profiles hour by hour.
+func averageHours(profiles [][]float64) []float64 {
+	result := make([]float64, hoursInProfile)
+	for _, p := range profiles {
+		for h := 0; h < hoursInProfile && h < len(p); h++ {
+			result[h] += p[h] / float64(len(profiles))
+		}
+	}
+	return result
+}
+
+// newProfile
+// Build the profile of a day from its prices grouped by local hour.
+func newProfile(day time.Time, hours map[int][]float64) DayProfile {
+	averages := make(map[int]float64, len(hours))
+	for h, values := range hours {
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		averages[h] = sum / float64(len(values))
+	}
+
+	prices := make([]float64, hoursInProfile)
+	for h := 0; h < hoursInProfile; h++ {
+		prices[h] = nearestHour(averages, h)
+	}
+
+	return DayProfile{
+		Date:       day,
+		Prices:     prices,
+		Normalised: normalise(prices),
+		Average:    mean(prices),
+	}
+}
+
+// nearestHour
+// Get the price of the hour, or of the nearest known hour if it is missing, preferring the earlier one.
+func nearestHour(hours map[int]float64, hour int) float64 {
+	if p, ok := hours[hour]; ok {
+		return p
+	}
+	for offset := 1; offset < hoursInProfile; offset++ {
+		if p, ok := hours[hour-offset]; ok {
+			return p
+		}
+		if p, ok := hours[hour+offset]; ok {
+			return p
+		}
+	}
+	return 0.0
+}
+
+// normalise
+// Scale the prices to a mean of zero and a standard deviation of one. A flat day normalises to zeros.
+func normalise(prices []float64) []float64 {
+	avg := mean(prices)
+	variance := 0.0
+	for _, p := range prices {
+		variance += (p - avg) * (p - avg)
+	}
+	stdDev := math.Sqrt(variance / float64(len(prices)))
+
+	result := make([]float64, len(prices))
+	if stdDev < flatDayStdDev {
+		return result
+	}
+	for i, p := range prices {
+		result[i] = (p - avg) / stdDev
+	}
+	return result
+}
+
+// mean
+// Calculate the average of the values.
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// withDefaults
+// Fill in the options that were not set.
+func (o SearchOptions) withDefaults() SearchOptions {
+	if o.Metric == "" {
+		o.Metric = Euclidean
+	}
+	if o.Days <= 0 {
+		o.Days = defaultDays
+	}
+	if o.Limit <= 0 {
+		o.Limit = defaultLimit
+	}
+	return o
+}
+
+// similarDaysWithOutput
+// Convert the prices of the day and its matches to the output unit, using the taxes in effect on each day.
+func similarDaysWithOutput(similar SimilarDays, output price.Output) (SimilarDays, error) {
+	factor, err := output.DayFactor(similar.Date)
+	if err != nil {
+		return SimilarDays{}, err
+	}
+	similar.Average *= factor
+	similar.Prices = scale(similar.Prices, factor)
+
+	matches := make([]Match, len(similar.Matches))
+	for i, m := range similar.Matches {
+		factor, err := output.DayFactor(m.Date)
+		if err != nil {
+			return SimilarDays{}, err
+		}
+		m.Average *= factor
+		m.Prices = scale(m.Prices, factor)
+		matches[i] = m
+	}
+	similar.Matches = matches
+	return similar, nil
+}
+
+// clustersWithOutput
+// Convert the average prices of the clusters to the output unit, using the taxes in effect on the last day.
+// The centroids are normalised and have no unit.
+func clustersWithOutput(report ClusterReport, output price.Output) (ClusterReport, error) {
+	factor, err := output.DayFactor(report.End)
+	if err != nil {
+		return ClusterReport{}, err
+	}
+
+	clusters := make([]Cluster, len(report.Clusters))
+	for i, c := range report.Clusters {
+		c.Prices = scale(c.Prices, factor)
+		clusters[i] = c
+	}
+	report.Clusters = clusters
+	return report, nil
+}
+
+// labelsWithOutput
+// Convert the average price of each labelled day to the output unit and its creation time to the output time zone.
+func labelsWithOutput(labels []DayLabel, output price.Output) ([]DayLabel, error) {
+	result := make([]DayLabel, len(labels))
+	for i, l := range labels {
+		factor, err := output.DayFactor(l.Date)
+		if err != nil {
+			return nil, err
+		}
+		l.Average *= factor
+		l.CreatedAt = output.Time(l.CreatedAt)
+		result[i] = l
+	}
+	return result, nil
+}
+
+// scale
+// Multiply each value by the factor.
+func scale(values []float64, factor float64) []float64 {
+	result := make([]float64, len(values))
+	for i, v := range values {
+		result[i] = v * factor
+	}
+	return result
+}
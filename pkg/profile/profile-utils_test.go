@@ -0,0 +1,262 @@
+package profile
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"math"
+	"testing"
+	"time"
+)
+
+// dayPrices builds a day of hourly prices from a shape, scaled and shifted.
+func dayPrices(day time.Time, shape func(hour int) float64, scale float64, offset float64) []price.Price {
+	start := date.StartOfDay(day)
+	prices := make([]price.Price, 0, 24)
+	for h := 0; h < date.HoursInDay(start); h++ {
+		t := start.Add(time.Duration(h) * time.Hour)
+		prices = append(prices, price.Price{DateTime: t, Price: offset + scale*shape(t.In(date.Location).Hour())})
+	}
+	return prices
+}
+
+func duckCurve(hour int) float64 {
+	if hour >= 11 && hour <= 16 {
+		return 0.02
+	}
+	if hour >= 19 && hour <= 22 {
+		return 0.2
+	}
+	return 0.1
+}
+
+func eveningPeak(hour int) float64 {
+	if hour >= 19 && hour <= 22 {
+		return 0.25
+	}
+	return 0.1
+}
+
+func shiftedDuckCurve(hour int) float64 {
+	return duckCurve(hour - 1)
+}
+
+func shallowDuckCurve(hour int) float64 {
+	if hour >= 19 && hour <= 22 {
+		return 0.15
+	}
+	return duckCurve(hour)
+}
+
+func TestCalculateProfiles(t *testing.T) {
+	testCases := []struct {
+		name     string
+		day      time.Time
+		expected float64
+	}{
+		{"Normal day", time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location), 0.1},
+		{"Clocks go forward", time.Date(2024, 3, 31, 0, 0, 0, 0, date.Location), 0.1},
+		{"Clocks go back", time.Date(2024, 10, 27, 0, 0, 0, 0, date.Location), 0.1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profiles := CalculateProfiles(dayPrices(tc.day, duckCurve, 1, 0))
+			if len(profiles) != 1 {
+				t.Fatalf("Expected 1 profile, but got %d", len(profiles))
+			}
+			p := profiles[0]
+			if !p.Date.Equal(tc.day) {
+				t.Errorf("Expected date %s, but got %s", tc.day, p.Date)
+			}
+			if len(p.Prices) != 24 || len(p.Normalised) != 24 {
+				t.Fatalf("Expected 24 prices, but got %d and %d", len(p.Prices), len(p.Normalised))
+			}
+			if p.Prices[2] != tc.expected {
+				t.Errorf("Expected %f at 2 AM, but got %f", tc.expected, p.Prices[2])
+			}
+			if mean(p.Normalised) > 1e-9 {
+				t.Errorf("Expected the normalised prices to have a mean of zero, but got %f", mean(p.Normalised))
+			}
+		})
+	}
+}
+
+func TestCalculateProfilesOrder(t *testing.T) {
+	first := time.Date(2024, 5, 14, 0, 0, 0, 0, date.Location)
+	second := first.AddDate(0, 0, 1)
+	prices := append(dayPrices(second, duckCurve, 1, 0), dayPrices(first, eveningPeak, 1, 0)...)
+
+	profiles := CalculateProfiles(prices)
+	if len(profiles) != 2 || !profiles[0].Date.Equal(first) || !profiles[1].Date.Equal(second) {
+		t.Errorf("Expected the profiles in date order, but got %v", profiles)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	day := time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location)
+	duck := CalculateProfiles(dayPrices(day, duckCurve, 1, 0))[0]
+	scaledDuck := CalculateProfiles(dayPrices(day, duckCurve, 2, 0.05))[0]
+	shiftedDuck := CalculateProfiles(dayPrices(day, shiftedDuckCurve, 1, 0))[0]
+	evening := CalculateProfiles(dayPrices(day, eveningPeak, 1, 0))[0]
+	flat := CalculateProfiles(dayPrices(day, func(int) float64 { return 0.1 }, 1, 0))[0]
+
+	testCases := []struct {
+		name     string
+		a        DayProfile
+		b        DayProfile
+		metric   Metric
+		expected func(float64) bool
+	}{
+		{"Same shape at a different level", duck, scaledDuck, Euclidean, func(d float64) bool { return d < 1e-9 }},
+		{"Same shape with DTW", duck, scaledDuck, DTW, func(d float64) bool { return d < 1e-9 }},
+		{"Different shapes", duck, evening, Euclidean, func(d float64) bool { return d > 0.5 }},
+		{"Flat day", flat, flat, Euclidean, func(d float64) bool { return d == 0 }},
+		{"Flat and shaped day", flat, duck, Euclidean, func(d float64) bool { return math.Abs(d-1) < 1e-9 }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := Distance(tc.a.Normalised, tc.b.Normalised, tc.metric)
+			if !tc.expected(d) {
+				t.Errorf("Unexpected distance %f", d)
+			}
+		})
+	}
+
+	// DTW tolerates a peak shifted by an hour
+	euclidean := Distance(duck.Normalised, shiftedDuck.Normalised, Euclidean)
+	dtw := Distance(duck.Normalised, shiftedDuck.Normalised, DTW)
+	if dtw >= euclidean {
+		t.Errorf("Expected the DTW distance %f to be less than the Euclidean distance %f for a shifted day", dtw, euclidean)
+	}
+}
+
+func TestFindSimilarDays(t *testing.T) {
+	// Wednesday 15th of May 2024
+	target := CalculateProfiles(dayPrices(time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location), duckCurve, 1, 0))[0]
+
+	var prices []price.Price
+	prices = append(prices, dayPrices(time.Date(2024, 5, 14, 0, 0, 0, 0, date.Location), duckCurve, 1.5, 0)...)      // Spring working day
+	prices = append(prices, dayPrices(time.Date(2024, 5, 12, 0, 0, 0, 0, date.Location), shiftedDuckCurve, 1, 0)...) // Spring Sunday
+	prices = append(prices, dayPrices(time.Date(2024, 5, 13, 0, 0, 0, 0, date.Location), eveningPeak, 1, 0)...)      // Spring working day
+	prices = append(prices, dayPrices(time.Date(2024, 1, 10, 0, 0, 0, 0, date.Location), shallowDuckCurve, 1, 0)...) // Winter working day
+	prices = append(prices, dayPrices(time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location), eveningPeak, 1, 0)...)      // The target day itself
+	candidates := CalculateProfiles(prices)
+
+	testCases := []struct {
+		name     string
+		opts     SearchOptions
+		expected []string
+	}{
+		{"All days", SearchOptions{}, []string{"2024-05-14", "2024-01-10", "2024-05-13", "2024-05-12"}},
+		{"DTW matches the shifted day", SearchOptions{Metric: DTW}, []string{"2024-05-14", "2024-01-10", "2024-05-12", "2024-05-13"}},
+		{"Limit", SearchOptions{Limit: 2}, []string{"2024-05-14", "2024-01-10"}},
+		{"Same season", SearchOptions{SameSeason: true}, []string{"2024-05-14", "2024-05-13", "2024-05-12"}},
+		{"Same day type", SearchOptions{SameDayType: true}, []string{"2024-05-14", "2024-01-10", "2024-05-13"}},
+		{"Same season and day type", SearchOptions{SameSeason: true, SameDayType: true}, []string{"2024-05-14", "2024-05-13"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			matches := FindSimilarDays(target, candidates, tc.opts)
+			var dates []string
+			for _, m := range matches {
+				dates = append(dates, m.Date)
+			}
+			if len(dates) != len(tc.expected) {
+				t.Fatalf("Expected %v, but got %v", tc.expected, dates)
+			}
+			for i := range dates {
+				if dates[i] != tc.expected[i] {
+					t.Errorf("Expected %v, but got %v", tc.expected, dates)
+					break
+				}
+			}
+		})
+	}
+}
+
+func doublePeak(hour int) float64 {
+	if (hour >= 7 && hour <= 9) || (hour >= 19 && hour <= 21) {
+		return 0.2
+	}
+	return 0.1
+}
+
+func TestClusterDays(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, date.Location)
+	shapes := []struct {
+		shape    func(int) float64
+		scale    float64
+		expected Shape
+	}{
+		{duckCurve, 1, DuckCurve},
+		{eveningPeak, 1, EveningPeak},
+		{duckCurve, 1.5, DuckCurve},
+		{func(hour int) float64 { return 0.1 + 0.001*float64(hour%2) }, 1, Flat},
+		{doublePeak, 1, DoublePeak},
+		{eveningPeak, 0.8, EveningPeak},
+		{shiftedDuckCurve, 1, DuckCurve},
+		{doublePeak, 1.2, DoublePeak},
+	}
+
+	var prices []price.Price
+	for i, s := range shapes {
+		prices = append(prices, dayPrices(start.AddDate(0, 0, i), s.shape, s.scale, 0)...)
+	}
+
+	clusters, labels := ClusterDays(CalculateProfiles(prices))
+
+	expectedClusters := map[Shape]int{Flat: 1, DuckCurve: 3, EveningPeak: 2, DoublePeak: 2}
+	if len(clusters) != len(expectedClusters) {
+		t.Fatalf("Expected %d clusters, but got %v", len(expectedClusters), clusters)
+	}
+	for _, c := range clusters {
+		if c.Days != expectedClusters[c.Shape] {
+			t.Errorf("Expected %d %s days, but got %d", expectedClusters[c.Shape], c.Shape, c.Days)
+		}
+		if len(c.Centroid) != 24 || len(c.Prices) != 24 {
+			t.Errorf("Expected 24 hours in the %s cluster", c.Shape)
+		}
+	}
+
+	if len(labels) != len(shapes) {
+		t.Fatalf("Expected %d labels, but got %d", len(shapes), len(labels))
+	}
+	for i, s := range shapes {
+		expectedDate := date.ParseToLocalDay(start.AddDate(0, 0, i))
+		if labels[i].Date != expectedDate || labels[i].Shape != s.expected {
+			t.Errorf("Expected %s to be %s, but got %s %s", expectedDate, s.expected, labels[i].Date, labels[i].Shape)
+		}
+	}
+}
+
+func TestClusterDaysEmpty(t *testing.T) {
+	clusters, labels := ClusterDays([]DayProfile{})
+	if len(clusters) != 0 || len(labels) != 0 {
+		t.Errorf("Expected no clusters or labels, but got %v and %v", clusters, labels)
+	}
+}
+
+func TestSimilarDaysWithOutput(t *testing.T) {
+	similar := SimilarDays{
+		Date:    "2024-05-15",
+		Average: 0.1,
+		Prices:  []float64{0.1, 0.2},
+		Matches: []Match{{Date: "2024-05-08", Distance: 0.5, Average: 0.05, Prices: []float64{0.05}}},
+	}
+
+	result, err := similarDaysWithOutput(similar, price.Output{Unit: price.EurPerMwh})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if math.Abs(result.Average-100) > 1e-9 || math.Abs(result.Prices[1]-200) > 1e-9 {
+		t.Errorf("Expected the day in €/MWh, but got %+v", result)
+	}
+	if m := result.Matches[0]; math.Abs(m.Average-50) > 1e-9 || math.Abs(m.Prices[0]-50) > 1e-9 || m.Distance != 0.5 {
+		t.Errorf("Expected the match prices in €/MWh but not its distance, but got %+v", m)
+	}
+	if similar.Prices[1] != 0.2 || similar.Matches[0].Prices[0] != 0.05 {
+		t.Errorf("Expected the original days to be unchanged")
+	}
+}
@@ -0,0 +1,115 @@
+package profile
+
+import (
+	"electricity-prices/pkg/price"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDayNotFound   = errors.New("no prices found for the day")
+	ErrLabelNotFound = errors.New("no shape label found for the day")
+)
+
+type Metric string
+
+const (
+	// Euclidean compares the normalised prices hour by hour
+	Euclidean Metric = "euclidean"
+	// DTW compares the normalised prices with dynamic time warping, so a peak shifted by an hour or two still matches
+	DTW Metric = "dtw"
+)
+
+// Metrics are the available distance metrics.
+var Metrics = []Metric{Euclidean, DTW}
+
+// ParseMetric returns the metric with the given name.
+func ParseMetric(name string) (Metric, bool) {
+	for _, m := range Metrics {
+		if string(m) == name {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+// DayProfile is the shape of a day's prices, with one price for each local hour of the day.
+// The normalised prices have a mean of zero and a standard deviation of one so days with different price levels can be compared.
+type DayProfile struct {
+	Date       time.Time
+	Prices     []float64
+	Normalised []float64
+	Average    float64
+}
+
+// SearchOptions restricts the similar-day search.
+// Zero values compare with Euclidean distance against the last year of prices and return five days.
+type SearchOptions struct {
+	Metric      Metric
+	SameSeason  bool
+	SameDayType bool
+	Days        int
+	Limit       int
+}
+
+// Match is a historical day with a price shape similar to the searched day.
+type Match struct {
+	Date       string       `json:"date"`
+	Distance   float64      `json:"distance"`
+	Season     price.Season `json:"season"`
+	WorkingDay bool         `json:"workingDay"`
+	Average    float64      `json:"average"`
+	Prices     []float64    `json:"prices"`
+}
+
+// SimilarDays are the historical days most similar to a day, closest first.
+type SimilarDays struct {
+	Date       string       `json:"date"`
+	Metric     Metric       `json:"metric"`
+	Season     price.Season `json:"season"`
+	WorkingDay bool         `json:"workingDay"`
+	Average    float64      `json:"average"`
+	Prices     []float64    `json:"prices"`
+	Matches    []Match      `json:"matches"`
+}
+
+type Shape string
+
+const (
+	// Flat days have little difference between the cheapest and most expensive hours
+	Flat Shape = "FLAT"
+	// DuckCurve days are cheapest in the middle of the day when solar generation is high, rising steeply into the evening
+	DuckCurve Shape = "SOLAR_DUCK_CURVE"
+	// EveningPeak days are steady until a peak in the evening
+	EveningPeak Shape = "EVENING_PEAK"
+	// DoublePeak days have a peak in the morning and another in the evening
+	DoublePeak Shape = "DOUBLE_PEAK"
+)
+
+// Shapes are the clusters days are grouped into.
+var Shapes = []Shape{Flat, DuckCurve, EveningPeak, DoublePeak}
+
+// Cluster is a group of days with a similar price shape.
+// The centroid is the average normalised shape of the days and the prices are their average price at each hour.
+type Cluster struct {
+	Shape    Shape     `json:"shape"`
+	Days     int       `json:"days"`
+	Centroid []float64 `json:"centroid"`
+	Prices   []float64 `json:"prices"`
+}
+
+type ClusterReport struct {
+	Start    string    `json:"start"`
+	End      string    `json:"end"`
+	Clusters []Cluster `json:"clusters"`
+}
+
+// DayLabel is the shape cluster a day belongs to, with the distance of the day from the cluster's centroid.
+type DayLabel struct {
+	ID        string    `bson:"_id,omitempty" json:"-"`
+	Date      string    `bson:"date" json:"date"`
+	Shape     Shape     `bson:"shape" json:"shape"`
+	Distance  float64   `bson:"distance" json:"distance"`
+	Average   float64   `bson:"average" json:"average"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,121 @@
+package profile
+
+import (
+	"context"
+	"time"
+)
+
+// A mock implementation of Service
+
+type MockProfileService struct {
+	MockGetSimilarDaysResult *[]SimilarDays
+	MockGetSimilarDaysError  *[]error
+	MockGetClustersResult    *[]ClusterReport
+	MockGetClustersError     *[]error
+	MockRecordLabelsResult   *[][]DayLabel
+	MockRecordLabelsError    *[]error
+	MockGetLabelsResult      *[][]DayLabel
+	MockGetLabelsError       *[]error
+	MockGetLabelResult       *[]DayLabel
+	MockGetLabelError        *[]error
+}
+
+func (m *MockProfileService) GetSimilarDays(ctx context.Context, day time.Time, opts SearchOptions) (SimilarDays, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result SimilarDays
+	if len(*m.MockGetSimilarDaysResult) > 0 {
+		result = (*m.MockGetSimilarDaysResult)[0]
+		*m.MockGetSimilarDaysResult = (*m.MockGetSimilarDaysResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetSimilarDaysError) > 0 {
+		err = (*m.MockGetSimilarDaysError)[0]
+		*m.MockGetSimilarDaysError = (*m.MockGetSimilarDaysError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockProfileService) GetClusters(ctx context.Context, start time.Time, end time.Time) (ClusterReport, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result ClusterReport
+	if len(*m.MockGetClustersResult) > 0 {
+		result = (*m.MockGetClustersResult)[0]
+		*m.MockGetClustersResult = (*m.MockGetClustersResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetClustersError) > 0 {
+		err = (*m.MockGetClustersError)[0]
+		*m.MockGetClustersError = (*m.MockGetClustersError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockProfileService) RecordLabels(ctx context.Context, start time.Time, end time.Time) ([]DayLabel, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []DayLabel
+	if len(*m.MockRecordLabelsResult) > 0 {
+		result = (*m.MockRecordLabelsResult)[0]
+		*m.MockRecordLabelsResult = (*m.MockRecordLabelsResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockRecordLabelsError) > 0 {
+		err = (*m.MockRecordLabelsError)[0]
+		*m.MockRecordLabelsError = (*m.MockRecordLabelsError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockProfileService) GetLabels(ctx context.Context, start time.Time, end time.Time) ([]DayLabel, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result []DayLabel
+	if len(*m.MockGetLabelsResult) > 0 {
+		result = (*m.MockGetLabelsResult)[0]
+		*m.MockGetLabelsResult = (*m.MockGetLabelsResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetLabelsError) > 0 {
+		err = (*m.MockGetLabelsError)[0]
+		*m.MockGetLabelsError = (*m.MockGetLabelsError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
+
+func (m *MockProfileService) GetLabel(ctx context.Context, day time.Time) (DayLabel, error) {
+	// Get the first element of the result array and remove it from the array, return nil if the array is empty
+	var result DayLabel
+	if len(*m.MockGetLabelResult) > 0 {
+		result = (*m.MockGetLabelResult)[0]
+		*m.MockGetLabelResult = (*m.MockGetLabelResult)[1:]
+	}
+
+	// Get the first element of the error array and remove it from the array, return nil if the array is empty
+	var err error
+	if len(*m.MockGetLabelError) > 0 {
+		err = (*m.MockGetLabelError)[0]
+		*m.MockGetLabelError = (*m.MockGetLabelError)[1:]
+	} else {
+		err = nil
+	}
+
+	return result, err
+}
@@ -21,10 +21,43 @@ func (c *Client) GetPrices(t time.Time) ([]price.Price, bool, error) {
 	// Parse date to day string
 	day := t.Format("2006-01-02")
 
+	res, synced, err := fetch(c.Http, fmt.Sprintf(urlTemplate, day, day), t)
+	if err != nil || synced {
+		return nil, synced, err
+	}
+
+	var included ReeIncluded
+	for _, inc := range res.Included {
+		if inc.ID == "1001" {
+			included = inc
+			continue
+		}
+	}
+
+	if len(included.Attributes.Values) == 0 {
+		return nil, false, fmt.Errorf("failed to parse response for date %s", day)
+	}
+
+	prices := make([]price.Price, len(included.Attributes.Values))
+
+	for i, p := range included.Attributes.Values {
+		prices[i] = price.Price{
+			DateTime: p.DateTime,
+			Price:    p.Price / 1000,
+		}
+	}
+
+	return prices, false, nil
+}
+
+// fetch
+// Call the REE API and parse the response.
+// Returns synced as true when there is no data yet for a date in the future.
+func fetch(client web.HTTPClient, url string, t time.Time) (ReeResponse, bool, error) {
 	// Call to endpoint
-	resp, err := c.Http.Get(fmt.Sprintf(urlTemplate, day, day))
+	resp, err := client.Get(url)
 	if err != nil {
-		return nil, false, err
+		return ReeResponse{}, false, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -36,7 +69,7 @@ func (c *Client) GetPrices(t time.Time) ([]price.Price, bool, error) {
 	// Read the response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, false, err
+		return ReeResponse{}, false, err
 	}
 
 	// Check if the status code indicates success
@@ -48,36 +81,15 @@ func (c *Client) GetPrices(t time.Time) ([]price.Price, bool, error) {
 		// Parse the JSON response body into the response struct
 		err = json.Unmarshal(body, &res)
 		if err != nil {
-			return nil, false, err
-		}
-
-		var included ReeIncluded
-		for _, inc := range res.Included {
-			if inc.ID == "1001" {
-				included = inc
-				continue
-			}
-		}
-
-		if len(included.Attributes.Values) == 0 {
-			return nil, false, fmt.Errorf("failed to parse response for date %s", day)
+			return ReeResponse{}, false, err
 		}
 
-		prices := make([]price.Price, len(included.Attributes.Values))
-
-		for i, p := range included.Attributes.Values {
-			prices[i] = price.Price{
-				DateTime: p.DateTime,
-				Price:    p.Price / 1000,
-			}
-		}
-
-		return prices, false, nil
+		return res, false, nil
 
 	} else if resp.StatusCode == 404 {
 		// If the date is in the future, return synced as true
 		if t.After(time.Now()) {
-			return nil, true, nil
+			return ReeResponse{}, true, nil
 		}
 	} else if resp.StatusCode == 502 {
 		// Initialize the error response object
@@ -92,11 +104,10 @@ func (c *Client) GetPrices(t time.Time) ([]price.Price, bool, error) {
 		if len(res.Errors) > 0 && res.Errors[0].Detail == "There are no data for the selected filters." {
 			// If the date is in the future, return synced as true
 			if t.After(time.Now()) {
-				return nil, true, nil
+				return ReeResponse{}, true, nil
 			}
 		}
 	}
 
-	return nil, false, fmt.Errorf("server responded with a non-successful status code: %d", resp.StatusCode)
-
+	return ReeResponse{}, false, fmt.Errorf("server responded with a non-successful status code: %d", resp.StatusCode)
 }
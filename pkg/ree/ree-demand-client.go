@@ -0,0 +1,85 @@
+package ree
+
+import (
+	"electricity-prices/pkg/demand"
+	"electricity-prices/pkg/web"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	demandUrlTemplate = "https://apidatos.ree.es/en/datos/demanda/demanda-tiempo-real?time_trunc=hour&geo_trunc=electric_system&geo_limit=peninsular&geo_ids=8741&start_date=%sT00:00&end_date=%sT23:59"
+
+	realDemandTitle      = "Real demand"
+	forecastDemandTitle  = "Forecast demand"
+	scheduledDemandTitle = "Scheduled demand"
+)
+
+// DemandClient gets the real, forecast and scheduled peninsular demand from the REE API.
+type DemandClient struct {
+	Http web.HTTPClient
+}
+
+// GetDemand returns the demand for each hour of the given date.
+// Values published more often than hourly are averaged over the hour.
+func (c *DemandClient) GetDemand(t time.Time) ([]demand.Demand, bool, error) {
+	day := t.Format("2006-01-02")
+
+	res, synced, err := fetch(c.Http, fmt.Sprintf(demandUrlTemplate, day, day), t)
+	if err != nil || synced {
+		return nil, synced, err
+	}
+
+	byHour := make(map[int64]*demand.Demand)
+	for _, inc := range res.Included {
+		var set func(d *demand.Demand, v float64)
+		switch inc.Attributes.Title {
+		case realDemandTitle:
+			set = func(d *demand.Demand, v float64) { d.Real = &v }
+		case forecastDemandTitle:
+			set = func(d *demand.Demand, v float64) { d.Forecast = &v }
+		case scheduledDemandTitle:
+			set = func(d *demand.Demand, v float64) { d.Scheduled = &v }
+		default:
+			continue
+		}
+
+		for hour, v := range averageByHour(inc.Attributes.Values) {
+			d, ok := byHour[hour.Unix()]
+			if !ok {
+				d = &demand.Demand{DateTime: hour}
+				byHour[hour.Unix()] = d
+			}
+			set(d, v)
+		}
+	}
+
+	if len(byHour) == 0 {
+		return nil, false, fmt.Errorf("failed to parse demand response for date %s", day)
+	}
+
+	result := make([]demand.Demand, 0, len(byHour))
+	for _, d := range byHour {
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].DateTime.Before(result[j].DateTime) })
+	return result, false, nil
+}
+
+// averageByHour
+// Average the values within each hour.
+func averageByHour(values []ReePrices) map[time.Time]float64 {
+	sums := make(map[time.Time]float64)
+	counts := make(map[time.Time]int)
+	for _, v := range values {
+		hour := v.DateTime.Truncate(time.Hour)
+		sums[hour] += v.Price
+		counts[hour]++
+	}
+	result := make(map[time.Time]float64, len(sums))
+	for hour, sum := range sums {
+		result[hour] = sum / float64(counts[hour])
+	}
+	return result
+}
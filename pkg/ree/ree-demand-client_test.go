@@ -0,0 +1,105 @@
+package ree
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/testutils"
+	"electricity-prices/pkg/web/testdata"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetDemand(t *testing.T) {
+	tests := []struct {
+		name               string
+		testDate           time.Time
+		mockResponse       *http.Response
+		mockError          error
+		expectedResultSize int
+		expectSynced       bool
+		expectingError     bool
+	}{
+		{
+			name:               "Valid response",
+			testDate:           time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse:       &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(testutils.ReadJsonStringFromFile("testdata/demand-2024-05-15.json"))},
+			expectedResultSize: 24,
+		},
+		{
+			name:     "No values for specified archive - in future",
+			testDate: time.Now().AddDate(0, 0, 2),
+			mockResponse: &http.Response{StatusCode: 502, Body: testdata.NewMockReadCloser(
+				testutils.ReadJsonStringFromFile("testdata/no-data.json"))},
+			expectSynced: true,
+		},
+		{
+			name:           "Price data returned",
+			testDate:       time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(testutils.ReadJsonStringFromFile("testdata/valid-2023-11-29.json"))},
+			expectingError: true,
+		},
+		{
+			name:           "500 error",
+			testDate:       time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 500, Body: testdata.NewMockReadCloser("")},
+			expectingError: true,
+		},
+		{
+			name:           "Error calling to API",
+			testDate:       time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockError:      errors.New("mock error"),
+			expectingError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			client := DemandClient{Http: &testdata.MockHTTPClient{
+				MockResp: test.mockResponse,
+				MockErr:  test.mockError,
+			}}
+
+			demand, synced, err := client.GetDemand(test.testDate)
+
+			if test.expectingError {
+				if err == nil {
+					t.Errorf("Expected error but got nil")
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error but got %s", err)
+			}
+
+			if synced != test.expectSynced {
+				t.Errorf("Expected synced to be %t but got %t", test.expectSynced, synced)
+			}
+
+			if len(demand) != test.expectedResultSize {
+				t.Fatalf("Expected %d hours but got %d", test.expectedResultSize, len(demand))
+			}
+
+			if test.expectedResultSize == 0 {
+				return
+			}
+
+			// The real demand is averaged over the 10 minute values
+			first := demand[0]
+			if !first.DateTime.Equal(test.testDate) {
+				t.Errorf("Expected the first hour to be %s but got %s", test.testDate, first.DateTime)
+			}
+			if first.Real == nil || *first.Real != 22000 {
+				t.Errorf("Expected a real demand of 22000 MW but got %v", first.Real)
+			}
+			if first.Forecast == nil || *first.Forecast != 22100 || first.Scheduled == nil || *first.Scheduled != 21900 {
+				t.Errorf("Expected a forecast of 22100 MW and a schedule of 21900 MW but got %v and %v", first.Forecast, first.Scheduled)
+			}
+
+			// The hours that have not passed only have the forecast and schedule
+			last := demand[len(demand)-1]
+			if last.Real != nil || last.Forecast == nil || last.Scheduled == nil {
+				t.Errorf("Expected the last hour to only have the forecast and schedule but got %+v", last)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package ree
+
+import (
+	"electricity-prices/pkg/generation"
+	"electricity-prices/pkg/web"
+	"fmt"
+	"time"
+)
+
+const (
+	generationUrlTemplate = "https://apidatos.ree.es/en/datos/generacion/estructura-generacion?time_trunc=hour&geo_trunc=electric_system&geo_limit=peninsular&geo_ids=8741&start_date=%sT00:00&end_date=%sT23:59"
+	emissionsUrlTemplate  = "https://apidatos.ree.es/en/datos/generacion/no-renovables-detalle-emisiones-CO2?time_trunc=hour&geo_trunc=electric_system&geo_limit=peninsular&geo_ids=8741&start_date=%sT00:00&end_date=%sT23:59"
+
+	renewableType    = "Renewable"
+	nonRenewableType = "Non-renewable"
+
+	totalEmissionsTitle = "Total tCO2 eq."
+	emissionsRateTitle  = "tCO2 eq./MWh"
+)
+
+// GenerationClient gets the hourly generation by technology and the CO2 emissions of the peninsular system from the REE API.
+type GenerationClient struct {
+	Http web.HTTPClient
+}
+
+// GetGeneration returns the generation by technology for each hour of the given date.
+// The current day only has the hours published so far.
+func (c *GenerationClient) GetGeneration(t time.Time) ([]generation.Generation, bool, error) {
+	day := t.Format("2006-01-02")
+
+	res, synced, err := fetch(c.Http, fmt.Sprintf(generationUrlTemplate, day, day), t)
+	if err != nil || synced {
+		return nil, synced, err
+	}
+
+	byHour := make(map[int64]*generation.Generation)
+	var hours []int64
+	for _, inc := range res.Included {
+		// Skip the total, which is the sum of the technologies
+		if inc.Attributes.Type != renewableType && inc.Attributes.Type != nonRenewableType {
+			continue
+		}
+		for _, v := range inc.Attributes.Values {
+			key := v.DateTime.Unix()
+			g, ok := byHour[key]
+			if !ok {
+				g = &generation.Generation{DateTime: v.DateTime, Technologies: make(map[string]float64)}
+				byHour[key] = g
+				hours = append(hours, key)
+			}
+			g.Technologies[inc.Attributes.Title] += v.Price
+			if inc.Attributes.Type == renewableType {
+				g.Renewable += v.Price
+			} else {
+				g.NonRenewable += v.Price
+			}
+		}
+	}
+
+	if len(hours) == 0 {
+		return nil, false, fmt.Errorf("failed to parse generation response for date %s", day)
+	}
+
+	result := make([]generation.Generation, len(hours))
+	for i, key := range hours {
+		result[i] = *byHour[key]
+	}
+	return result, false, nil
+}
+
+// GetEmissions returns the tonnes of CO2 equivalent emitted in each hour of the given date.
+// The total is used when it is published, otherwise the technologies are added up.
+func (c *GenerationClient) GetEmissions(t time.Time) ([]generation.Emission, bool, error) {
+	day := t.Format("2006-01-02")
+
+	res, synced, err := fetch(c.Http, fmt.Sprintf(emissionsUrlTemplate, day, day), t)
+	if err != nil || synced {
+		return nil, synced, err
+	}
+
+	var total *ReeIncluded
+	for i, inc := range res.Included {
+		if inc.Attributes.Title == totalEmissionsTitle {
+			total = &res.Included[i]
+		}
+	}
+
+	byHour := make(map[int64]*generation.Emission)
+	var hours []int64
+	add := func(values []ReePrices) {
+		for _, v := range values {
+			key := v.DateTime.Unix()
+			e, ok := byHour[key]
+			if !ok {
+				e = &generation.Emission{DateTime: v.DateTime}
+				byHour[key] = e
+				hours = append(hours, key)
+			}
+			e.Emissions += v.Price
+		}
+	}
+
+	if total != nil {
+		add(total.Attributes.Values)
+	} else {
+		for _, inc := range res.Included {
+			if inc.Attributes.Title != emissionsRateTitle {
+				add(inc.Attributes.Values)
+			}
+		}
+	}
+
+	if len(hours) == 0 {
+		return nil, false, fmt.Errorf("failed to parse emissions response for date %s", day)
+	}
+
+	result := make([]generation.Emission, len(hours))
+	for i, key := range hours {
+		result[i] = *byHour[key]
+	}
+	return result, false, nil
+}
@@ -0,0 +1,180 @@
+package ree
+
+import (
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/testutils"
+	"electricity-prices/pkg/web/testdata"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetGeneration(t *testing.T) {
+	tests := []struct {
+		name                 string
+		testDate             time.Time
+		mockResponse         *http.Response
+		mockError            error
+		expectedResultSize   int
+		expectedRenewable    float64
+		expectedNonRenewable float64
+		expectSynced         bool
+		expectingError       bool
+	}{
+		{
+			name:                 "Valid response",
+			testDate:             time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse:         &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(testutils.ReadJsonStringFromFile("testdata/generation-2024-05-15.json"))},
+			expectedResultSize:   24,
+			expectedRenewable:    8000,
+			expectedNonRenewable: 10000,
+		},
+		{
+			name:     "No values for specified archive - in future",
+			testDate: time.Now().AddDate(0, 0, 1),
+			mockResponse: &http.Response{StatusCode: 502, Body: testdata.NewMockReadCloser(
+				testutils.ReadJsonStringFromFile("testdata/no-data.json"))},
+			expectSynced: true,
+		},
+		{
+			name:           "Price data returned",
+			testDate:       time.Date(2023, 11, 29, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(testutils.ReadJsonStringFromFile("testdata/valid-2023-11-29.json"))},
+			expectingError: true,
+		},
+		{
+			name:           "500 error",
+			testDate:       time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 500, Body: testdata.NewMockReadCloser("")},
+			expectingError: true,
+		},
+		{
+			name:           "Error calling to API",
+			testDate:       time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockError:      errors.New("mock error"),
+			expectingError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			client := GenerationClient{Http: &testdata.MockHTTPClient{
+				MockResp: test.mockResponse,
+				MockErr:  test.mockError,
+			}}
+
+			generation, synced, err := client.GetGeneration(test.testDate)
+
+			if test.expectingError {
+				if err == nil {
+					t.Errorf("Expected error but got nil")
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error but got %s", err)
+			}
+
+			if synced != test.expectSynced {
+				t.Errorf("Expected synced to be %t but got %t", test.expectSynced, synced)
+			}
+
+			if len(generation) != test.expectedResultSize {
+				t.Fatalf("Expected %d hours but got %d", test.expectedResultSize, len(generation))
+			}
+
+			if test.expectedResultSize > 0 {
+				first := generation[0]
+				if !first.DateTime.Equal(test.testDate) {
+					t.Errorf("Expected the first hour to be %s but got %s", test.testDate, first.DateTime)
+				}
+				if first.Renewable != test.expectedRenewable || first.NonRenewable != test.expectedNonRenewable {
+					t.Errorf("Expected %f renewable and %f non-renewable MWh but got %f and %f", test.expectedRenewable, test.expectedNonRenewable, first.Renewable, first.NonRenewable)
+				}
+				if first.Technologies["Nuclear"] != 6000 || len(first.Technologies) != 5 {
+					t.Errorf("Expected the generation of the 5 technologies but got %v", first.Technologies)
+				}
+			}
+		})
+	}
+}
+
+func TestGetEmissions(t *testing.T) {
+	tests := []struct {
+		name               string
+		testDate           time.Time
+		mockResponse       *http.Response
+		mockError          error
+		expectedResultSize int
+		expectedEmissions  float64
+		expectSynced       bool
+		expectingError     bool
+	}{
+		{
+			name:               "Valid response",
+			testDate:           time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse:       &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(testutils.ReadJsonStringFromFile("testdata/emissions-2024-05-15.json"))},
+			expectedResultSize: 24,
+			expectedEmissions:  1630,
+		},
+		{
+			name:               "Valid response without the total",
+			testDate:           time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse:       &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser(testutils.ReadJsonStringFromFile("testdata/emissions-no-total-2024-05-15.json"))},
+			expectedResultSize: 24,
+			expectedEmissions:  1630,
+		},
+		{
+			name:     "404 error - in future",
+			testDate: time.Now().AddDate(0, 0, 2),
+			mockResponse: &http.Response{StatusCode: 404, Body: testdata.NewMockReadCloser(
+				"not found")},
+			expectSynced: true,
+		},
+		{
+			name:           "Invalid data returned - not json",
+			testDate:       time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockResponse:   &http.Response{StatusCode: 200, Body: testdata.NewMockReadCloser("not json$")},
+			expectingError: true,
+		},
+		{
+			name:           "Error calling to API",
+			testDate:       time.Date(2024, 5, 15, 0, 0, 0, 0, date.Location),
+			mockError:      errors.New("mock error"),
+			expectingError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			client := GenerationClient{Http: &testdata.MockHTTPClient{
+				MockResp: test.mockResponse,
+				MockErr:  test.mockError,
+			}}
+
+			emissions, synced, err := client.GetEmissions(test.testDate)
+
+			if test.expectingError {
+				if err == nil {
+					t.Errorf("Expected error but got nil")
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error but got %s", err)
+			}
+
+			if synced != test.expectSynced {
+				t.Errorf("Expected synced to be %t but got %t", test.expectSynced, synced)
+			}
+
+			if len(emissions) != test.expectedResultSize {
+				t.Fatalf("Expected %d hours but got %d", test.expectedResultSize, len(emissions))
+			}
+
+			if test.expectedResultSize > 0 && math.Abs(emissions[0].Emissions-test.expectedEmissions) > 1e-9 {
+				t.Errorf("Expected %f tonnes but got %f", test.expectedEmissions, emissions[0].Emissions)
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ type ReePrices struct {
 	DateTime time.Time `json:"datetime"`
 }
 type ReeAttributes struct {
+	Title  string      `json:"title"`
+	Type   string      `json:"type"`
 	Values []ReePrices `json:"values"`
 }
 
@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/demand"
+	"log"
+	"time"
+)
+
+// demandBackfillDays is how far back the demand is synced when none is stored.
+const demandBackfillDays = 30
+
+// demandResyncDays is how many days before the latest stored day are synced again.
+// The latest days are stored with the forecast ahead of time, and the real demand is filled in as the hours pass.
+const demandResyncDays = 2
+
+// DemandSyncer syncs the hourly real, forecast and scheduled demand.
+type DemandSyncer struct {
+	DemandService demand.Service
+	Client        demand.Client
+}
+
+// Sync syncs the demand from the API to the database up to the end date.
+// It returns the number of hours saved.
+func (s *DemandSyncer) Sync(ctx context.Context, end time.Time) (int, error) {
+	latest, notFound, err := s.DemandService.GetLatestDemand(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	currentDate := date.StartOfDay(latest.DateTime).AddDate(0, 0, -demandResyncDays)
+	if notFound {
+		currentDate = date.StartOfDay(end).AddDate(0, 0, -demandBackfillDays)
+	}
+
+	saved := 0
+	for !currentDate.After(date.StartOfDay(end)) {
+		d, synced, err := s.Client.GetDemand(currentDate)
+		if err != nil {
+			return saved, err
+		}
+		if synced || len(d) == 0 {
+			break
+		}
+
+		log.Printf("Syncing demand for %s", currentDate.Format("January 2 2006"))
+		err = s.DemandService.SaveDemand(ctx, d)
+		if err != nil {
+			return saved, err
+		}
+		saved += len(d)
+		currentDate = currentDate.AddDate(0, 0, 1)
+	}
+
+	return saved, nil
+}
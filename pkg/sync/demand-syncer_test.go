@@ -0,0 +1,114 @@
+package sync
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/demand"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDemandSync(t *testing.T) {
+	end := time.Date(2024, 5, 16, 0, 0, 0, 0, date.Location)
+	tomorrow := date.StartOfDay(end)
+	today := tomorrow.AddDate(0, 0, -1)
+	yesterday := today.AddDate(0, 0, -1)
+	hour := func(t time.Time) []demand.Demand {
+		forecast := 25000.0
+		return []demand.Demand{{DateTime: t, Forecast: &forecast}}
+	}
+
+	tests := []struct {
+		name         string
+		latest       demand.Demand
+		notFound     bool
+		latestErr    error
+		demand       [][]demand.Demand
+		synced       []bool
+		demandErr    []error
+		saveErr      []error
+		expectedDays []time.Time
+		expectError  bool
+	}{
+		{
+			name:         "Syncs the days before the latest again",
+			latest:       demand.Demand{DateTime: tomorrow.Add(23 * time.Hour)},
+			demand:       [][]demand.Demand{hour(yesterday), hour(today), hour(tomorrow)},
+			expectedDays: []time.Time{yesterday, today, tomorrow},
+		},
+		{
+			name:         "Stops when the day is not published",
+			latest:       demand.Demand{DateTime: today.AddDate(0, 0, 1)},
+			demand:       [][]demand.Demand{hour(yesterday), hour(today), nil},
+			synced:       []bool{false, false, true},
+			expectedDays: []time.Time{yesterday, today},
+		},
+		{
+			name:         "Backfills when nothing is stored",
+			notFound:     true,
+			demand:       [][]demand.Demand{hour(tomorrow.AddDate(0, 0, -30)), nil},
+			synced:       []bool{false, true},
+			expectedDays: []time.Time{tomorrow.AddDate(0, 0, -30)},
+		},
+		{
+			name:        "Client error",
+			latest:      demand.Demand{DateTime: tomorrow},
+			demand:      [][]demand.Demand{nil},
+			demandErr:   []error{errors.New("error")},
+			expectError: true,
+		},
+		{
+			name:        "Save error",
+			latest:      demand.Demand{DateTime: tomorrow},
+			demand:      [][]demand.Demand{hour(yesterday)},
+			saveErr:     []error{errors.New("error")},
+			expectError: true,
+		},
+		{
+			name:        "Latest error",
+			latestErr:   errors.New("error"),
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := &demand.MockDemandService{
+				MockGetLatestDemandResult:   &[]demand.Demand{test.latest},
+				MockGetLatestDemandNotFound: &[]bool{test.notFound},
+				MockGetLatestDemandError:    &[]error{test.latestErr},
+				MockSaveDemand:              &[][]demand.Demand{},
+				MockSaveDemandError:         &test.saveErr,
+			}
+			mockClient := &demand.MockDemandClient{
+				MockGetDemandResult: &test.demand,
+				MockGetDemandSynced: &test.synced,
+				MockGetDemandError:  &test.demandErr,
+			}
+			syncer := DemandSyncer{DemandService: mockService, Client: mockClient}
+
+			hours, err := syncer.Sync(context.Background(), end)
+
+			if test.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+
+			saved := *mockService.MockSaveDemand
+			if len(saved) != len(test.expectedDays) || hours != len(test.expectedDays) {
+				t.Fatalf("Expected %d days to be saved, but got %d (%d hours)", len(test.expectedDays), len(saved), hours)
+			}
+			for i, d := range test.expectedDays {
+				if !saved[i][0].DateTime.Equal(d) {
+					t.Errorf("Expected %s to be saved, but got %s", d, saved[i][0].DateTime)
+				}
+			}
+		})
+	}
+}
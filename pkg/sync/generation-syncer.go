@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/generation"
+	"log"
+	"time"
+)
+
+// generationBackfillDays is how far back the generation is synced when none is stored.
+const generationBackfillDays = 30
+
+// GenerationSyncer syncs the hourly generation and CO2 emissions.
+// The current day is only partly published, so the latest stored day is always synced again.
+// The emissions are optional, so failing to get them is logged and the generation is saved without them.
+type GenerationSyncer struct {
+	GenerationService generation.Service
+	Client            generation.Client
+}
+
+// Sync syncs the generation from the API to the database up to the end date.
+// It returns the number of hours saved.
+func (s *GenerationSyncer) Sync(ctx context.Context, end time.Time) (int, error) {
+	latest, notFound, err := s.GenerationService.GetLatestGeneration(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	currentDate := date.StartOfDay(latest.DateTime)
+	if notFound {
+		currentDate = date.StartOfDay(end).AddDate(0, 0, -generationBackfillDays)
+	}
+
+	saved := 0
+	for !currentDate.After(date.StartOfDay(end)) {
+		gen, synced, err := s.Client.GetGeneration(currentDate)
+		if err != nil {
+			return saved, err
+		}
+		if synced || len(gen) == 0 {
+			break
+		}
+
+		emissions, _, err := s.Client.GetEmissions(currentDate)
+		if err != nil {
+			log.Printf("Failed to get emissions for %s: %s", currentDate.Format("January 2 2006"), err)
+		} else {
+			gen = generation.MergeEmissions(gen, emissions)
+		}
+
+		log.Printf("Syncing generation for %s", currentDate.Format("January 2 2006"))
+		err = s.GenerationService.SaveGeneration(ctx, gen)
+		if err != nil {
+			return saved, err
+		}
+		saved += len(gen)
+		currentDate = currentDate.AddDate(0, 0, 1)
+	}
+
+	return saved, nil
+}
@@ -0,0 +1,130 @@
+package sync
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/generation"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerationSync(t *testing.T) {
+	end := time.Date(2024, 5, 15, 12, 0, 0, 0, date.Location)
+	today := date.StartOfDay(end)
+	yesterday := today.AddDate(0, 0, -1)
+	hour := func(t time.Time) []generation.Generation {
+		return []generation.Generation{{DateTime: t, Renewable: 500, NonRenewable: 500}}
+	}
+
+	tests := []struct {
+		name              string
+		latest            generation.Generation
+		notFound          bool
+		latestErr         error
+		generation        [][]generation.Generation
+		synced            []bool
+		generationErr     []error
+		emissions         [][]generation.Emission
+		emissionsErr      []error
+		expectedDays      []time.Time
+		expectedEmissions []float64
+		expectError       bool
+	}{
+		{
+			name:              "Syncs the latest day again",
+			latest:            generation.Generation{DateTime: yesterday.Add(20 * time.Hour)},
+			generation:        [][]generation.Generation{hour(yesterday), hour(today)},
+			emissions:         [][]generation.Emission{{{DateTime: yesterday, Emissions: 100}}, {{DateTime: today, Emissions: 80}}},
+			emissionsErr:      []error{nil, nil},
+			expectedDays:      []time.Time{yesterday, today},
+			expectedEmissions: []float64{100, 80},
+		},
+		{
+			name:              "Saves without emissions when they fail",
+			latest:            generation.Generation{DateTime: today},
+			generation:        [][]generation.Generation{hour(today)},
+			emissions:         [][]generation.Emission{nil},
+			emissionsErr:      []error{errors.New("error")},
+			expectedDays:      []time.Time{today},
+			expectedEmissions: []float64{0},
+		},
+		{
+			name:          "Stops when the day is not published",
+			latest:        generation.Generation{DateTime: yesterday},
+			generation:    [][]generation.Generation{hour(yesterday), nil},
+			synced:        []bool{false, true},
+			emissions:     [][]generation.Emission{nil},
+			emissionsErr:  []error{nil},
+			expectedDays:  []time.Time{yesterday},
+			generationErr: []error{nil, nil},
+		},
+		{
+			name:          "Backfills when nothing is stored",
+			notFound:      true,
+			generation:    [][]generation.Generation{hour(today.AddDate(0, 0, -30)), nil},
+			synced:        []bool{false, true},
+			emissions:     [][]generation.Emission{nil},
+			emissionsErr:  []error{nil},
+			expectedDays:  []time.Time{today.AddDate(0, 0, -30)},
+			generationErr: []error{nil, nil},
+		},
+		{
+			name:          "Client error",
+			latest:        generation.Generation{DateTime: today},
+			generation:    [][]generation.Generation{nil},
+			generationErr: []error{errors.New("error")},
+			expectError:   true,
+		},
+		{
+			name:        "Latest error",
+			latestErr:   errors.New("error"),
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := &generation.MockGenerationService{
+				MockGetLatestGenerationResult:   &[]generation.Generation{test.latest},
+				MockGetLatestGenerationNotFound: &[]bool{test.notFound},
+				MockGetLatestGenerationError:    &[]error{test.latestErr},
+				MockSaveGeneration:              &[][]generation.Generation{},
+				MockSaveGenerationError:         &[]error{},
+			}
+			mockClient := &generation.MockGenerationClient{
+				MockGetGenerationResult: &test.generation,
+				MockGetGenerationSynced: &test.synced,
+				MockGetGenerationError:  &test.generationErr,
+				MockGetEmissionsResult:  &test.emissions,
+				MockGetEmissionsError:   &test.emissionsErr,
+			}
+			syncer := GenerationSyncer{GenerationService: mockService, Client: mockClient}
+
+			hours, err := syncer.Sync(context.Background(), end)
+
+			if test.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+
+			saved := *mockService.MockSaveGeneration
+			if len(saved) != len(test.expectedDays) || hours != len(test.expectedDays) {
+				t.Fatalf("Expected %d days to be saved, but got %d (%d hours)", len(test.expectedDays), len(saved), hours)
+			}
+			for i, d := range test.expectedDays {
+				if !saved[i][0].DateTime.Equal(d) {
+					t.Errorf("Expected %s to be saved, but got %s", d, saved[i][0].DateTime)
+				}
+				if test.expectedEmissions != nil && saved[i][0].Emissions != test.expectedEmissions[i] {
+					t.Errorf("Expected %f tonnes, but got %f", test.expectedEmissions[i], saved[i][0].Emissions)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package sync
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"log"
+	"time"
+)
+
+// marketBackfillDays is how far back the intraday markets are synced when none are stored.
+const marketBackfillDays = 30
+
+// MarketSyncer syncs an intraday market price series.
+// The intraday sessions and the continuous market fill in the day as they are held, so the latest stored day is always synced again
+// and the prices are replaced rather than inserted.
+type MarketSyncer struct {
+	PriceService price.Service
+	Client       price.Client
+}
+
+// Sync syncs the prices from the API to the database up to the end date.
+// It returns the number of prices saved.
+func (s *MarketSyncer) Sync(ctx context.Context, end time.Time) (int, error) {
+	latest, notFound, err := s.PriceService.GetLatestPrice(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	currentDate := date.StartOfDay(latest.DateTime)
+	if notFound {
+		currentDate = date.StartOfDay(end).AddDate(0, 0, -marketBackfillDays)
+	}
+
+	saved := 0
+	for !currentDate.After(date.StartOfDay(end)) {
+		prices, synced, err := s.Client.GetPrices(currentDate)
+		if err != nil {
+			return saved, err
+		}
+		if synced || len(prices) == 0 {
+			break
+		}
+
+		log.Printf("Syncing market prices for %s", currentDate.Format("January 2 2006"))
+		err = s.PriceService.ReplacePrices(ctx, prices)
+		if err != nil {
+			return saved, err
+		}
+		saved += len(prices)
+		currentDate = currentDate.AddDate(0, 0, 1)
+	}
+
+	return saved, nil
+}
@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"context"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMarketSync(t *testing.T) {
+	end := time.Date(2024, 5, 16, 0, 0, 0, 0, date.Location)
+	tomorrow := date.StartOfDay(end)
+	today := tomorrow.AddDate(0, 0, -1)
+	sessions := func(t time.Time, n int) []price.Price {
+		var prices []price.Price
+		for session := 1; session <= n; session++ {
+			prices = append(prices, price.Price{DateTime: t, Price: 0.05, Session: session})
+		}
+		return prices
+	}
+
+	tests := []struct {
+		name           string
+		latest         price.Price
+		notFound       bool
+		latestErr      error
+		prices         [][]price.Price
+		synced         []bool
+		pricesErr      []error
+		replaceErr     []error
+		expectedDays   []time.Time
+		expectedPrices int
+		expectError    bool
+	}{
+		{
+			name:           "Syncs the latest day again",
+			latest:         price.Price{DateTime: today.Add(23 * time.Hour), Session: 2},
+			prices:         [][]price.Price{sessions(today, 3), sessions(tomorrow, 2)},
+			expectedDays:   []time.Time{today, tomorrow},
+			expectedPrices: 5,
+		},
+		{
+			name:           "Stops when the day is not published",
+			latest:         price.Price{DateTime: today},
+			prices:         [][]price.Price{sessions(today, 3), nil},
+			synced:         []bool{false, true},
+			expectedDays:   []time.Time{today},
+			expectedPrices: 3,
+		},
+		{
+			name:           "Backfills when nothing is stored",
+			notFound:       true,
+			prices:         [][]price.Price{sessions(tomorrow.AddDate(0, 0, -30), 1), nil},
+			synced:         []bool{false, true},
+			expectedDays:   []time.Time{tomorrow.AddDate(0, 0, -30)},
+			expectedPrices: 1,
+		},
+		{
+			name:        "Client error",
+			latest:      price.Price{DateTime: today},
+			prices:      [][]price.Price{nil},
+			pricesErr:   []error{errors.New("error")},
+			expectError: true,
+		},
+		{
+			name:        "Save error",
+			latest:      price.Price{DateTime: today},
+			prices:      [][]price.Price{sessions(today, 1)},
+			replaceErr:  []error{errors.New("error")},
+			expectError: true,
+		},
+		{
+			name:        "Latest error",
+			latestErr:   errors.New("error"),
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockService := &price.MockPriceService{
+				MockGetLatestPriceResult:   &[]price.Price{test.latest},
+				MockGetLatestPriceNoResult: &[]bool{test.notFound},
+				MockGetLatestPriceError:    &[]error{test.latestErr},
+				MockReplacePrices:          &[][]price.Price{},
+				MockReplacePricesError:     &test.replaceErr,
+			}
+			mockClient := &price.MockPriceClient{
+				MockGetPricesResult: &test.prices,
+				MockGetPricesSynced: &test.synced,
+				MockGetPricesError:  &test.pricesErr,
+			}
+			syncer := MarketSyncer{PriceService: mockService, Client: mockClient}
+
+			saved, err := syncer.Sync(context.Background(), end)
+
+			if test.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+
+			replaced := *mockService.MockReplacePrices
+			if len(replaced) != len(test.expectedDays) || saved != test.expectedPrices {
+				t.Fatalf("Expected %d days and %d prices to be saved, but got %d days and %d prices", len(test.expectedDays), test.expectedPrices, len(replaced), saved)
+			}
+			for i, d := range test.expectedDays {
+				if !replaced[i][0].DateTime.Equal(d) {
+					t.Errorf("Expected %s to be saved, but got %s", d, replaced[i][0].DateTime)
+				}
+			}
+		})
+	}
+}
@@ -9,10 +9,24 @@ import (
 	"time"
 )
 
+// HistoryStart is the first day of prices synced into an empty database.
+var HistoryStart = time.Date(2021, 6, 1, 0, 0, 0, 0, date.Location)
+
+// Syncer syncs a single price series. The SecondaryClient is optional
+// and is used as a backup when the PrimaryClient has no prices.
+// The ComponentsClient is optional and is used to add the price component
+// breakdown to prices from a client that doesn't provide it.
+// The AnomalyDetector is optional and flags unusual prices against the stored
+// history before each day is saved.
+// The Location is optional and is the time zone of the market days the
+// clients return, which defaults to Madrid.
 type Syncer struct {
-	PriceService    price.Service
-	PrimaryClient   price.Client
-	SecondaryClient price.Client
+	PriceService     price.Service
+	PrimaryClient    price.Client
+	SecondaryClient  price.Client
+	ComponentsClient price.Client
+	AnomalyDetector  *price.AnomalyDetector
+	Location         *time.Location
 }
 
 // Sync syncs the prices from the API to the database
@@ -25,19 +39,19 @@ func (s *Syncer) Sync(ctx context.Context, end time.Time) (bool, error) {
 	// Get last day that was synced from database.
 	p, notFound, err := s.PriceService.GetLatestPrice(ctx)
 	if notFound {
-		p = price.Price{DateTime: date.StartOfDay(time.Date(2021, 5, 31, 0, 0, 0, 0, time.Local))}
+		p = price.Price{DateTime: HistoryStart.AddDate(0, 0, -1)}
 	}
 	if err != nil {
 		return false, err
 	}
 
 	log.Println("Last day synced: ", p.DateTime.Format("January 2 2006"))
-	currentDate := date.StartOfDay(p.DateTime).AddDate(0, 0, 1)
+	currentDate := s.startOfDay(p.DateTime).AddDate(0, 0, 1)
 
 	// Keep processing until we reach tomorrow
 	for {
 		// If we reach the end date, exit
-		if currentDate.After(date.StartOfDay(end).Add(time.Hour)) {
+		if currentDate.After(s.startOfDay(end).Add(time.Hour)) {
 			break
 		}
 
@@ -45,7 +59,7 @@ func (s *Syncer) Sync(ctx context.Context, end time.Time) (bool, error) {
 		prices, synced, err := s.PrimaryClient.GetPrices(currentDate)
 
 		// If there is an error or the primary API is synced, try the backup API
-		if err != nil || synced || len(prices) == 0 {
+		if s.SecondaryClient != nil && (err != nil || synced || len(prices) == 0) {
 			err = nil
 			prices, synced, err = s.SecondaryClient.GetPrices(currentDate)
 		}
@@ -63,6 +77,9 @@ func (s *Syncer) Sync(ctx context.Context, end time.Time) (bool, error) {
 			return false, errors.New("no prices for " + currentDate.Format("January 2 2006"))
 		}
 
+		prices = s.addComponents(currentDate, prices)
+		prices = s.detectAnomalies(ctx, currentDate, prices)
+
 		log.Printf("Syncing prices for %s", currentDate.Format("January 2 2006"))
 
 		// Save the prices in the database
@@ -76,3 +93,81 @@ func (s *Syncer) Sync(ctx context.Context, end time.Time) (bool, error) {
 	log.Println("Fully Synced. Exiting...")
 	return true, nil
 }
+
+// addComponents
+// Add the component breakdown to any prices that are missing it.
+// The breakdown is optional so failing to get it is logged rather than returned.
+func (s *Syncer) addComponents(t time.Time, prices []price.Price) []price.Price {
+	if s.ComponentsClient == nil || !missingComponents(prices) {
+		return prices
+	}
+
+	withComponents, _, err := s.ComponentsClient.GetPrices(t)
+	if err != nil {
+		log.Printf("Failed to get price components for %s: %s", t.Format("January 2 2006"), err)
+		return prices
+	}
+
+	return mergeComponents(prices, withComponents)
+}
+
+// detectAnomalies
+// Flag the unusual prices of the day against the stored history.
+// The flags are optional so failing to get the history is logged rather than returned.
+func (s *Syncer) detectAnomalies(ctx context.Context, t time.Time, prices []price.Price) []price.Price {
+	if s.AnomalyDetector == nil {
+		return prices
+	}
+
+	detector := s.AnomalyDetector.WithDefaults()
+	day := s.startOfDay(t)
+	history, err := s.PriceService.GetPrices(ctx, day.AddDate(0, 0, -detector.WindowDays), day.Add(-time.Second))
+	if err != nil {
+		log.Printf("Failed to get the price history to detect anomalies for %s: %s", t.Format("January 2 2006"), err)
+		return prices
+	}
+
+	prices = price.DetectAnomalies(prices, history, detector)
+	if anomalies := price.CollectAnomalies(prices); len(anomalies) > 0 {
+		log.Printf("Flagged %d price anomalies for %s", len(anomalies), t.Format("January 2 2006"))
+	}
+	return prices
+}
+
+// startOfDay
+// Get the start of the market day of t.
+func (s *Syncer) startOfDay(t time.Time) time.Time {
+	if s.Location == nil {
+		return date.StartOfDay(t)
+	}
+	local := t.In(s.Location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.Location)
+}
+
+// missingComponents
+// Returns true if any of the prices are missing the component breakdown.
+func missingComponents(prices []price.Price) bool {
+	for _, p := range prices {
+		if p.Components == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeComponents
+// Copy the component breakdown onto the prices for the same hour.
+func mergeComponents(prices []price.Price, withComponents []price.Price) []price.Price {
+	components := make(map[int64]*price.Components)
+	for _, p := range withComponents {
+		if p.Components != nil {
+			components[p.DateTime.Truncate(time.Hour).Unix()] = p.Components
+		}
+	}
+	for i, p := range prices {
+		if p.Components == nil {
+			prices[i].Components = components[p.DateTime.Truncate(time.Hour).Unix()]
+		}
+	}
+	return prices
+}
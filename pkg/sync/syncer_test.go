@@ -316,3 +316,278 @@ func TestSync(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncWithoutSecondaryClient(t *testing.T) {
+	tests := []struct {
+		name                   string
+		primaryGetPricesResp   *[][]price.Price
+		primaryGetPricesSynced *[]bool
+		primaryGetPricesErr    *[]error
+		savePricesCount        *price.CallCounter
+		expectError            bool
+		expectSynced           bool
+	}{
+		{
+			name: "Primary Client successful",
+			primaryGetPricesResp: &[][]price.Price{
+				{
+					{
+						DateTime: time.Date(2021, 6, 1, 0, 0, 0, 0, time.Local),
+						Price:    1.0,
+					},
+				},
+			},
+			primaryGetPricesSynced: &[]bool{false, true},
+			primaryGetPricesErr:    &[]error{nil},
+			savePricesCount:        &price.CallCounter{Count: 1},
+			expectError:            false,
+			expectSynced:           true,
+		},
+		{
+			name:                   "Primary Client fails",
+			primaryGetPricesResp:   &[][]price.Price{},
+			primaryGetPricesSynced: &[]bool{false},
+			primaryGetPricesErr:    &[]error{fmt.Errorf("error")},
+			savePricesCount:        &price.CallCounter{Count: 0},
+			expectError:            true,
+			expectSynced:           false,
+		},
+	}
+	for _, test := range tests {
+		mockPriceService := &price.MockPriceService{
+			MockGetLatestPriceResult:   &[]price.Price{{DateTime: time.Date(2021, 5, 31, 0, 0, 0, 0, time.Local)}},
+			MockGetLatestPriceNoResult: &[]bool{false},
+			MockGetLatestPriceError:    &[]error{nil},
+			MockSavePricesCount:        test.savePricesCount,
+			MockSavePricesError:        &[]error{nil},
+		}
+		mockPrimaryClient := &price.MockPriceClient{
+			MockGetPricesResult: test.primaryGetPricesResp,
+			MockGetPricesSynced: test.primaryGetPricesSynced,
+			MockGetPricesError:  test.primaryGetPricesErr,
+		}
+
+		syncer := Syncer{
+			PriceService:  mockPriceService,
+			PrimaryClient: mockPrimaryClient,
+		}
+
+		t.Run(test.name, func(t *testing.T) {
+			synced, err := syncer.Sync(context.Background(), time.Date(2023, 6, 1, 0, 0, 0, 0, time.Local))
+			if test.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !test.expectError && err != nil {
+				t.Errorf("Expected no error but got %v", err)
+			}
+			if synced != test.expectSynced {
+				t.Errorf("Expected expectSynced to be %v but got %v", test.expectSynced, synced)
+			}
+			if test.savePricesCount.Count != 0 {
+				t.Errorf("Expected savePricesCount to be 0 but got %v", test.savePricesCount.Count)
+			}
+		})
+	}
+}
+
+func TestSyncWithComponentsClient(t *testing.T) {
+	hour := time.Date(2021, 6, 1, 0, 0, 0, 0, time.Local)
+	components := &price.Components{Energy: 0.8, TollsAndCharges: 0.2}
+
+	tests := []struct {
+		name                    string
+		componentsGetPricesResp *[][]price.Price
+		componentsGetPricesErr  *[]error
+	}{
+		{
+			name:                    "Components client successful",
+			componentsGetPricesResp: &[][]price.Price{{{DateTime: hour, Price: 1.0, Components: components}}},
+			componentsGetPricesErr:  &[]error{nil},
+		},
+		{
+			name:                    "Components client fails",
+			componentsGetPricesResp: &[][]price.Price{},
+			componentsGetPricesErr:  &[]error{fmt.Errorf("error")},
+		},
+	}
+	for _, test := range tests {
+		mockPriceService := &price.MockPriceService{
+			MockGetLatestPriceResult:   &[]price.Price{{DateTime: time.Date(2021, 5, 31, 0, 0, 0, 0, time.Local)}},
+			MockGetLatestPriceNoResult: &[]bool{false},
+			MockGetLatestPriceError:    &[]error{nil},
+			MockSavePricesCount:        &price.CallCounter{Count: 1},
+			MockSavePricesError:        &[]error{nil},
+		}
+		mockPrimaryClient := &price.MockPriceClient{
+			MockGetPricesResult: &[][]price.Price{{{DateTime: hour, Price: 1.0}}},
+			MockGetPricesSynced: &[]bool{false, true},
+			MockGetPricesError:  &[]error{nil},
+		}
+		mockComponentsClient := &price.MockPriceClient{
+			MockGetPricesResult: test.componentsGetPricesResp,
+			MockGetPricesSynced: &[]bool{false},
+			MockGetPricesError:  test.componentsGetPricesErr,
+		}
+
+		syncer := Syncer{
+			PriceService:     mockPriceService,
+			PrimaryClient:    mockPrimaryClient,
+			ComponentsClient: mockComponentsClient,
+		}
+
+		t.Run(test.name, func(t *testing.T) {
+			synced, err := syncer.Sync(context.Background(), time.Date(2023, 6, 1, 0, 0, 0, 0, time.Local))
+			if err != nil {
+				t.Errorf("Expected no error but got %v", err)
+			}
+			if !synced {
+				t.Errorf("Expected synced to be true")
+			}
+		})
+	}
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	day := time.Date(2021, 6, 10, 0, 0, 0, 0, date.Location)
+	var history []price.Price
+	for h := 0; h < 24*9; h++ {
+		history = append(history, price.Price{DateTime: day.AddDate(0, 0, -9).Add(time.Duration(h) * time.Hour), Price: 0.1 + 0.01*float64(h%3)})
+	}
+	prices := []price.Price{
+		{DateTime: day, Price: 0.1},
+		{DateTime: day.Add(time.Hour), Price: 0.5},
+		{DateTime: day.Add(2 * time.Hour), Price: -0.01},
+	}
+
+	tests := []struct {
+		name          string
+		detector      *price.AnomalyDetector
+		historyResp   *[][]price.Price
+		historyErr    *[]error
+		expectedTypes [][]price.AnomalyType
+	}{
+		{
+			name:          "No detector",
+			historyResp:   &[][]price.Price{},
+			historyErr:    &[]error{},
+			expectedTypes: [][]price.AnomalyType{nil, nil, nil},
+		},
+		{
+			name:          "Detector",
+			detector:      &price.AnomalyDetector{},
+			historyResp:   &[][]price.Price{history},
+			historyErr:    &[]error{nil},
+			expectedTypes: [][]price.AnomalyType{{price.DayJump}, {price.Spike}, {price.NonPositive, price.Dip}},
+		},
+		{
+			name:          "History fails",
+			detector:      &price.AnomalyDetector{},
+			historyResp:   &[][]price.Price{},
+			historyErr:    &[]error{fmt.Errorf("error")},
+			expectedTypes: [][]price.AnomalyType{nil, nil, nil},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			syncer := Syncer{
+				PriceService: &price.MockPriceService{
+					MockGetPricesResult: test.historyResp,
+					MockGetPricesError:  test.historyErr,
+				},
+				AnomalyDetector: test.detector,
+			}
+
+			result := syncer.detectAnomalies(context.Background(), day, prices)
+
+			for i, p := range result {
+				var types []price.AnomalyType
+				for _, a := range p.Anomalies {
+					types = append(types, a.Type)
+				}
+				if len(types) != len(test.expectedTypes[i]) {
+					t.Fatalf("Expected anomalies %v at %s but got %v", test.expectedTypes[i], p.DateTime, types)
+				}
+				for j := range types {
+					if types[j] != test.expectedTypes[i][j] {
+						t.Errorf("Expected anomalies %v at %s but got %v", test.expectedTypes[i], p.DateTime, types)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMergeComponents(t *testing.T) {
+	hour := time.Date(2021, 6, 1, 0, 0, 0, 0, time.Local)
+	existing := &price.Components{Energy: 0.5}
+	components := &price.Components{Energy: 0.8}
+
+	prices := []price.Price{
+		{DateTime: hour, Price: 1.0},
+		{DateTime: hour.Add(time.Hour), Price: 1.0, Components: existing},
+		{DateTime: hour.Add(2 * time.Hour), Price: 1.0},
+	}
+	withComponents := []price.Price{
+		{DateTime: hour, Components: components},
+		{DateTime: hour.Add(time.Hour), Components: components},
+	}
+
+	merged := mergeComponents(prices, withComponents)
+
+	if merged[0].Components != components {
+		t.Errorf("Expected the components to be added")
+	}
+	if merged[1].Components != existing {
+		t.Errorf("Expected the existing components to be kept")
+	}
+	if merged[2].Components != nil {
+		t.Errorf("Expected no components for an hour without a breakdown")
+	}
+}
+
+// recordingClient returns a price for each day it is asked for until the last day, recording the days.
+type recordingClient struct {
+	last time.Time
+	days []time.Time
+}
+
+func (c *recordingClient) GetPrices(t time.Time) ([]price.Price, bool, error) {
+	if t.After(c.last) {
+		return nil, true, nil
+	}
+	c.days = append(c.days, t)
+	return []price.Price{{DateTime: t, Price: 1.0}}, false, nil
+}
+
+func TestSyncMarketDays(t *testing.T) {
+	dublin, err := time.LoadLocation("Europe/Dublin")
+	if err != nil {
+		t.Fatalf("Failed to load the time zone: %s", err)
+	}
+
+	// The last hour of the 1st of December in Dublin is already the 2nd in Madrid
+	latest := time.Date(2023, 12, 1, 23, 0, 0, 0, dublin)
+	mockPriceService := &price.MockPriceService{
+		MockGetLatestPriceResult:   &[]price.Price{{DateTime: latest}},
+		MockGetLatestPriceNoResult: &[]bool{false},
+		MockGetLatestPriceError:    &[]error{nil},
+		MockSavePricesCount:        &price.CallCounter{Count: 2},
+		MockSavePricesError:        &[]error{nil, nil},
+	}
+	client := &recordingClient{last: time.Date(2023, 12, 3, 0, 0, 0, 0, dublin)}
+	syncer := Syncer{PriceService: mockPriceService, PrimaryClient: client, Location: dublin}
+
+	synced, err := syncer.Sync(context.Background(), time.Date(2023, 12, 3, 0, 0, 0, 0, dublin))
+	if err != nil || !synced {
+		t.Fatalf("Expected to sync but got %t, %v", synced, err)
+	}
+	expected := []time.Time{time.Date(2023, 12, 2, 0, 0, 0, 0, dublin), time.Date(2023, 12, 3, 0, 0, 0, 0, dublin)}
+	if len(client.days) != len(expected) {
+		t.Fatalf("Expected %d days to be synced but got %v", len(expected), client.days)
+	}
+	for i, day := range expected {
+		if !client.days[i].Equal(day) {
+			t.Errorf("Expected day %d to start at %s but got %s", i, day, client.days[i])
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package tariff
+
+import (
+	"electricity-prices/pkg/api"
+	"electricity-prices/pkg/auth"
+	"electricity-prices/pkg/bill"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	TariffService Service
+}
+
+// Compare @Summary Compare PVPC with fixed-price offers
+// @Description Calculates the cost of a consumption profile under PVPC and each of the offers over a historical range, using the stored prices. The profile is either an estimated profile spread from an annual consumption over a typical residential shape (not the REE initial profiles) or, without one, the consumption uploaded by the user identified by the bearer token. Returns the options ranked from cheapest to most expensive with a month-by-month breakdown. Dates should be given in the form yyyy-MM-dd and are inclusive.
+// @Tags Tariff
+// @ID compare-tariffs
+// @Accept  json
+// @Produce  json
+// @Param request body tariff.ComparisonRequest true "Range, consumption profile and offers to compare"
+// @Success 200 {object} tariff.Comparison
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 401 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Security BearerAuth
+// @Router /tariff/compare [post]
+func (h *Handler) Compare(c *gin.Context) {
+	var req ComparisonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: "Failed to parse request body."})
+		return
+	}
+	req.UserID = auth.UserID(c)
+
+	// Get the context from the request
+	ctx := c.Request.Context()
+
+	comparison, err := h.TariffService.Compare(ctx, req)
+	if errors.Is(err, ErrInvalidRequest) || errors.Is(err, bill.ErrInvalidRequest) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, comparison)
+}
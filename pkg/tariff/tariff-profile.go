@@ -0,0 +1,64 @@
+package tariff
+
+import (
+	"electricity-prices/pkg/bill"
+	"electricity-prices/pkg/date"
+	"time"
+)
+
+// workingDayShape and nonWorkingDayShape are illustrative residential hourly load shapes (local hour 0 to 23),
+// with the usual night trough and evening peak. They are not REE's published initial consumption profiles,
+// which change every year, and are normalised per day.
+var workingDayShape = [24]float64{
+	0.031, 0.025, 0.022, 0.021, 0.021, 0.022, 0.026, 0.034, 0.040, 0.041, 0.041, 0.042,
+	0.043, 0.045, 0.046, 0.043, 0.041, 0.042, 0.047, 0.053, 0.058, 0.061, 0.055, 0.041,
+}
+
+var nonWorkingDayShape = [24]float64{
+	0.034, 0.028, 0.024, 0.022, 0.021, 0.021, 0.022, 0.025, 0.031, 0.038, 0.043, 0.046,
+	0.048, 0.051, 0.050, 0.045, 0.041, 0.041, 0.044, 0.049, 0.054, 0.057, 0.053, 0.042,
+}
+
+// monthlyShare is an illustrative share of the annual consumption used in each month, January to December,
+// higher in winter than in summer.
+var monthlyShare = [12]float64{
+	0.101, 0.089, 0.084, 0.075, 0.071, 0.074, 0.085, 0.085, 0.075, 0.074, 0.083, 0.104,
+}
+
+// CalculateEstimatedProfile
+// Build an estimated hourly consumption profile for the days between start and end (inclusive) from an annual consumption.
+// The annual consumption is split across months, then evenly across the days of each month and finally across
+// the hours of each day using the working or non-working day shape.
+func CalculateEstimatedProfile(start time.Time, end time.Time, annualKwh float64) []bill.Consumption {
+	var totalShare float64
+	for _, share := range monthlyShare {
+		totalShare += share
+	}
+
+	var profile []bill.Consumption
+	lastDay := date.StartOfDay(end)
+	for d := date.StartOfDay(start); !d.After(lastDay); d = d.AddDate(0, 0, 1) {
+		daysInMonth := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, date.Location).Day()
+		dayKwh := annualKwh * monthlyShare[d.Month()-1] / totalShare / float64(daysInMonth)
+
+		shape := workingDayShape
+		if !date.IsWorkingDay(d) {
+			shape = nonWorkingDayShape
+		}
+
+		// Weight each hour of the local day, there may be 23 or 25 when the clocks change
+		hours := make([]time.Time, date.HoursInDay(d))
+		var daySum float64
+		for i := range hours {
+			hours[i], _ = date.ParseHourIndex(d, i+1)
+			daySum += shape[hours[i].In(date.Location).Hour()]
+		}
+		for _, h := range hours {
+			profile = append(profile, bill.Consumption{
+				DateTime: h,
+				Kwh:      dayKwh * shape[h.In(date.Location).Hour()] / daySum,
+			})
+		}
+	}
+	return profile
+}
@@ -0,0 +1,59 @@
+package tariff
+
+import (
+	"electricity-prices/pkg/date"
+	"testing"
+	"time"
+)
+
+func TestCalculateEstimatedProfile(t *testing.T) {
+	testCases := []struct {
+		name          string
+		start         time.Time
+		end           time.Time
+		annualKwh     float64
+		expectedHours int
+		expectedKwh   float64
+	}{
+		{
+			name:          "Full year",
+			start:         time.Date(2023, 1, 1, 0, 0, 0, 0, date.Location),
+			end:           time.Date(2023, 12, 31, 0, 0, 0, 0, date.Location),
+			annualKwh:     3000,
+			expectedHours: 8760,
+			expectedKwh:   3000,
+		},
+		{
+			name:          "Day the clocks go back",
+			start:         time.Date(2023, 10, 29, 0, 0, 0, 0, date.Location),
+			end:           time.Date(2023, 10, 29, 0, 0, 0, 0, date.Location),
+			annualKwh:     3100,
+			expectedHours: 25,
+			expectedKwh:   3100 * 0.074 / 31,
+		},
+		{
+			name:          "Day the clocks go forward",
+			start:         time.Date(2023, 3, 26, 0, 0, 0, 0, date.Location),
+			end:           time.Date(2023, 3, 26, 0, 0, 0, 0, date.Location),
+			annualKwh:     3100,
+			expectedHours: 23,
+			expectedKwh:   3100 * 0.084 / 31,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := CalculateEstimatedProfile(tc.start, tc.end, tc.annualKwh)
+			if len(profile) != tc.expectedHours {
+				t.Errorf("Expected %d hours but got %d", tc.expectedHours, len(profile))
+			}
+			var total float64
+			for _, c := range profile {
+				total += c.Kwh
+			}
+			if !floatEquals(total, tc.expectedKwh) {
+				t.Errorf("Expected %f kWh but got %f", tc.expectedKwh, total)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package tariff
+
+import (
+	"context"
+	"electricity-prices/pkg/bill"
+	"electricity-prices/pkg/consumption"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"fmt"
+	"time"
+)
+
+type Service interface {
+	Compare(ctx context.Context, req ComparisonRequest) (Comparison, error)
+}
+
+type Receiver struct {
+	PriceService       price.Service
+	ConsumptionService consumption.Service
+	Rates              bill.RateSchedule
+}
+
+// Compare calculates the cost of the consumption profile under PVPC and each of the offers
+// over the requested range and returns the options ranked from cheapest to most expensive.
+func (r *Receiver) Compare(ctx context.Context, req ComparisonRequest) (Comparison, error) {
+	start, err := date.ParseDate(req.Start)
+	if err != nil {
+		return Comparison{}, fmt.Errorf("%w: failed to parse start date", ErrInvalidRequest)
+	}
+	end, err := date.ParseDate(req.End)
+	if err != nil {
+		return Comparison{}, fmt.Errorf("%w: failed to parse end date", ErrInvalidRequest)
+	}
+	if end.Before(start) {
+		return Comparison{}, fmt.Errorf("%w: end date is before start date", ErrInvalidRequest)
+	}
+	if len(req.Offers) == 0 {
+		return Comparison{}, fmt.Errorf("%w: at least one offer is required", ErrInvalidRequest)
+	}
+	for _, offer := range req.Offers {
+		if err := ValidateOffer(offer); err != nil {
+			return Comparison{}, err
+		}
+	}
+	endOfDay := end.AddDate(0, 0, 1).Add(-time.Second)
+
+	// Get the consumption profile
+	profile, source, err := r.getProfile(ctx, req, start, end, endOfDay)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	// Get the PVPC prices for the whole range
+	prices, err := r.PriceService.GetPrices(ctx, start, endOfDay)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	months := splitByMonth(start, end, profile)
+	billReq := bill.Request{PowerP1: req.PowerP1, PowerP2: req.PowerP2}
+
+	pvpc, err := CalculateOption(string(Pvpc), Pvpc, months, billReq, prices, r.Rates)
+	if err != nil {
+		return Comparison{}, err
+	}
+	options := []Option{pvpc}
+
+	for _, offer := range req.Offers {
+		schedule := r.Rates
+		if offer.PowerP1 > 0 || offer.PowerP2 > 0 {
+			schedule = r.Rates.WithPower(offer.PowerP1, offer.PowerP2)
+		}
+		option, err := CalculateOption(offer.Name, offer.Type, months, billReq, CalculateOfferPrices(offer, profile), schedule)
+		if err != nil {
+			return Comparison{}, err
+		}
+		options = append(options, option)
+	}
+
+	var kwh float64
+	for _, c := range profile {
+		kwh += c.Kwh
+	}
+
+	return Comparison{
+		Start:          date.ParseToLocalDay(start),
+		End:            date.ParseToLocalDay(end),
+		Profile:        source,
+		ConsumptionKwh: kwh,
+		Options:        RankOptions(options),
+	}, nil
+}
+
+// getProfile
+// Build the estimated profile if an annual consumption is provided, otherwise get the user's uploaded consumption.
+func (r *Receiver) getProfile(ctx context.Context, req ComparisonRequest, start time.Time, end time.Time, endOfDay time.Time) ([]bill.Consumption, ProfileSource, error) {
+	if req.AnnualKwh > 0 {
+		return CalculateEstimatedProfile(start, end, req.AnnualKwh), EstimatedProfile, nil
+	}
+	if req.UserID == "" {
+		return nil, "", fmt.Errorf("%w: either an annual consumption or a bearer token for a user with uploaded consumption is required", ErrInvalidRequest)
+	}
+
+	readings, err := r.ConsumptionService.GetConsumption(ctx, req.UserID, start, endOfDay)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(readings) == 0 {
+		return nil, "", fmt.Errorf("%w: no consumption found for user %s", ErrInvalidRequest, req.UserID)
+	}
+	profile := make([]bill.Consumption, len(readings))
+	for i, c := range readings {
+		profile[i] = bill.Consumption{DateTime: c.DateTime, Kwh: c.Kwh}
+	}
+	return profile, UserProfile, nil
+}
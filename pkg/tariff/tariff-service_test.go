@@ -0,0 +1,167 @@
+package tariff
+
+import (
+	"context"
+	"electricity-prices/pkg/bill"
+	"electricity-prices/pkg/consumption"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	ctx := context.Background()
+	rates, err := bill.LoadRates("../bill/testdata/rates.toml")
+	if err != nil {
+		t.Fatalf("Error loading rates: %s", err)
+	}
+
+	// Two days of prices, cheap at night and expensive during the day
+	var prices []price.Price
+	var readings []consumption.Consumption
+	for d := 0; d < 2; d++ {
+		for h := 0; h < 24; h++ {
+			dt := time.Date(2024, 1, 2+d, h, 0, 0, 0, date.Location)
+			p := 0.2
+			if h < 8 {
+				p = 0.05
+			}
+			prices = append(prices, price.Price{DateTime: dt, Price: p})
+			readings = append(readings, consumption.Consumption{UserID: "user-1", DateTime: dt, Kwh: 0.5})
+		}
+	}
+
+	offers := []Offer{
+		{Name: "Fixed", Type: Fixed, Price: 0.3},
+		{Name: "Night", Type: TwoPeriod, PeakPrice: 0.18, OffPeakPrice: 0.04},
+	}
+
+	tests := []struct {
+		name               string
+		req                ComparisonRequest
+		mockPrices         *[][]price.Price
+		mockPricesErr      *[]error
+		mockConsumption    *[][]consumption.Consumption
+		mockConsumptionErr *[]error
+		expectedProfile    ProfileSource
+		expectedOrder      []string
+		expectingError     bool
+		expectInvalid      bool
+	}{
+		{
+			name:               "user consumption",
+			req:                ComparisonRequest{Start: "2024-01-02", End: "2024-01-03", UserID: "user-1", PowerP1: 3.3, PowerP2: 3.3, Offers: offers},
+			mockPrices:         &[][]price.Price{prices},
+			mockPricesErr:      &[]error{},
+			mockConsumption:    &[][]consumption.Consumption{readings},
+			mockConsumptionErr: &[]error{},
+			expectedProfile:    UserProfile,
+			expectedOrder:      []string{"Night", "PVPC", "Fixed"},
+		},
+		{
+			name:               "estimated profile",
+			req:                ComparisonRequest{Start: "2024-01-02", End: "2024-01-03", AnnualKwh: 3000, PowerP1: 3.3, PowerP2: 3.3, Offers: offers},
+			mockPrices:         &[][]price.Price{prices},
+			mockPricesErr:      &[]error{},
+			mockConsumption:    &[][]consumption.Consumption{},
+			mockConsumptionErr: &[]error{},
+			expectedProfile:    EstimatedProfile,
+			expectedOrder:      []string{"Night", "PVPC", "Fixed"},
+		},
+		{
+			name:               "annual consumption over the user consumption",
+			req:                ComparisonRequest{Start: "2024-01-02", End: "2024-01-03", UserID: "user-1", AnnualKwh: 3000, PowerP1: 3.3, PowerP2: 3.3, Offers: offers},
+			mockPrices:         &[][]price.Price{prices},
+			mockPricesErr:      &[]error{},
+			mockConsumption:    &[][]consumption.Consumption{},
+			mockConsumptionErr: &[]error{},
+			expectedProfile:    EstimatedProfile,
+			expectedOrder:      []string{"Night", "PVPC", "Fixed"},
+		},
+		{
+			name:               "no profile",
+			req:                ComparisonRequest{Start: "2024-01-02", End: "2024-01-03", Offers: offers},
+			mockPrices:         &[][]price.Price{prices},
+			mockPricesErr:      &[]error{},
+			mockConsumption:    &[][]consumption.Consumption{},
+			mockConsumptionErr: &[]error{},
+			expectingError:     true,
+			expectInvalid:      true,
+		},
+		{
+			name:               "no consumption uploaded",
+			req:                ComparisonRequest{Start: "2024-01-02", End: "2024-01-03", UserID: "user-2", Offers: offers},
+			mockPrices:         &[][]price.Price{prices},
+			mockPricesErr:      &[]error{},
+			mockConsumption:    &[][]consumption.Consumption{{}},
+			mockConsumptionErr: &[]error{},
+			expectingError:     true,
+			expectInvalid:      true,
+		},
+		{
+			name:               "no offers",
+			req:                ComparisonRequest{Start: "2024-01-02", End: "2024-01-03", AnnualKwh: 3000},
+			mockPrices:         &[][]price.Price{prices},
+			mockPricesErr:      &[]error{},
+			mockConsumption:    &[][]consumption.Consumption{},
+			mockConsumptionErr: &[]error{},
+			expectingError:     true,
+			expectInvalid:      true,
+		},
+		{
+			name:               "invalid dates",
+			req:                ComparisonRequest{Start: "2024-01-03", End: "2024-01-02", AnnualKwh: 3000, Offers: offers},
+			mockPrices:         &[][]price.Price{prices},
+			mockPricesErr:      &[]error{},
+			mockConsumption:    &[][]consumption.Consumption{},
+			mockConsumptionErr: &[]error{},
+			expectingError:     true,
+			expectInvalid:      true,
+		},
+		{
+			name:               "failure getting prices",
+			req:                ComparisonRequest{Start: "2024-01-02", End: "2024-01-03", AnnualKwh: 3000, Offers: offers},
+			mockPrices:         &[][]price.Price{},
+			mockPricesErr:      &[]error{errors.New("failed")},
+			mockConsumption:    &[][]consumption.Consumption{},
+			mockConsumptionErr: &[]error{},
+			expectingError:     true,
+		},
+		{
+			name:               "missing prices",
+			req:                ComparisonRequest{Start: "2024-01-02", End: "2024-01-03", AnnualKwh: 3000, Offers: offers},
+			mockPrices:         &[][]price.Price{prices[:24]},
+			mockPricesErr:      &[]error{},
+			mockConsumption:    &[][]consumption.Consumption{},
+			mockConsumptionErr: &[]error{},
+			expectingError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priceService := &price.MockPriceService{MockGetPricesResult: tt.mockPrices, MockGetPricesError: tt.mockPricesErr}
+			consumptionService := &consumption.MockConsumptionService{MockGetConsumptionResult: tt.mockConsumption, MockGetConsumptionError: tt.mockConsumptionErr}
+			service := &Receiver{PriceService: priceService, ConsumptionService: consumptionService, Rates: rates}
+
+			result, err := service.Compare(ctx, tt.req)
+
+			if tt.expectingError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectInvalid, errors.Is(err, ErrInvalidRequest))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedProfile, result.Profile)
+			assert.Len(t, result.Options, len(tt.expectedOrder))
+			for i, o := range result.Options {
+				assert.Equal(t, tt.expectedOrder[i], o.Name)
+				assert.Len(t, o.Months, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package tariff
+
+import (
+	"electricity-prices/pkg/bill"
+	"electricity-prices/pkg/date"
+	"electricity-prices/pkg/price"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+var ErrInvalidRequest = errors.New("invalid comparison request")
+
+type month struct {
+	start       time.Time
+	end         time.Time
+	consumption []bill.Consumption
+}
+
+// IsOffPeak
+// Returns true if the hour is in the 2.0TD off-peak (valle) period.
+// That is midnight to 8 AM on working days and all day at weekends and on national holidays.
+func IsOffPeak(t time.Time) bool {
+	if !date.IsWorkingDay(t) {
+		return true
+	}
+	return t.In(date.Location).Hour() < 8
+}
+
+// ValidateOffer
+// Check that the offer has a name, a known type and non-negative prices.
+func ValidateOffer(offer Offer) error {
+	if offer.Name == "" {
+		return fmt.Errorf("%w: offers must have a name", ErrInvalidRequest)
+	}
+	if offer.Type != Fixed && offer.Type != TwoPeriod {
+		return fmt.Errorf("%w: offer %s has an unknown type %q", ErrInvalidRequest, offer.Name, offer.Type)
+	}
+	if offer.Price < 0 || offer.PeakPrice < 0 || offer.OffPeakPrice < 0 || offer.PowerP1 < 0 || offer.PowerP2 < 0 {
+		return fmt.Errorf("%w: offer %s has a negative price", ErrInvalidRequest, offer.Name)
+	}
+	return nil
+}
+
+// CalculateOfferPrices
+// Build the hourly prices an offer would charge for each hour of consumption.
+func CalculateOfferPrices(offer Offer, consumption []bill.Consumption) []price.Price {
+	prices := make([]price.Price, len(consumption))
+	for i, c := range consumption {
+		p := offer.Price
+		if offer.Type == TwoPeriod {
+			p = offer.PeakPrice
+			if IsOffPeak(c.DateTime) {
+				p = offer.OffPeakPrice
+			}
+		}
+		prices[i] = price.Price{DateTime: c.DateTime, Price: p}
+	}
+	return prices
+}
+
+// CalculateOption
+// Calculate the month-by-month cost of consumption at the given prices and rates.
+// Each month is billed separately using the bill calculator so taxes are applied as they would be on a real bill.
+func CalculateOption(name string, offerType OfferType, months []month, req bill.Request, prices []price.Price, schedule bill.RateSchedule) (Option, error) {
+	option := Option{Name: name, Type: offerType, Months: make([]MonthlyCost, 0, len(months))}
+	for _, m := range months {
+		req.Consumption = m.consumption
+		b, err := bill.CalculateBill(m.start, m.end, req, prices, nil, schedule)
+		if err != nil {
+			return Option{}, err
+		}
+
+		var energy float64
+		for _, item := range b.Items {
+			if item.Concept == bill.Energy {
+				energy += item.Amount
+			}
+		}
+
+		option.Months = append(option.Months, MonthlyCost{
+			Month:          m.start.Format("2006-01"),
+			ConsumptionKwh: b.ConsumptionKwh,
+			Energy:         roundToCents(energy),
+			Total:          b.Total,
+		})
+		option.Total += b.Total
+	}
+	option.Total = roundToCents(option.Total)
+	return option, nil
+}
+
+// RankOptions
+// Sort the options from cheapest to most expensive and set their rank and difference from the cheapest.
+func RankOptions(options []Option) []Option {
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].Total < options[j].Total
+	})
+	for i := range options {
+		options[i].Rank = i + 1
+		options[i].DifferenceFromCheapest = roundToCents(options[i].Total - options[0].Total)
+	}
+	return options
+}
+
+// splitByMonth
+// Split the consumption between start and end (inclusive) into calendar months.
+func splitByMonth(start time.Time, end time.Time, consumption []bill.Consumption) []month {
+	var months []month
+	lastDay := date.StartOfDay(end)
+	for s := date.StartOfDay(start); !s.After(lastDay); {
+		e := time.Date(s.Year(), s.Month()+1, 0, 0, 0, 0, 0, date.Location)
+		if e.After(lastDay) {
+			e = lastDay
+		}
+		months = append(months, month{start: s, end: e})
+		s = e.AddDate(0, 0, 1)
+	}
+
+	for _, c := range consumption {
+		day := date.StartOfDay(c.DateTime)
+		for i := range months {
+			if !day.Before(months[i].start) && !day.After(months[i].end) {
+				months[i].consumption = append(months[i].consumption, c)
+				break
+			}
+		}
+	}
+	return months
+}
+
+// roundToCents
+// Round an amount in euro to two decimal places.
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
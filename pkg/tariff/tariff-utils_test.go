@@ -0,0 +1,155 @@
+package tariff
+
+import (
+	"electricity-prices/pkg/bill"
+	"electricity-prices/pkg/date"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+const epsilon = 1e-4 // Tolerance level
+
+func floatEquals(a, b float64) bool {
+	return math.Abs(a-b) <= epsilon
+}
+
+func TestIsOffPeak(t *testing.T) {
+	testCases := []struct {
+		name     string
+		date     time.Time
+		expected bool
+	}{
+		{"Weekday night", time.Date(2024, 1, 2, 3, 0, 0, 0, date.Location), true},
+		{"Weekday last off-peak hour", time.Date(2024, 1, 2, 7, 0, 0, 0, date.Location), true},
+		{"Weekday morning", time.Date(2024, 1, 2, 8, 0, 0, 0, date.Location), false},
+		{"Weekday evening", time.Date(2024, 1, 2, 20, 0, 0, 0, date.Location), false},
+		{"Saturday evening", time.Date(2024, 1, 6, 20, 0, 0, 0, date.Location), true},
+		{"Holiday evening", time.Date(2024, 12, 25, 20, 0, 0, 0, date.Location), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := IsOffPeak(tc.date)
+			if result != tc.expected {
+				t.Errorf("Expected %v but was %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestValidateOffer(t *testing.T) {
+	testCases := []struct {
+		name      string
+		offer     Offer
+		expectErr bool
+	}{
+		{"Fixed", Offer{Name: "Fixed", Type: Fixed, Price: 0.15}, false},
+		{"Two period", Offer{Name: "Night", Type: TwoPeriod, PeakPrice: 0.2, OffPeakPrice: 0.1}, false},
+		{"Missing name", Offer{Type: Fixed, Price: 0.15}, true},
+		{"Unknown type", Offer{Name: "Other", Type: "INDEXED", Price: 0.15}, true},
+		{"PVPC is not an offer", Offer{Name: "PVPC", Type: Pvpc}, true},
+		{"Negative price", Offer{Name: "Fixed", Type: Fixed, Price: -0.15}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateOffer(tc.offer)
+			if tc.expectErr && !errors.Is(err, ErrInvalidRequest) {
+				t.Errorf("Expected invalid request error but got %v", err)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("Expected no error but got %s", err)
+			}
+		})
+	}
+}
+
+func TestCalculateOfferPrices(t *testing.T) {
+	consumption := []bill.Consumption{
+		{DateTime: time.Date(2024, 1, 2, 3, 0, 0, 0, date.Location), Kwh: 1},
+		{DateTime: time.Date(2024, 1, 2, 12, 0, 0, 0, date.Location), Kwh: 1},
+	}
+
+	testCases := []struct {
+		name     string
+		offer    Offer
+		expected []float64
+	}{
+		{"Fixed", Offer{Name: "Fixed", Type: Fixed, Price: 0.15}, []float64{0.15, 0.15}},
+		{"Two period", Offer{Name: "Night", Type: TwoPeriod, PeakPrice: 0.2, OffPeakPrice: 0.1}, []float64{0.1, 0.2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prices := CalculateOfferPrices(tc.offer, consumption)
+			if len(prices) != len(tc.expected) {
+				t.Fatalf("Expected %d prices but got %d", len(tc.expected), len(prices))
+			}
+			for i, p := range prices {
+				if !floatEquals(p.Price, tc.expected[i]) {
+					t.Errorf("Expected %f, but got %f", tc.expected[i], p.Price)
+				}
+				if !p.DateTime.Equal(consumption[i].DateTime) {
+					t.Errorf("Expected %v, but got %v", consumption[i].DateTime, p.DateTime)
+				}
+			}
+		})
+	}
+}
+
+func TestRankOptions(t *testing.T) {
+	options := RankOptions([]Option{
+		{Name: "PVPC", Total: 20},
+		{Name: "Fixed", Total: 15.5},
+		{Name: "Night", Total: 30},
+	})
+
+	expectedNames := []string{"Fixed", "PVPC", "Night"}
+	expectedDiffs := []float64{0, 4.5, 14.5}
+	for i, o := range options {
+		if o.Name != expectedNames[i] {
+			t.Errorf("Expected %s at position %d but got %s", expectedNames[i], i, o.Name)
+		}
+		if o.Rank != i+1 {
+			t.Errorf("Expected rank %d but got %d", i+1, o.Rank)
+		}
+		if !floatEquals(o.DifferenceFromCheapest, expectedDiffs[i]) {
+			t.Errorf("Expected difference %f but got %f", expectedDiffs[i], o.DifferenceFromCheapest)
+		}
+	}
+}
+
+func TestSplitByMonth(t *testing.T) {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, date.Location)
+	end := time.Date(2024, 3, 10, 0, 0, 0, 0, date.Location)
+	consumption := []bill.Consumption{
+		{DateTime: time.Date(2024, 1, 31, 23, 0, 0, 0, date.Location), Kwh: 1},
+		{DateTime: time.Date(2024, 2, 1, 0, 0, 0, 0, date.Location), Kwh: 1},
+		{DateTime: time.Date(2024, 3, 10, 23, 0, 0, 0, date.Location), Kwh: 1},
+	}
+
+	months := splitByMonth(start, end, consumption)
+
+	if len(months) != 3 {
+		t.Fatalf("Expected 3 months but got %d", len(months))
+	}
+	expected := []struct {
+		start    string
+		end      string
+		readings int
+	}{
+		{"2024-01-15", "2024-01-31", 1},
+		{"2024-02-01", "2024-02-29", 1},
+		{"2024-03-01", "2024-03-10", 1},
+	}
+	for i, m := range months {
+		if date.ParseToLocalDay(m.start) != expected[i].start || date.ParseToLocalDay(m.end) != expected[i].end {
+			t.Errorf("Expected %s to %s but got %s to %s", expected[i].start, expected[i].end, date.ParseToLocalDay(m.start), date.ParseToLocalDay(m.end))
+		}
+		if len(m.consumption) != expected[i].readings {
+			t.Errorf("Expected %d readings in month %d but got %d", expected[i].readings, i, len(m.consumption))
+		}
+	}
+}
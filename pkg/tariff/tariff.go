@@ -0,0 +1,66 @@
+package tariff
+
+type OfferType string
+
+const (
+	Pvpc      OfferType = "PVPC"
+	Fixed     OfferType = "FIXED"
+	TwoPeriod OfferType = "TWO_PERIOD"
+)
+
+type ProfileSource string
+
+const (
+	UserProfile      ProfileSource = "USER"
+	EstimatedProfile ProfileSource = "ESTIMATED"
+)
+
+// Offer is a retail offer to compare against PVPC.
+// Energy prices are in €/kWh and power prices in €/kW per year.
+// If the power prices are not set the regulated power term is used.
+type Offer struct {
+	Name         string    `json:"name"`
+	Type         OfferType `json:"type"`
+	Price        float64   `json:"price"`
+	PeakPrice    float64   `json:"peakPrice"`
+	OffPeakPrice float64   `json:"offPeakPrice"`
+	PowerP1      float64   `json:"powerP1"`
+	PowerP2      float64   `json:"powerP2"`
+}
+
+// ComparisonRequest describes the consumption profile and offers to compare.
+// Either an AnnualKwh for the estimated profile or a UserID with uploaded consumption must be provided.
+// The UserID is never read from the body, it is the user verified from the bearer token.
+type ComparisonRequest struct {
+	Start     string  `json:"start"`
+	End       string  `json:"end"`
+	UserID    string  `json:"-"`
+	AnnualKwh float64 `json:"annualKwh"`
+	PowerP1   float64 `json:"powerP1"`
+	PowerP2   float64 `json:"powerP2"`
+	Offers    []Offer `json:"offers"`
+}
+
+type MonthlyCost struct {
+	Month          string  `json:"month"`
+	ConsumptionKwh float64 `json:"consumptionKwh"`
+	Energy         float64 `json:"energy"`
+	Total          float64 `json:"total"`
+}
+
+type Option struct {
+	Rank                   int           `json:"rank"`
+	Name                   string        `json:"name"`
+	Type                   OfferType     `json:"type"`
+	Total                  float64       `json:"total"`
+	DifferenceFromCheapest float64       `json:"differenceFromCheapest"`
+	Months                 []MonthlyCost `json:"months"`
+}
+
+type Comparison struct {
+	Start          string        `json:"start"`
+	End            string        `json:"end"`
+	Profile        ProfileSource `json:"profile"`
+	ConsumptionKwh float64       `json:"consumptionKwh"`
+	Options        []Option      `json:"options"`
+}
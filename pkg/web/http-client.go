@@ -4,4 +4,5 @@ import "net/http"
 
 type HTTPClient interface {
 	Get(url string) (resp *http.Response, err error)
+	Do(req *http.Request) (resp *http.Response, err error)
 }
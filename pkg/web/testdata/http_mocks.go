@@ -15,6 +15,10 @@ func (m *MockHTTPClient) Get(url string) (*http.Response, error) {
 	return m.MockResp, m.MockErr
 }
 
+func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return m.MockResp, m.MockErr
+}
+
 type MockReadCloser struct {
 	io.Reader
 }